@@ -0,0 +1,119 @@
+package meilisearch
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// CallOption configures a single SDK call, see WithCallOptions.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	apiKey         string
+	headers        http.Header
+	timeout        time.Duration
+	acceptEncoding *ContentEncoding
+	metadata       map[string]interface{}
+	hedging        *HedgingConfig
+	disableHedging bool
+}
+
+type callOptionsKey struct{}
+
+// WithCallOptions returns a copy of ctx carrying the given options, they apply to every
+// *WithContext call made with it. Options already carried by ctx are kept unless overridden,
+// which lets a service share one ServiceManager between tenants:
+//
+//	ctx = meilisearch.WithCallOptions(ctx, meilisearch.CallWithAPIKey(tenantToken))
+//	resp, err := client.Index("movies").SearchWithContext(ctx, "batman", &meilisearch.SearchRequest{})
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	co := &callOptions{}
+	if parent := callOptionsFromContext(ctx); parent != nil {
+		co = parent.clone()
+	}
+	for _, opt := range opts {
+		opt(co)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, co)
+}
+
+// CallWithAPIKey sends the call with the given bearer token instead of the client API key,
+// for instance a tenant token from GenerateTenantToken.
+func CallWithAPIKey(apiKey string) CallOption {
+	return func(co *callOptions) {
+		co.apiKey = apiKey
+	}
+}
+
+// CallWithHeader adds a header to the call, it takes precedence over the headers set by the client.
+func CallWithHeader(key, value string) CallOption {
+	return func(co *callOptions) {
+		if co.headers == nil {
+			co.headers = make(http.Header)
+		}
+		co.headers.Add(key, value)
+	}
+}
+
+// CallWithTimeout bounds the whole call, retries included.
+func CallWithTimeout(timeout time.Duration) CallOption {
+	return func(co *callOptions) {
+		co.timeout = timeout
+	}
+}
+
+// CallWithAcceptEncoding overrides the Accept-Encoding of the call, the response is decoded
+// according to the Content-Encoding the server answers with. An empty encoding asks for an
+// uncompressed response.
+func CallWithAcceptEncoding(encoding ContentEncoding) CallOption {
+	return func(co *callOptions) {
+		co.acceptEncoding = &encoding
+	}
+}
+
+// CallWithMetadata attaches a value to the call, it is not sent to Meilisearch but exposed
+// to middlewares through RequestInfo.Metadata.
+func CallWithMetadata(key string, value interface{}) CallOption {
+	return func(co *callOptions) {
+		if co.metadata == nil {
+			co.metadata = make(map[string]interface{})
+		}
+		co.metadata[key] = value
+	}
+}
+
+// CallWithHedging hedges the call as configured by cfg, overriding the configuration of
+// WithHedging if any. Only Search and MultiSearch calls are hedged, see WithHedging.
+func CallWithHedging(cfg HedgingConfig) CallOption {
+	return func(co *callOptions) {
+		co.hedging = &cfg
+		co.disableHedging = false
+	}
+}
+
+// CallWithoutHedging sends the call once even though the client hedges searches with
+// WithHedging.
+func CallWithoutHedging() CallOption {
+	return func(co *callOptions) {
+		co.hedging = nil
+		co.disableHedging = true
+	}
+}
+
+func callOptionsFromContext(ctx context.Context) *callOptions {
+	co, _ := ctx.Value(callOptionsKey{}).(*callOptions)
+	return co
+}
+
+func (co *callOptions) clone() *callOptions {
+	cp := *co
+	cp.headers = co.headers.Clone()
+	if co.metadata != nil {
+		cp.metadata = make(map[string]interface{}, len(co.metadata))
+		for k, v := range co.metadata {
+			cp.metadata[k] = v
+		}
+	}
+	return &cp
+}
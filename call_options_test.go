@@ -0,0 +1,116 @@
+package meilisearch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallOptions_APIKeyAndHeaders(t *testing.T) {
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"hits":[]}`))
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL, WithAPIKey("masterKey"))
+	idx := sv.Index("movies")
+
+	ctx := WithCallOptions(context.Background(), CallWithAPIKey("tenantToken"), CallWithHeader("X-Tenant", "acme"))
+	_, err := idx.SearchWithContext(ctx, "", &SearchRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "Bearer tenantToken", got.Get("Authorization"))
+	require.Equal(t, "acme", got.Get("X-Tenant"))
+
+	// nested options keep the parent ones
+	ctx = WithCallOptions(ctx, CallWithHeader("X-Request-Id", "42"))
+	_, err = idx.SearchWithContext(ctx, "", &SearchRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "Bearer tenantToken", got.Get("Authorization"))
+	require.Equal(t, "acme", got.Get("X-Tenant"))
+	require.Equal(t, "42", got.Get("X-Request-Id"))
+
+	_, err = idx.SearchWithContext(context.Background(), "", &SearchRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "Bearer masterKey", got.Get("Authorization"))
+	require.Empty(t, got.Get("X-Tenant"))
+}
+
+func TestCallOptions_Timeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL)
+	ctx := WithCallOptions(context.Background(), CallWithTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	_, err := sv.VersionWithContext(ctx)
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, TimeoutError, e.ErrCode)
+}
+
+func TestCallOptions_AcceptEncoding(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte(`{"pkgVersion":"1.2.3"}`))
+	require.NoError(t, zw.Close())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") == "gzip" {
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write(gz.Bytes())
+			return
+		}
+		_, _ = w.Write([]byte(`{"pkgVersion":"1.2.3"}`))
+	}))
+	defer ts.Close()
+
+	// the client does not compress, the call asks for gzip
+	sv := New(ts.URL)
+	ctx := WithCallOptions(context.Background(), CallWithAcceptEncoding(GzipEncoding))
+	resp, err := sv.VersionWithContext(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3", resp.PkgVersion)
+
+	// the client compresses, the call asks for a plain response
+	sv = New(ts.URL, WithContentEncoding(GzipEncoding, DefaultCompression))
+	ctx = WithCallOptions(context.Background(), CallWithAcceptEncoding(""))
+	resp, err = sv.VersionWithContext(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3", resp.PkgVersion)
+}
+
+func TestCallOptions_Metadata(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"pkgVersion":"1.2.3"}`))
+	}))
+	defer ts.Close()
+
+	var tenant interface{}
+	sv := New(ts.URL, WithMiddleware(func(next Handler) Handler {
+		return func(ctx context.Context, info *RequestInfo) error {
+			tenant = info.Metadata["tenant"]
+			return next(ctx, info)
+		}
+	}))
+
+	ctx := WithCallOptions(context.Background(), CallWithMetadata("tenant", "acme"))
+	_, err := sv.VersionWithContext(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "acme", tenant)
+}
@@ -0,0 +1,162 @@
+package meilisearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through, failures are counted.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every request fast until the cooldown is over.
+	CircuitOpen
+	// CircuitHalfOpen lets a single trial request through to decide whether to close or reopen.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	defaultFailureThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// CircuitBreakerConfig configures the per-host circuit breaker, see WithCircuitBreaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed attempts opening the breaker, default 5.
+	// Only communication errors, timeouts and 5xx responses are failures, 4xx API errors never are.
+	FailureThreshold int
+	// Cooldown is how long the breaker stays open before letting a trial request through, default 30s.
+	Cooldown time.Duration
+	// OnStateChange is called on every transition with the host of the breaker.
+	// It runs synchronously and must not call the client.
+	OnStateChange func(host string, from, to CircuitState)
+}
+
+// circuitBreaker is a consecutive-failure circuit breaker guarding a single host.
+type circuitBreaker struct {
+	host string
+	cfg  CircuitBreakerConfig
+	now  func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	// trial is true while the half-open trial request is in flight
+	trial bool
+}
+
+func newCircuitBreaker(host string, cfg CircuitBreakerConfig) *circuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaultFailureThreshold
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = defaultBreakerCooldown
+	}
+	return &circuitBreaker{host: host, cfg: cfg, now: time.Now}
+}
+
+// allow reports whether a request may be sent to the host, and whether it is the half-open
+// trial request, whose outcome alone decides to close or reopen the breaker.
+func (cb *circuitBreaker) allow() (ok, trial bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cfg.Cooldown {
+			return false, false
+		}
+		cb.setState(CircuitHalfOpen)
+		cb.trial = true
+		return true, true
+	case CircuitHalfOpen:
+		if cb.trial {
+			return false, false
+		}
+		cb.trial = true
+		return true, true
+	default:
+		return true, false
+	}
+}
+
+// record updates the breaker with the outcome of an attempt allowed by allow, trial being
+// what allow returned. Communication errors, timeouts and 5xx responses are failures, a
+// request canceled by the caller is not the host's fault and leaves the breaker as is. The
+// outcome of a request let through while the breaker was closed only counts while it still is.
+func (cb *circuitBreaker) record(trial bool, resp *http.Response, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if trial {
+		cb.trial = false
+	} else if cb.state != CircuitClosed {
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+	if !failed {
+		cb.failures = 0
+		if cb.state != CircuitClosed {
+			cb.setState(CircuitClosed)
+		}
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitHalfOpen || cb.failures >= cb.cfg.FailureThreshold {
+		cb.openedAt = cb.now()
+		if cb.state != CircuitOpen {
+			cb.setState(CircuitOpen)
+		}
+	}
+}
+
+// setState must be called with mu held.
+func (cb *circuitBreaker) setState(to CircuitState) {
+	from := cb.state
+	cb.state = to
+	if cb.cfg.OnStateChange != nil {
+		cb.cfg.OnStateChange(cb.host, from, to)
+	}
+}
+
+func (cb *circuitBreaker) currentState() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// breakerFor returns the circuit breaker of the host the request is routed to, nil when
+// the client has none.
+func (c *client) breakerFor(req *internalRequest) *circuitBreaker {
+	if c.breakers == nil {
+		return nil
+	}
+	if req.node != nil {
+		return c.breakers[req.node.host]
+	}
+	return c.breakers[c.host]
+}
@@ -0,0 +1,133 @@
+package meilisearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker_States(t *testing.T) {
+	type transition struct{ from, to CircuitState }
+	var transitions []transition
+
+	now := time.Now()
+	cb := newCircuitBreaker("http://ms", CircuitBreakerConfig{
+		FailureThreshold: 2,
+		Cooldown:         time.Minute,
+		OnStateChange: func(host string, from, to CircuitState) {
+			require.Equal(t, "http://ms", host)
+			transitions = append(transitions, transition{from, to})
+		},
+	})
+	cb.now = func() time.Time { return now }
+
+	unavailable := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	notFound := &http.Response{StatusCode: http.StatusNotFound}
+
+	allow := func() bool {
+		ok, trial := cb.allow()
+		require.Equal(t, cb.currentState() == CircuitHalfOpen && ok, trial)
+		return ok
+	}
+
+	// 4xx are not failures and reset the count
+	require.True(t, allow())
+	cb.record(false, unavailable, nil)
+	require.True(t, allow())
+	cb.record(false, notFound, nil)
+	require.True(t, allow())
+	cb.record(false, nil, errors.New("connection refused"))
+	require.Equal(t, CircuitClosed, cb.currentState())
+
+	require.True(t, allow())
+	cb.record(false, unavailable, nil)
+	require.Equal(t, CircuitOpen, cb.currentState())
+	require.False(t, allow())
+
+	// after the cooldown a single trial is let through
+	now = now.Add(time.Minute)
+	require.True(t, allow())
+	require.Equal(t, CircuitHalfOpen, cb.currentState())
+	require.False(t, allow())
+
+	cb.record(true, unavailable, nil)
+	require.Equal(t, CircuitOpen, cb.currentState())
+	require.False(t, allow())
+
+	now = now.Add(time.Minute)
+	require.True(t, allow())
+	// a canceled trial leaves the breaker half-open
+	cb.record(true, nil, context.Canceled)
+	require.Equal(t, CircuitHalfOpen, cb.currentState())
+	require.True(t, allow())
+	cb.record(true, notFound, nil)
+	require.Equal(t, CircuitClosed, cb.currentState())
+
+	require.Equal(t, []transition{
+		{CircuitClosed, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitClosed},
+	}, transitions)
+}
+
+func TestCircuitBreaker_LateResultDoesNotDecide(t *testing.T) {
+	now := time.Now()
+	cb := newCircuitBreaker("http://ms", CircuitBreakerConfig{FailureThreshold: 1, Cooldown: time.Minute})
+	cb.now = func() time.Time { return now }
+	ok := &http.Response{StatusCode: http.StatusOK}
+
+	// a request let through while closed is still in flight when the breaker opens
+	_, lateTrial := cb.allow()
+	_, trial := cb.allow()
+	cb.record(trial, nil, errors.New("connection refused"))
+	require.Equal(t, CircuitOpen, cb.currentState())
+
+	now = now.Add(time.Minute)
+	allowed, trial := cb.allow()
+	require.True(t, allowed)
+	require.True(t, trial)
+
+	cb.record(lateTrial, ok, nil)
+	require.Equal(t, CircuitHalfOpen, cb.currentState(), "the late success does not close the breaker")
+	allowed, _ = cb.allow()
+	require.False(t, allowed, "the trial is still in flight")
+
+	cb.record(trial, nil, errors.New("connection refused"))
+	require.Equal(t, CircuitOpen, cb.currentState())
+}
+
+func TestCircuitBreaker_FailFast(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL, DisableRetries(), WithCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 3,
+		Cooldown:         time.Hour,
+	}))
+
+	for i := 0; i < 3; i++ {
+		_, err := sv.Version()
+		var e *Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, APIErrorWithoutMessage, e.ErrCode)
+	}
+
+	_, err := sv.Version()
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, CircuitBreakerOpen, e.ErrCode)
+	require.Equal(t, "circuit_breaker_open", e.ErrCode.String())
+	require.Equal(t, int32(3), hits.Load())
+}
@@ -7,10 +7,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,6 +20,7 @@ type client struct {
 	client          *http.Client
 	host            string
 	apiKey          string
+	apiKeyProvider  APIKeyProvider
 	encoder         encoder
 	contentEncoding ContentEncoding
 	retryOnStatus   map[int]bool
@@ -25,8 +28,37 @@ type client struct {
 	maxRetries      uint8
 	retryBackoff    func(attempt uint8) time.Duration
 
+	// refresh is the APIKeyProvider refresh in flight, guarded by refreshMu
+	refreshMu sync.Mutex
+	refresh   *keyRefresh
+
+	// compressionThresholds skips the compression of small bodies, nil compresses every body
+	compressionThresholds *CompressionThresholds
+
+	maxElapsedTime     time.Duration
+	respectRetryAfter  bool
+	maxRetryAfter      time.Duration
+	retryNetworkErrors bool
+	onAttempt          func(attempt RetryAttempt)
+
 	jsonMarshal   JSONMarshal
 	jsonUnmarshal JSONUnmarshal
+
+	handler Handler
+	tracer  Tracer
+	nodes   *nodePool
+	// breakers holds a circuit breaker per host, nil when WithCircuitBreaker is not used
+	breakers map[string]*circuitBreaker
+	// limiters holds the limiter of every operation class, nil when WithRateLimits is not used
+	limiters map[OperationClass]*classLimiter
+	hedging  *HedgingConfig
+
+	logger       *slog.Logger
+	logLevels    LogLevels
+	logBodyLimit int
+
+	// requestDump keeps the request behind every *Error, see WithRequestDump
+	requestDump bool
 }
 
 type clientConfig struct {
@@ -35,8 +67,23 @@ type clientConfig struct {
 	retryOnStatus            map[int]bool
 	disableRetry             bool
 	maxRetries               uint8
+	retryPolicy              *RetryPolicy
+	hosts                    []string
+	healthProbeInterval      time.Duration
+	circuitBreaker           *CircuitBreakerConfig
+	rateLimits               *RateLimits
+	hedging                  *HedgingConfig
+	apiKeyProvider           APIKeyProvider
 	jsonMarshal              JSONMarshal
 	jsonUnmarshal            JSONUnmarshal
+	middlewares              []Middleware
+	tracer                   Tracer
+	metrics                  MetricsRecorder
+	logger                   *slog.Logger
+	logLevels                *LogLevels
+	logBodyLimit             int
+	requestDump              bool
+	compressionThresholds    *CompressionThresholds
 }
 
 type internalRequest struct {
@@ -46,24 +93,75 @@ type internalRequest struct {
 	withRequest          interface{}
 	withResponse         interface{}
 	withQueryParams      map[string]string
+	withHeaders          http.Header
 	withResponseEncoding bool
 
 	acceptedStatusCodes []int
 	acceptedContentType string
 
 	functionName string
+
+	// node the request is routed to, nil when the client has a single host
+	node *node
+
+	stats requestStats
+}
+
+// requestStats is filled while a request is executed, it feeds the built-in middlewares.
+type requestStats struct {
+	// statusCode of the last HTTP response received for this request
+	statusCode int
+	// retries done by client.do, the first attempt is not counted
+	retries uint8
+	// requestBytes is the size of the body sent on the wire, after compression
+	requestBytes int64
+	// responseBytes is the size of the body read from the wire, before decompression
+	responseBytes int64
+	// requestEncoding is the Content-Encoding of the request body, empty when it was sent as is
+	requestEncoding ContentEncoding
+	// throttled is set when an attempt was answered with 429 Too Many Requests
+	throttled bool
 }
 
 func newClient(cli *http.Client, host, apiKey string, cfg *clientConfig) *client {
 	c := &client{
-		client:        cli,
-		host:          host,
-		apiKey:        apiKey,
-		disableRetry:  cfg.disableRetry,
-		maxRetries:    cfg.maxRetries,
-		retryOnStatus: cfg.retryOnStatus,
-		jsonMarshal:   cfg.jsonMarshal,
-		jsonUnmarshal: cfg.jsonUnmarshal,
+		client:         cli,
+		host:           host,
+		apiKey:         apiKey,
+		disableRetry:   cfg.disableRetry,
+		maxRetries:     cfg.maxRetries,
+		retryOnStatus:  cfg.retryOnStatus,
+		jsonMarshal:    cfg.jsonMarshal,
+		jsonUnmarshal:  cfg.jsonUnmarshal,
+		tracer:         cfg.tracer,
+		hedging:        cfg.hedging,
+		apiKeyProvider: cfg.apiKeyProvider,
+		logger:         cfg.logger,
+		logLevels:      defaultLogLevels(),
+		logBodyLimit:   cfg.logBodyLimit,
+		requestDump:    cfg.requestDump,
+
+		compressionThresholds: cfg.compressionThresholds,
+	}
+
+	if len(cfg.hosts) > 0 {
+		c.nodes = newNodePool(cli, host, cfg.hosts, cfg.healthProbeInterval)
+	}
+
+	if cfg.circuitBreaker != nil {
+		c.breakers = map[string]*circuitBreaker{host: newCircuitBreaker(host, *cfg.circuitBreaker)}
+		for _, h := range cfg.hosts {
+			if _, ok := c.breakers[h]; !ok {
+				c.breakers[h] = newCircuitBreaker(h, *cfg.circuitBreaker)
+			}
+		}
+	}
+
+	if cfg.logLevels != nil {
+		c.logLevels = *cfg.logLevels
+	}
+	if c.logBodyLimit == 0 {
+		c.logBodyLimit = defaultLogBodyLimit
 	}
 
 	if c.retryOnStatus == nil {
@@ -74,6 +172,20 @@ func newClient(cli *http.Client, host, apiKey string, cfg *clientConfig) *client
 		}
 	}
 
+	if p := cfg.retryPolicy; p != nil {
+		c.maxRetries = p.MaxRetries
+		c.retryOnStatus = make(map[int]bool, len(p.RetryOnStatus))
+		for _, status := range p.RetryOnStatus {
+			c.retryOnStatus[status] = true
+		}
+		c.retryBackoff = p.backoff()
+		c.maxElapsedTime = p.MaxElapsedTime
+		c.respectRetryAfter = p.RespectRetryAfter
+		c.maxRetryAfter = p.maxBackoff()
+		c.retryNetworkErrors = p.RetryNetworkErrors
+		c.onAttempt = p.OnAttempt
+	}
+
 	if !c.disableRetry && c.retryBackoff == nil {
 		c.retryBackoff = func(attempt uint8) time.Duration {
 			return time.Second * time.Duration(attempt)
@@ -85,11 +197,83 @@ func newClient(cli *http.Client, host, apiKey string, cfg *clientConfig) *client
 		c.encoder = newEncoding(cfg.contentEncoding, cfg.encodingCompressionLevel)
 	}
 
+	// built-in middlewares wrap the user ones so they measure the whole call
+	var middlewares []Middleware
+	if c.tracer != nil {
+		middlewares = append(middlewares, tracingMiddleware(c.tracer))
+	}
+	if cfg.metrics != nil {
+		middlewares = append(middlewares, metricsMiddleware(cfg.metrics))
+	}
+	if c.logger != nil {
+		middlewares = append(middlewares, c.loggingMiddleware())
+	}
+	if cfg.rateLimits != nil {
+		c.limiters = newClassLimiters(*cfg.rateLimits)
+		middlewares = append(middlewares, rateLimitMiddleware(c.limiters))
+	}
+	middlewares = append(middlewares, cfg.middlewares...)
+	if len(middlewares) > 0 {
+		c.handler = chainMiddlewares(c.handle, middlewares...)
+	}
+
 	return c
 }
 
 func (c *client) executeRequest(ctx context.Context, req *internalRequest) error {
-	if req.acceptedContentType == contentTypeNDJSON && req.withResponse != nil {
+	co := callOptionsFromContext(ctx)
+	if co != nil && co.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, co.timeout)
+		defer cancel()
+	}
+
+	if c.handler == nil {
+		return c.execute(ctx, req)
+	}
+
+	info := newRequestInfo(req)
+	if co != nil {
+		info.Metadata = co.metadata
+	}
+	return c.handler(ctx, info)
+}
+
+// handle is the innermost Handler of the middleware chain.
+func (c *client) handle(ctx context.Context, info *RequestInfo) error {
+	req := info.req
+	if len(info.Header) > 0 {
+		req.withHeaders = info.Header
+	}
+	err := c.execute(ctx, req)
+	info.Response = req.withResponse
+	info.StatusCode = req.stats.statusCode
+	return err
+}
+
+func (c *client) execute(ctx context.Context, req *internalRequest) error {
+	err := c.dispatch(ctx, req)
+	if err == nil || isOneShotBody(req) {
+		return err
+	}
+	if key, ok := c.refreshAPIKey(ctx, err); ok {
+		req.stats = requestStats{}
+		err = c.dispatch(WithCallOptions(ctx, CallWithAPIKey(key)), req)
+	}
+	return err
+}
+
+// dispatch sends the request once, or hedged when it is eligible.
+func (c *client) dispatch(ctx context.Context, req *internalRequest) error {
+	if cfg := c.hedgingFor(ctx); cfg != nil && hedgedFunctions[req.functionName] && req.withResponse != nil {
+		return c.executeHedged(ctx, req, cfg)
+	}
+	return c.executeOnce(ctx, req)
+}
+
+// executeOnce sends the request, with retries, and decodes its response.
+func (c *client) executeOnce(ctx context.Context, req *internalRequest) error {
+	if _, ok := req.withResponse.(ndjsonHandler); !ok && req.acceptedContentType == contentTypeNDJSON && req.withResponse != nil {
 		if _, _, err := validateNDJSONDestination(req.functionName, req.withResponse); err != nil {
 			return err
 		}
@@ -110,6 +294,7 @@ func (c *client) executeRequest(ctx context.Context, req *internalRequest) error
 
 	resp, err := c.sendRequest(ctx, req, internalError)
 	if err != nil {
+		req.stats.statusCode = internalError.StatusCode
 		return err
 	}
 
@@ -118,6 +303,8 @@ func (c *client) executeRequest(ctx context.Context, req *internalRequest) error
 	}()
 
 	internalError.StatusCode = resp.StatusCode
+	req.stats.statusCode = resp.StatusCode
+	resp.Body = &countingReadCloser{ReadCloser: resp.Body, n: &req.stats.responseBytes}
 
 	if req.acceptedContentType == contentTypeNDJSON && req.withResponse != nil {
 		return c.handleNDJSONResponse(req, resp, internalError)
@@ -128,6 +315,19 @@ func (c *client) executeRequest(ctx context.Context, req *internalRequest) error
 		return err
 	}
 
+	c.logResponse(ctx, req, resp, b)
+	internalError.dump.captureResponse(resp, b)
+
+	// the response follows the client encoding unless the call asked for another one
+	enc := c.encoder
+	if c.contentEncoding.IsZero() {
+		enc = nil
+	}
+	if co := callOptionsFromContext(ctx); co != nil && co.acceptEncoding != nil {
+		enc = c.responseEncoder(resp)
+		internalError.encoder = enc
+	}
+
 	err = c.handleStatusCode(req, resp.StatusCode, b, internalError)
 	if err != nil {
 		return err
@@ -138,7 +338,20 @@ func (c *client) executeRequest(ctx context.Context, req *internalRequest) error
 		return err
 	}
 
-	err = c.handleResponse(req, b, internalError)
+	if raw, ok := req.withResponse.(*[]byte); ok {
+		if *raw, err = decompressBody(resp.Header.Get("Content-Encoding"), b); err != nil {
+			return internalError.WithErrCode(ErrCodeResponseUnmarshalBody, err)
+		}
+		return nil
+	}
+
+	// a 204 or an empty body answering Do has nothing to decode, the response is left as is,
+	// the typed calls expect a body and fail to decode it
+	if req.functionName == "Do" && (resp.StatusCode == http.StatusNoContent || len(b) == 0) {
+		return nil
+	}
+
+	err = c.handleResponse(req, enc, b, internalError)
 	if err != nil {
 		return err
 	}
@@ -146,9 +359,15 @@ func (c *client) executeRequest(ctx context.Context, req *internalRequest) error
 }
 
 func (c *client) handleNDJSONResponse(req *internalRequest, resp *http.Response, internalError *Error) error {
-	sliceValue, _, err := validateNDJSONDestination(req.functionName, req.withResponse)
-	if err != nil {
-		return err
+	handler, streamed := req.withResponse.(ndjsonHandler)
+
+	var sliceValue reflect.Value
+	if !streamed {
+		var err error
+		sliceValue, _, err = validateNDJSONDestination(req.functionName, req.withResponse)
+		if err != nil {
+			return err
+		}
 	}
 
 	if err := c.handleStreamingStatusCode(req, resp, internalError); err != nil {
@@ -159,11 +378,6 @@ func (c *client) handleNDJSONResponse(req *internalRequest, resp *http.Response,
 		return err
 	}
 
-	// The Meilisearch API returns concatenated JSON values (akin to NDJSON
-	// but the server does not stream them). Read every value through the
-	// response decoder up front and assemble a single JSON array, then
-	// unmarshal once into dst so the SDK does not expose streaming
-	// semantics to its callers.
 	dec, err := c.responseDecoder(resp)
 	if err != nil {
 		return fmt.Errorf("%s: failed to create response decoder: %w", req.functionName, err)
@@ -172,19 +386,26 @@ func (c *client) handleNDJSONResponse(req *internalRequest, resp *http.Response,
 		_ = dec.Close()
 	}()
 
+	if streamed {
+		// every value is handed over as soon as it is decoded, nothing is retained
+		return c.decodeNDJSON(req, dec, handler)
+	}
+
+	// The Meilisearch API returns concatenated JSON values (akin to NDJSON
+	// but the server does not stream them). Read every value through the
+	// response decoder up front and assemble a single JSON array, then
+	// unmarshal once into dst so the SDK does not expose streaming
+	// semantics to its callers.
 	values := make([]json.RawMessage, 0)
 	totalBytes := 2
 
-	for {
-		var raw json.RawMessage
-		if err := dec.Decode(&raw); err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			return fmt.Errorf("%s: failed to decode NDJSON: %w", req.functionName, err)
-		}
+	err = c.decodeNDJSON(req, dec, func(raw json.RawMessage) error {
 		values = append(values, raw)
 		totalBytes += len(raw) + 1
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	if len(values) == 0 {
@@ -209,6 +430,23 @@ func (c *client) handleNDJSONResponse(req *internalRequest, resp *http.Response,
 	return nil
 }
 
+// decodeNDJSON passes the values read by dec to handler until the end of the response, or
+// until handler returns an error.
+func (c *client) decodeNDJSON(req *internalRequest, dec streamDecoder, handler ndjsonHandler) error {
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("%s: failed to decode NDJSON: %w", req.functionName, err)
+		}
+		if err := handler(raw); err != nil {
+			return err
+		}
+	}
+}
+
 func (c *client) handleStreamingStatusCode(req *internalRequest, resp *http.Response, internalError *Error) error {
 	if req.acceptedStatusCodes == nil {
 		return nil
@@ -228,6 +466,7 @@ func (c *client) handleStreamingStatusCode(req *internalRequest, resp *http.Resp
 	if err != nil {
 		return err
 	}
+	internalError.dump.captureResponse(resp, body)
 
 	return c.handleStatusCode(req, resp.StatusCode, body, internalError)
 }
@@ -270,13 +509,27 @@ func validateContentType(functionName, expectedContentType, contentType string)
 	return fmt.Errorf("%s: unexpected Content-Type %q, expected %q", functionName, contentType, expectedContentType)
 }
 
+// responseEncoder returns the encoder matching the Content-Encoding of the response,
+// nil when the body is not compressed.
+func (c *client) responseEncoder(resp *http.Response) encoder {
+	ce := ContentEncoding(strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))))
+	switch {
+	case ce.IsZero():
+		return nil
+	case ce == c.contentEncoding && c.encoder != nil:
+		return c.encoder
+	default:
+		return newEncoding(ce, DefaultCompression)
+	}
+}
+
 func (c *client) responseDecoder(resp *http.Response) (streamDecoder, error) {
 	contentEncoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
 	ce := ContentEncoding(contentEncoding)
 	switch ce {
 	case "":
 		return &jsonStreamDecoder{Decoder: json.NewDecoder(resp.Body)}, nil
-	case GzipEncoding, DeflateEncoding, BrotliEncoding:
+	case GzipEncoding, DeflateEncoding, BrotliEncoding, ZstdEncoding:
 		encoder := c.encoder
 		if encoder == nil || ce != c.contentEncoding {
 			encoder = newEncoding(ce, DefaultCompression)
@@ -300,7 +553,13 @@ func (c *client) sendRequest(
 	internalError *Error,
 ) (*http.Response, error) {
 
-	apiURL, err := url.Parse(c.host + req.endpoint)
+	host := c.host
+	if c.nodes != nil {
+		req.node = c.nodes.pick(balancedFunctions[req.functionName])
+		host = req.node.host
+	}
+
+	apiURL, err := url.Parse(host + req.endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse url: %w", err)
 	}
@@ -314,13 +573,27 @@ func (c *client) sendRequest(
 		apiURL.RawQuery = query.Encode()
 	}
 
-	body, err := c.buildBody(req, internalError)
-	if err != nil {
+	var (
+		body      io.ReadCloser
+		bodyBytes []byte
+		getBody   func() (io.ReadCloser, error)
+	)
+	stream, streamed := streamingBody(req.withRequest)
+	if streamed {
+		if req.method == http.MethodGet || req.method == http.MethodHead {
+			return nil, ErrInvalidRequestMethod
+		}
+		if req.contentType == "" {
+			return nil, ErrRequestBodyWithoutContentType
+		}
+		if body, getBody, err = c.openStream(req, stream, internalError); err != nil {
+			return nil, err
+		}
+	} else if body, err = c.buildBody(req, internalError); err != nil {
 		return nil, err
 	}
 
-	var bodyBytes []byte
-	if body != nil {
+	if body != nil && !streamed {
 		bodyBytes, err = io.ReadAll(body)
 		// Close the original body (handles sync.Pool internally if you implement a custom closer, or let GC handle it)
 		_ = body.Close()
@@ -330,7 +603,8 @@ func (c *client) sendRequest(
 		body = io.NopCloser(bytes.NewReader(bodyBytes))
 	}
 
-	request, err := http.NewRequestWithContext(ctx, req.method, apiURL.String(), body)
+	request, err := http.NewRequestWithContext(context.WithValue(ctx, functionKey{}, req.functionName),
+		req.method, apiURL.String(), body)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create request: %w", err)
 	}
@@ -341,27 +615,62 @@ func (c *client) sendRequest(
 		}
 
 		request.ContentLength = int64(len(bodyBytes))
+		req.stats.requestBytes = request.ContentLength
+	} else if streamed {
+		// streamed with chunked transfer encoding, only a reopenable source can be sent again
+		request.GetBody = getBody
+		request.ContentLength = -1
 	}
 
 	// adding request headers
 	if req.contentType != "" {
 		request.Header.Set("Content-Type", req.contentType)
 	}
-	if c.apiKey != "" {
-		request.Header.Set("Authorization", "Bearer "+c.apiKey)
+	co := callOptionsFromContext(ctx)
+
+	var apiKey string
+	if co != nil && co.apiKey != "" {
+		apiKey = co.apiKey
+	} else if apiKey, err = c.currentAPIKey(ctx); err != nil {
+		return nil, err
+	}
+	if apiKey != "" {
+		request.Header.Set("Authorization", "Bearer "+apiKey)
 	}
 
-	if (req.withResponse != nil || req.withResponseEncoding) && !c.contentEncoding.IsZero() {
+	if co != nil && co.acceptEncoding != nil {
+		if !co.acceptEncoding.IsZero() {
+			request.Header.Set("Accept-Encoding", co.acceptEncoding.String())
+		}
+	} else if (req.withResponse != nil || req.withResponseEncoding) && !c.contentEncoding.IsZero() {
 		request.Header.Set("Accept-Encoding", c.contentEncoding.String())
 	}
 
-	if req.withRequest != nil && !c.contentEncoding.IsZero() {
-		request.Header.Set("Content-Encoding", c.contentEncoding.String())
+	if !req.stats.requestEncoding.IsZero() {
+		request.Header.Set("Content-Encoding", req.stats.requestEncoding.String())
 	}
 
 	request.Header.Set("User-Agent", GetQualifiedVersion())
 
-	resp, err := c.do(request, internalError)
+	if co != nil {
+		for key, values := range co.headers {
+			request.Header[key] = append([]string(nil), values...)
+		}
+	}
+
+	for key, values := range req.withHeaders {
+		request.Header.Del(key)
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+
+	c.logRequest(ctx, req, request, bodyBytes)
+	if c.requestDump {
+		internalError.dump = newRequestDump(request, bodyBytes, streamed)
+	}
+
+	resp, err := c.do(request, req, internalError)
 	if err != nil {
 		if rc, ok := body.(io.Closer); ok {
 			_ = rc.Close()
@@ -376,67 +685,160 @@ func (c *client) sendRequest(
 	return resp, nil
 }
 
-func (c *client) do(req *http.Request, internalError *Error) (resp *http.Response, err error) {
+func (c *client) do(request *http.Request, req *internalRequest, internalError *Error) (*http.Response, error) {
+	start := time.Now()
 	retriesCount := uint8(0)
+	balanced := c.nodes != nil && balancedFunctions[req.functionName]
+	var lastErr error
 
 	for {
-		if retriesCount > 0 && req.GetBody != nil {
-			newBody, bodyErr := req.GetBody()
+		if retriesCount > 0 && request.GetBody != nil {
+			newBody, bodyErr := request.GetBody()
 			if bodyErr != nil {
 				return nil, internalError.WithErrCode(CommunicationError,
 					fmt.Errorf("failed to rewind body on retry: %w", bodyErr))
 			}
-			req.Body = newBody
+			request.Body = newBody
 		}
 
-		resp, err = c.client.Do(req)
-		if err != nil {
-			if errors.Is(err, context.DeadlineExceeded) {
-				return nil, internalError.WithErrCode(TimeoutError, err)
+		breaker := c.breakerFor(req)
+		var trial bool
+		if breaker != nil {
+			var ok bool
+			if ok, trial = breaker.allow(); !ok {
+				return nil, internalError.WithErrCode(CircuitBreakerOpen, lastErr)
 			}
-			if errors.Is(err, context.Canceled) {
-				return nil, internalError.WithErrCode(TimeoutError, err)
-			}
-			return nil, internalError.WithErrCode(CommunicationError, err)
 		}
 
-		// Exit if retries are disabled
-		if c.disableRetry {
-			break
+		resp, err := c.roundTrip(request, retriesCount)
+		if breaker != nil {
+			breaker.record(trial, resp, err)
 		}
 
-		// Check if response status is retryable and we haven't exceeded max retries
-		if c.retryOnStatus[resp.StatusCode] && retriesCount < c.maxRetries {
-			retriesCount++
+		attempt := RetryAttempt{
+			Attempt: retriesCount,
+			Method:  request.Method,
+			URL:     request.URL.String(),
+			Err:     err,
+		}
+		if err == nil {
+			internalError.StatusCode = resp.StatusCode
+			attempt.StatusCode = resp.StatusCode
+			if resp.StatusCode == http.StatusTooManyRequests {
+				req.stats.throttled = true
+			}
+		} else {
+			// the answer of a previous attempt does not describe this one
+			internalError.StatusCode, internalError.APIError = 0, APIErrorDetails{}
+		}
 
-			// Close response body to prevent memory leaks
-			_ = resp.Body.Close()
+		// A read failing on a node is replayed at once on another healthy node
+		failover := false
+		if c.nodes != nil && req.node != nil && c.nodes.observe(req.node, resp, err) {
+			failover = balanced && !c.disableRetry && isRewindable(request) && c.nodes.hasHealthy()
+		}
 
-			// Handle backoff with context cancellation support
-			backoff := c.retryBackoff(retriesCount)
-			timer := time.NewTimer(backoff)
+		if !failover && !c.shouldRetry(request, resp, err) {
+			attempt.Elapsed = time.Since(start)
+			c.reportAttempt(attempt)
+			if err != nil {
+				return nil, transportError(err, internalError)
+			}
+			return resp, nil
+		}
 
-			select {
-			case <-req.Context().Done():
-				err := req.Context().Err()
-				timer.Stop()
-				return nil, internalError.WithErrCode(TimeoutError, err)
-			case <-timer.C:
-				// Retry after backoff
-				timer.Stop()
+		// The attempt is retryable, keep its error in case no retry is left
+		lastErr = err
+		backoff := c.retryBackoff(retriesCount + 1)
+		if failover {
+			backoff = 0
+		}
+		if err == nil {
+			if retryAfter, ok := parseRetryAfter(resp); ok && c.respectRetryAfter && !failover {
+				backoff = retryAfter
+				if backoff > c.maxRetryAfter {
+					backoff = c.maxRetryAfter
+				}
 			}
+			// statusError closes the response body to prevent memory leaks
+			lastErr = statusError(resp, internalError)
+		}
+
+		attempt.Elapsed = time.Since(start)
+		if retriesCount >= c.maxRetries || (c.maxElapsedTime > 0 && attempt.Elapsed+backoff > c.maxElapsedTime) {
+			c.reportAttempt(attempt)
+			return nil, internalError.WithErrCode(MaxRetriesExceeded, lastErr)
+		}
+
+		attempt.Retry = true
+		attempt.Backoff = backoff
+		c.reportAttempt(attempt)
+
+		retriesCount++
+		req.stats.retries = retriesCount
+		c.logRetry(request.Context(), request, retriesCount, attempt.StatusCode, backoff)
+
+		// Handle backoff with context cancellation support
+		timer := time.NewTimer(backoff)
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, internalError.WithErrCode(TimeoutError, request.Context().Err())
+		case <-timer.C:
+			// Retry after backoff
+		}
+
+		if balanced {
+			c.reroute(request, req)
+		}
+	}
+}
 
-			continue
+// shouldRetry reports whether an attempt failed in a way the retry configuration covers.
+func (c *client) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if c.disableRetry || !isRewindable(req) {
+		return false
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
 		}
+		return c.retryNetworkErrors && isIdempotentMethod(req.Method) && isTransientNetworkError(err)
+	}
+	return c.retryOnStatus[resp.StatusCode]
+}
+
+func (c *client) reportAttempt(attempt RetryAttempt) {
+	if c.onAttempt != nil {
+		c.onAttempt(attempt)
+	}
+}
 
-		break
+func transportError(err error, internalError *Error) *Error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return internalError.WithErrCode(TimeoutError, err)
 	}
+	return internalError.WithErrCode(CommunicationError, err)
+}
 
-	// Return error if retries exceeded the maximum limit
-	if !c.disableRetry && retriesCount >= c.maxRetries {
-		return nil, internalError.WithErrCode(MaxRetriesExceeded, nil)
+// roundTrip sends a single HTTP attempt, inside its own span when a Tracer is configured.
+func (c *client) roundTrip(req *http.Request, attempt uint8) (*http.Response, error) {
+	if c.tracer == nil {
+		return c.client.Do(req)
 	}
 
+	ctx, span := c.tracer.Start(req.Context(), "meilisearch.attempt",
+		Attribute{Key: AttributeRetryAttempt, Value: int(attempt)},
+		Attribute{Key: AttributeHTTPMethod, Value: req.Method},
+	)
+	defer span.End()
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttributes(Attribute{Key: AttributeHTTPStatus, Value: resp.StatusCode})
 	return resp, nil
 }
 
@@ -462,10 +864,10 @@ func (c *client) handleStatusCode(req *internalRequest, statusCode int, body []b
 	return nil
 }
 
-func (c *client) handleResponse(req *internalRequest, body []byte, internalError *Error) (err error) {
+func (c *client) handleResponse(req *internalRequest, enc encoder, body []byte, internalError *Error) (err error) {
 	if req.withResponse != nil {
-		if !c.contentEncoding.IsZero() {
-			if err := c.encoder.Decode(body, req.withResponse); err != nil {
+		if enc != nil {
+			if err := enc.Decode(body, req.withResponse); err != nil {
 				return internalError.WithErrCode(ErrCodeResponseUnmarshalBody, err)
 			}
 		} else {
@@ -494,6 +896,7 @@ func (c *client) buildBody(req *internalRequest, internalError *Error) (io.ReadC
 			return nil, ErrRequestBodyWithoutContentType
 		}
 
+		size := -1
 		switch v := req.withRequest.(type) {
 		case io.ReadCloser:
 			body = v
@@ -501,6 +904,7 @@ func (c *client) buildBody(req *internalRequest, internalError *Error) (io.ReadC
 			body = io.NopCloser(v)
 		case []byte:
 			body = io.NopCloser(bytes.NewReader(v))
+			size = len(v)
 		default:
 			data, err := c.jsonMarshal(req.withRequest)
 			if err != nil {
@@ -508,9 +912,13 @@ func (c *client) buildBody(req *internalRequest, internalError *Error) (io.ReadC
 					fmt.Errorf("failed to marshal request with json.Marshal: %w", err))
 			}
 			body = io.NopCloser(bytes.NewReader(data))
+			size = len(data)
+		}
+		if r, ok := req.withRequest.(interface{ Len() int }); ok {
+			size = r.Len()
 		}
 
-		if !c.contentEncoding.IsZero() {
+		if c.compresses(req.contentType, size) {
 			compressedBody, err := c.encoder.Encode(body)
 			_ = body.Close()
 
@@ -518,9 +926,35 @@ func (c *client) buildBody(req *internalRequest, internalError *Error) (io.ReadC
 				return nil, internalError.WithErrCode(ErrCodeMarshalRequest,
 					fmt.Errorf("failed to encode request body: %w", err))
 			}
+			req.stats.requestEncoding = c.contentEncoding
 			return compressedBody, nil
 		}
 	}
 
 	return body, nil
 }
+
+// compresses reports whether a request body of the given Content-Type and size is compressed,
+// a negative size is unknown.
+func (c *client) compresses(contentType string, size int) bool {
+	if c.contentEncoding.IsZero() {
+		return false
+	}
+	if c.compressionThresholds == nil {
+		return true
+	}
+	threshold := c.compressionThresholds.threshold(contentType)
+	return threshold >= 0 && (size < 0 || size >= threshold)
+}
+
+// countingReadCloser counts the bytes read from the wrapped body.
+type countingReadCloser struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	*c.n += int64(n)
+	return n, err
+}
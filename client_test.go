@@ -47,6 +47,9 @@ type failingEncoder struct{}
 func (fe failingEncoder) Encode(r io.Reader) (io.ReadCloser, error) {
 	return nil, errors.New("dummy encoding failure")
 }
+func (fe failingEncoder) EncodeStream(r io.Reader) (io.ReadCloser, error) {
+	return nil, errors.New("dummy encoding failure")
+}
 func (fe failingEncoder) Decode(b []byte, v interface{}) error {
 	return errors.New("dummy decode failure")
 }
@@ -529,12 +532,14 @@ func TestClient_Coverage_EdgeCases(t *testing.T) {
 		require.Error(t, err)
 	})
 
-	t.Run("sendRequest - Body ReadAll Error", func(t *testing.T) {
+	t.Run("sendRequest - Body Open Error", func(t *testing.T) {
 		err := c.executeRequest(context.Background(), &internalRequest{
 			endpoint:    "/test",
 			method:      http.MethodPost,
 			contentType: contentTypeJSON,
-			withRequest: errorReadCloser{}, // Fails when sendRequest calls io.ReadAll()
+			withRequest: NewReopenableReader(func() (io.ReadCloser, error) {
+				return nil, errors.New("mock open error")
+			}),
 		})
 		require.Error(t, err)
 	})
@@ -602,7 +607,7 @@ func TestClient_Coverage_EdgeCases(t *testing.T) {
 			retryOnStatus: map[int]bool{http.StatusOK: true}, // Force retry to trigger GetBody
 		})
 		cRetry.retryBackoff = func(attempt uint8) time.Duration { return 0 }
-		_, err := cRetry.do(req, &Error{})
+		_, err := cRetry.do(req, &internalRequest{}, &Error{})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to rewind body")
 	})
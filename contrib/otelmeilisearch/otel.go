@@ -0,0 +1,77 @@
+// Package otelmeilisearch adapts OpenTelemetry tracing to the meilisearch.Tracer interface.
+//
+//	Example:
+//
+//	meili := meilisearch.New("http://localhost:7700",
+//		meilisearch.WithAPIKey("foobar"),
+//		meilisearch.WithTracer(otelmeilisearch.NewTracer(otel.GetTracerProvider())),
+//	)
+package otelmeilisearch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/meilisearch/meilisearch-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name of the spans emitted by the adapter.
+const ScopeName = "github.com/meilisearch/meilisearch-go"
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer returns a meilisearch.Tracer that starts client spans from the given provider.
+func NewTracer(provider trace.TracerProvider, opts ...trace.TracerOption) meilisearch.Tracer {
+	return &tracer{tracer: provider.Tracer(ScopeName, opts...)}
+}
+
+func (t *tracer) Start(ctx context.Context, name string, attrs ...meilisearch.Attribute) (context.Context, meilisearch.Span) {
+	ctx, span := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(convertAttributes(attrs)...),
+	)
+	return ctx, &otelSpan{span: span}
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s *otelSpan) SetAttributes(attrs ...meilisearch.Attribute) {
+	s.span.SetAttributes(convertAttributes(attrs)...)
+}
+
+func (s *otelSpan) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *otelSpan) End() {
+	s.span.End()
+}
+
+func convertAttributes(attrs []meilisearch.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(a.Key, v))
+		case int:
+			kvs = append(kvs, attribute.Int(a.Key, v))
+		case int64:
+			kvs = append(kvs, attribute.Int64(a.Key, v))
+		case bool:
+			kvs = append(kvs, attribute.Bool(a.Key, v))
+		case float64:
+			kvs = append(kvs, attribute.Float64(a.Key, v))
+		default:
+			kvs = append(kvs, attribute.String(a.Key, fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
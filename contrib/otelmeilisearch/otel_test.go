@@ -0,0 +1,42 @@
+package otelmeilisearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/meilisearch/meilisearch-go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Index movies not found.","code":"index_not_found","type":"invalid_request","link":""}`))
+	}))
+	defer ts.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	sv := meilisearch.New(ts.URL, meilisearch.WithTracer(NewTracer(provider)))
+	_, err := sv.Index("movies").FetchInfoWithContext(context.Background())
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	attempt, call := spans[0], spans[1]
+	require.Equal(t, "meilisearch.attempt", attempt.Name())
+	require.Equal(t, "meilisearch.FetchInfo", call.Name())
+	require.Equal(t, call.SpanContext().SpanID(), attempt.Parent().SpanID())
+	require.Equal(t, codes.Error, call.Status().Code)
+	require.Contains(t, call.Attributes(), attribute.String(meilisearch.AttributeIndexUID, "movies"))
+	require.Contains(t, call.Attributes(), attribute.String(meilisearch.AttributeAPIErrCode, "index_not_found"))
+	require.Contains(t, call.Attributes(), attribute.Int(meilisearch.AttributeHTTPStatus, http.StatusNotFound))
+}
@@ -0,0 +1,84 @@
+package meilisearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// APIKeyProvider returns the API key sent with a request. It is called before every request,
+// so it should serve the key from memory, and it must be safe for concurrent use.
+//
+// When Meilisearch answers invalid_api_key, the client calls the provider again with a context
+// for which APIKeyRefreshRequested is true and retries the call once with the key it returns.
+// A caching provider should fetch a fresh key in that case. The calls rejected while a refresh
+// is in flight share it instead of asking for another one.
+type APIKeyProvider func(ctx context.Context) (string, error)
+
+// keyRefresh is a call of the APIKeyProvider for a fresh key, shared by the calls rejected
+// while it is in flight.
+type keyRefresh struct {
+	done chan struct{}
+	key  string
+	err  error
+}
+
+type apiKeyRefreshKey struct{}
+
+// APIKeyRefreshRequested reports whether the client calls the APIKeyProvider because the
+// previous key was rejected by Meilisearch.
+func APIKeyRefreshRequested(ctx context.Context) bool {
+	refresh, _ := ctx.Value(apiKeyRefreshKey{}).(bool)
+	return refresh
+}
+
+// currentAPIKey returns the key of the APIKeyProvider, or the static key when there is none.
+func (c *client) currentAPIKey(ctx context.Context) (string, error) {
+	if c.apiKeyProvider == nil {
+		return c.apiKey, nil
+	}
+	key, err := c.apiKeyProvider(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to get API key: %w", err)
+	}
+	return key, nil
+}
+
+// refreshAPIKey asks the APIKeyProvider for a fresh key after err, it returns the key and
+// whether the call should be retried with it.
+func (c *client) refreshAPIKey(ctx context.Context, err error) (string, bool) {
+	if c.apiKeyProvider == nil {
+		return "", false
+	}
+	if co := callOptionsFromContext(ctx); co != nil && co.apiKey != "" {
+		// the key of the call is not the provider's one
+		return "", false
+	}
+
+	var meiliErr *Error
+	if !errors.As(err, &meiliErr) || meiliErr.APIError.Code != APIErrCodeInvalidAPIKey {
+		return "", false
+	}
+
+	c.refreshMu.Lock()
+	refresh := c.refresh
+	if refresh == nil {
+		refresh = &keyRefresh{done: make(chan struct{})}
+		c.refresh = refresh
+		c.refreshMu.Unlock()
+
+		refresh.key, refresh.err = c.apiKeyProvider(context.WithValue(ctx, apiKeyRefreshKey{}, true))
+		c.refreshMu.Lock()
+		c.refresh = nil
+		c.refreshMu.Unlock()
+		close(refresh.done)
+	} else {
+		c.refreshMu.Unlock()
+		select {
+		case <-refresh.done:
+		case <-ctx.Done():
+			return "", false
+		}
+	}
+	return refresh.key, refresh.err == nil
+}
@@ -0,0 +1,170 @@
+package meilisearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// rotatingKey caches a key and fetches the latest one only when a refresh is requested.
+type rotatingKey struct {
+	mu      sync.Mutex
+	cached  string
+	latest  string
+	fetches int
+}
+
+func (r *rotatingKey) provide(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if APIKeyRefreshRequested(ctx) {
+		r.fetches++
+		r.cached = r.latest
+	}
+	return r.cached, nil
+}
+
+func TestAPIKeyProvider_RefreshOnInvalidKey(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer newKey" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"The provided API key is invalid.","code":"invalid_api_key","type":"auth","link":""}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"pkgVersion":"1.2.3"}`))
+	}))
+	defer ts.Close()
+
+	key := &rotatingKey{cached: "oldKey", latest: "newKey"}
+	sv := New(ts.URL, WithAPIKey("staticKey"), WithAPIKeyProvider(key.provide))
+
+	resp, err := sv.Version()
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3", resp.PkgVersion)
+	require.Equal(t, int32(2), requests.Load())
+	require.Equal(t, 1, key.fetches)
+
+	// the refreshed key is used from now on, concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := sv.Version()
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(12), requests.Load())
+
+	// a key rejected after a refresh is not retried twice
+	key.mu.Lock()
+	key.cached, key.latest = "revoked", "revoked"
+	key.mu.Unlock()
+	_, err = sv.Version()
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, APIErrCodeInvalidAPIKey, e.APIError.Code)
+	require.Equal(t, int32(14), requests.Load())
+}
+
+func TestAPIKeyProvider_RetryUsesRefreshedKey(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer newKey" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"The provided API key is invalid.","code":"invalid_api_key","type":"auth","link":""}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"pkgVersion":"1.2.3"}`))
+	}))
+	defer ts.Close()
+
+	// the provider does not cache the fresh key, only the refresh call returns it
+	provider := func(ctx context.Context) (string, error) {
+		if APIKeyRefreshRequested(ctx) {
+			return "newKey", nil
+		}
+		return "oldKey", nil
+	}
+	_, err := New(ts.URL, WithAPIKeyProvider(provider)).Version()
+	require.NoError(t, err)
+}
+
+func TestAPIKeyProvider_ConcurrentRefreshesAreShared(t *testing.T) {
+	const calls = 10
+	var arrived sync.WaitGroup
+	arrived.Add(calls)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer newKey" {
+			// every call is rejected at once, so that they all ask for a refresh together
+			arrived.Done()
+			arrived.Wait()
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"The provided API key is invalid.","code":"invalid_api_key","type":"auth","link":""}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"pkgVersion":"1.2.3"}`))
+	}))
+	defer ts.Close()
+
+	var refreshes atomic.Int32
+	provider := func(ctx context.Context) (string, error) {
+		if !APIKeyRefreshRequested(ctx) {
+			return "oldKey", nil
+		}
+		refreshes.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		return "newKey", nil
+	}
+	sv := New(ts.URL, WithAPIKeyProvider(provider))
+
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := sv.Version()
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), refreshes.Load())
+}
+
+func TestAPIKeyProvider_Error(t *testing.T) {
+	sv := New("http://localhost:1", WithAPIKeyProvider(func(ctx context.Context) (string, error) {
+		return "", errors.New("vault unavailable")
+	}))
+
+	_, err := sv.Version()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "vault unavailable")
+
+	_, err = sv.GenerateTenantToken("85c3c2f9-bdd6-41f1-abd8-11fcf80e0f76", map[string]interface{}{"*": nil}, nil)
+	require.Error(t, err)
+}
+
+func TestAPIKeyProvider_GenerateTenantToken(t *testing.T) {
+	sv := New("http://localhost:7700", WithAPIKeyProvider(func(ctx context.Context) (string, error) {
+		return "providedKey", nil
+	}))
+
+	token, err := sv.GenerateTenantToken("85c3c2f9-bdd6-41f1-abd8-11fcf80e0f76", map[string]interface{}{"*": nil}, nil)
+	require.NoError(t, err)
+
+	parsed, err := jwt.ParseWithClaims(token, &TenantTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte("providedKey"), nil
+	})
+	require.NoError(t, err)
+	require.True(t, parsed.Valid)
+}
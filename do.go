@@ -0,0 +1,35 @@
+package meilisearch
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// successStatusCodes are the 2xx status codes, all accepted by Do.
+var successStatusCodes = func() []int {
+	codes := make([]int, 0, 100)
+	for code := http.StatusOK; code < http.StatusMultipleChoices; code++ {
+		codes = append(codes, code)
+	}
+	return codes
+}()
+
+func (m *meilisearch) Do(ctx context.Context, method, path string, query map[string]string, body, out interface{}) error {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	req := &internalRequest{
+		endpoint:            path,
+		method:              strings.ToUpper(method),
+		withRequest:         body,
+		withResponse:        out,
+		withQueryParams:     query,
+		acceptedStatusCodes: successStatusCodes,
+		functionName:        "Do",
+	}
+	if body != nil {
+		req.contentType = contentTypeJSON
+	}
+	return m.client.executeRequest(ctx, req)
+}
@@ -0,0 +1,145 @@
+package meilisearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeilisearch_Do(t *testing.T) {
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer masterKey", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/logs/stderr":
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"target":"milli=debug"}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		case "/metrics":
+			require.Equal(t, "movies", r.URL.Query().Get("index"))
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("meilisearch_index_count 1\n"))
+		case "/experimental-features":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"metrics":true}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found","code":"not_found","type":"invalid_request","link":""}`))
+		}
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL, WithAPIKey("masterKey"))
+	ctx := context.Background()
+
+	require.NoError(t, sv.Do(ctx, http.MethodPost, "/logs/stderr", nil, map[string]string{"target": "milli=debug"}, nil))
+
+	var metrics []byte
+	require.NoError(t, sv.Do(ctx, http.MethodGet, "metrics", map[string]string{"index": "movies"}, nil, &metrics))
+	require.Equal(t, "meilisearch_index_count 1\n", string(metrics))
+	require.Equal(t, int32(2), atomic.LoadInt32(&attempts), "the 503 is retried")
+
+	var features map[string]bool
+	require.NoError(t, sv.Do(ctx, http.MethodGet, "/experimental-features", nil, nil, &features))
+	require.True(t, features["metrics"])
+
+	err := sv.Do(ctx, http.MethodGet, "/unknown", nil, nil, nil)
+	require.ErrorIs(t, err, APIErrCodeNotFound)
+	var meiliErr *Error
+	require.True(t, errors.As(err, &meiliErr))
+	require.Equal(t, "Do", meiliErr.Function)
+	require.Equal(t, "/unknown", meiliErr.Endpoint)
+}
+
+func TestMeilisearch_Do_Compression(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		body, err := decompressBody("gzip", mustReadAll(t, r.Body))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"q":"prince"}`, string(body))
+
+		require.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+		compressed, err := newEncoding(GzipEncoding, DefaultCompression).Encode(strings.NewReader(`{"hits":[]}`))
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(mustReadAll(t, compressed))
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL, WithContentEncoding(GzipEncoding, DefaultCompression))
+	var raw json.RawMessage
+	require.NoError(t, sv.Do(context.Background(), http.MethodPost, "/indexes/movies/search", nil,
+		map[string]string{"q": "prince"}, &raw))
+	require.JSONEq(t, `{"hits":[]}`, string(raw))
+
+	var rawBytes []byte
+	require.NoError(t, sv.Do(context.Background(), http.MethodPost, "/indexes/movies/search", nil,
+		map[string]string{"q": "prince"}, &rawBytes))
+	require.Equal(t, `{"hits":[]}`, string(rawBytes))
+}
+
+func mustReadAll(t *testing.T, r io.Reader) []byte {
+	t.Helper()
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return data
+}
+
+func TestMeilisearch_Do_Status(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/empty":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+		case "/partial":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(`{"partial":true}`))
+		}
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL)
+	ctx := context.Background()
+
+	out := map[string]bool{"untouched": true}
+	require.NoError(t, sv.Do(ctx, http.MethodDelete, "/no-content", nil, nil, &out))
+	require.Equal(t, map[string]bool{"untouched": true}, out)
+	require.NoError(t, sv.Do(ctx, http.MethodPost, "/empty", nil, nil, &out))
+	require.Equal(t, map[string]bool{"untouched": true}, out)
+
+	var partial map[string]bool
+	require.NoError(t, sv.Do(ctx, http.MethodGet, "/partial", nil, nil, &partial))
+	require.True(t, partial["partial"])
+}
+
+func TestEmptyBody_TypedCallFails(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+	}))
+	defer ts.Close()
+
+	_, err := New(ts.URL).GetTask(7)
+	var meiliErr *Error
+	require.ErrorAs(t, err, &meiliErr)
+	require.Equal(t, ErrCodeResponseUnmarshalBody, meiliErr.ErrCode)
+}
@@ -0,0 +1,204 @@
+package meilisearch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// dumpSkippedHeaders describe how the body travelled on the wire, the dump shows it decompressed.
+var dumpSkippedHeaders = map[string]bool{
+	"Content-Encoding":  true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+}
+
+// RequestDump is the HTTP request behind an *Error, along with the last response received for
+// it. It is captured when the client is built with WithRequestDump, see Error.Dump.
+//
+// The Authorization header is redacted and the bodies are decompressed.
+type RequestDump struct {
+	// Method is the HTTP verb of the request
+	Method string
+
+	// URL is the full URL of the request, query parameters included
+	URL string
+
+	// Header holds the headers of the request
+	Header http.Header
+
+	// Body of the request, nil when the call has no body
+	Body []byte
+
+	// BodyStreamed is set when the body was streamed from a reader, it is then not captured
+	BodyStreamed bool
+
+	// StatusCode of the last response, zero when no response came back
+	StatusCode int
+
+	// ResponseHeader holds the headers of the last response
+	ResponseHeader http.Header
+
+	// ResponseBody of the last response
+	ResponseBody []byte
+}
+
+// Curl returns a curl command sending the request again, empty for a nil dump.
+func (d *RequestDump) Curl() string {
+	if d == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("curl -X " + d.Method + " " + shellQuote(d.URL))
+	for _, key := range sortedKeys(d.Header) {
+		if dumpSkippedHeaders[key] {
+			continue
+		}
+		if key == "Accept-Encoding" {
+			b.WriteString(" \\\n  --compressed")
+			continue
+		}
+		for _, value := range d.Header[key] {
+			b.WriteString(" \\\n  -H " + shellQuote(key+": "+value))
+		}
+	}
+	if d.Body != nil {
+		b.WriteString(" \\\n  --data-binary " + shellQuote(string(d.Body)))
+	}
+	return b.String()
+}
+
+// String returns the raw HTTP transcript of the request and of the last response, empty for a
+// nil dump.
+func (d *RequestDump) String() string {
+	if d == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	target, host := d.URL, ""
+	if i := strings.Index(target, "://"); i >= 0 {
+		target = target[i+3:]
+		if j := strings.IndexByte(target, '/'); j >= 0 {
+			host, target = target[:j], target[j:]
+		} else {
+			host, target = target, "/"
+		}
+	}
+	fmt.Fprintf(&b, "%s %s HTTP/1.1\n", d.Method, target)
+	if host != "" {
+		fmt.Fprintf(&b, "Host: %s\n", host)
+	}
+	writeHeader(&b, d.Header)
+	b.WriteString("\n")
+	switch {
+	case d.BodyStreamed:
+		b.WriteString("[streamed body not captured]\n")
+	case d.Body != nil:
+		b.Write(d.Body)
+		b.WriteString("\n")
+	}
+
+	if d.StatusCode != 0 {
+		fmt.Fprintf(&b, "\nHTTP/1.1 %d %s\n", d.StatusCode, http.StatusText(d.StatusCode))
+		writeHeader(&b, d.ResponseHeader)
+		b.WriteString("\n")
+		if len(d.ResponseBody) > 0 {
+			b.Write(d.ResponseBody)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// newRequestDump captures request, body is the request body as sent on the wire.
+func newRequestDump(request *http.Request, body []byte, streamed bool) *RequestDump {
+	d := &RequestDump{
+		Method:       request.Method,
+		URL:          request.URL.String(),
+		Header:       redactHeaders(request.Header),
+		BodyStreamed: streamed,
+	}
+	if body != nil {
+		d.Body = decompressOrRaw(request.Header.Get("Content-Encoding"), body)
+	}
+	return d
+}
+
+// captureResponse records resp and its body as read from the wire, it does nothing on a nil dump.
+func (d *RequestDump) captureResponse(resp *http.Response, body []byte) {
+	if d == nil {
+		return
+	}
+	d.StatusCode = resp.StatusCode
+	d.ResponseHeader = resp.Header.Clone()
+	d.ResponseBody = decompressOrRaw(resp.Header.Get("Content-Encoding"), body)
+}
+
+// decompressBody returns body decompressed according to contentEncoding.
+func decompressBody(contentEncoding string, body []byte) ([]byte, error) {
+	var (
+		r   io.Reader
+		err error
+	)
+	switch ce := ContentEncoding(strings.ToLower(strings.TrimSpace(contentEncoding))); ce {
+	case "":
+		return body, nil
+	case GzipEncoding:
+		r, err = gzip.NewReader(bytes.NewReader(body))
+	case DeflateEncoding:
+		r, err = zlib.NewReader(bytes.NewReader(body))
+	case BrotliEncoding:
+		r = brotli.NewReader(bytes.NewReader(body))
+	case ZstdEncoding:
+		return zstdDecodeAll(body)
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %q", ce)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// decompressOrRaw returns body decompressed, or as is when it cannot be decompressed.
+func decompressOrRaw(contentEncoding string, body []byte) []byte {
+	data, err := decompressBody(contentEncoding, body)
+	if err != nil {
+		return body
+	}
+	return data
+}
+
+func writeHeader(b *strings.Builder, header http.Header) {
+	for _, key := range sortedKeys(header) {
+		if dumpSkippedHeaders[key] {
+			continue
+		}
+		for _, value := range header[key] {
+			fmt.Fprintf(b, "%s: %s\n", key, value)
+		}
+	}
+}
+
+func sortedKeys(header http.Header) []string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
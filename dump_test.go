@@ -0,0 +1,79 @@
+package meilisearch
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestDump_FailedCall(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"Invalid value","code":"invalid_document_id","type":"invalid_request","link":""}`))
+	}))
+	defer ts.Close()
+
+	primaryKey := "id"
+	sv := New(ts.URL, WithAPIKey("secret"), WithRequestDump(), WithContentEncoding(GzipEncoding, DefaultCompression))
+	_, err := sv.Index("movies").AddDocuments([]map[string]interface{}{{"id": "it's"}}, &DocumentOptions{PrimaryKey: &primaryKey})
+
+	var meiliErr *Error
+	require.True(t, errors.As(err, &meiliErr))
+	dump := meiliErr.Dump()
+	require.NotNil(t, dump)
+	require.Equal(t, http.MethodPost, dump.Method)
+	require.Equal(t, ts.URL+"/indexes/movies/documents?primaryKey=id", dump.URL)
+	require.Equal(t, redactedValue, dump.Header.Get("Authorization"))
+	require.Equal(t, `[{"id":"it's"}]`, string(dump.Body))
+	require.Equal(t, http.StatusBadRequest, dump.StatusCode)
+	require.JSONEq(t, `{"message":"Invalid value","code":"invalid_document_id","type":"invalid_request","link":""}`, string(dump.ResponseBody))
+
+	require.Equal(t, "curl -X POST '"+ts.URL+"/indexes/movies/documents?primaryKey=id' \\\n"+
+		"  --compressed \\\n"+
+		"  -H 'Authorization: [REDACTED]' \\\n"+
+		"  -H 'Content-Type: application/json' \\\n"+
+		"  -H 'User-Agent: "+GetQualifiedVersion()+"' \\\n"+
+		`  --data-binary '[{"id":"it'\''s"}]'`, dump.Curl())
+
+	transcript := dump.String()
+	require.Contains(t, transcript, "POST /indexes/movies/documents?primaryKey=id HTTP/1.1\nHost: "+ts.Listener.Addr().String()+"\n")
+	require.Contains(t, transcript, "Authorization: [REDACTED]\n")
+	require.NotContains(t, transcript, "secret")
+	require.NotContains(t, transcript, "Content-Encoding")
+	require.Contains(t, transcript, "\n\n[{\"id\":\"it's\"}]\n\nHTTP/1.1 400 Bad Request\nContent-Type: application/json\n")
+}
+
+func TestRequestDump_NoResponse(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close()
+
+	sv := New(ts.URL, WithRequestDump(), DisableRetries())
+	_, err := sv.Version()
+
+	var meiliErr *Error
+	require.True(t, errors.As(err, &meiliErr))
+	require.Equal(t, CommunicationError, meiliErr.ErrCode)
+	dump := meiliErr.Dump()
+	require.NotNil(t, dump)
+	require.Zero(t, dump.StatusCode)
+	require.Nil(t, dump.Body)
+	require.Equal(t, "curl -X GET '"+ts.URL+"/version' \\\n  -H 'User-Agent: "+GetQualifiedVersion()+"'", dump.Curl())
+	require.NotContains(t, dump.String(), "HTTP/1.1 ")
+}
+
+func TestRequestDump_Disabled(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	_, err := New(ts.URL).Version()
+
+	var meiliErr *Error
+	require.True(t, errors.As(err, &meiliErr))
+	require.Nil(t, meiliErr.Dump())
+	require.Empty(t, meiliErr.Dump().Curl())
+	require.Empty(t, meiliErr.Dump().String())
+}
@@ -6,12 +6,49 @@ import (
 	"compress/zlib"
 	"encoding/json"
 	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 	"io"
+	"strings"
 	"sync"
 )
 
+// CompressionThresholds sets, per Content-Type of the request body, the size in bytes from
+// which a body is compressed with the encoding given to WithContentEncoding. Smaller bodies are
+// sent as is, compressing them costs more CPU than it saves on the wire. A zero threshold
+// compresses every body and a negative one none. Bodies of other content types are always
+// compressed, as well as bodies streamed from an io.Reader whose size is unknown.
+type CompressionThresholds struct {
+	JSON   int
+	NDJSON int
+	CSV    int
+}
+
+// DefaultCompressionThresholds send the bodies under 1 KiB, such as most search requests, as
+// is. Over a 100 Mbit/s link compressing JSON pays off from a few hundred bytes, the faster the
+// link the higher the cross-over, see BenchmarkCompressionThreshold.
+var DefaultCompressionThresholds = CompressionThresholds{
+	JSON:   1024,
+	NDJSON: 1024,
+	CSV:    1024,
+}
+
+// threshold returns the threshold of the given Content-Type.
+func (t *CompressionThresholds) threshold(contentType string) int {
+	switch {
+	case strings.HasPrefix(contentType, contentTypeJSON):
+		return t.JSON
+	case strings.HasPrefix(contentType, contentTypeNDJSON):
+		return t.NDJSON
+	case strings.HasPrefix(contentType, contentTypeCSV):
+		return t.CSV
+	default:
+		return 0
+	}
+}
+
 type encoder interface {
 	Encode(io.Reader) (io.ReadCloser, error)
+	EncodeStream(io.Reader) (io.ReadCloser, error)
 	Decode([]byte, interface{}) error
 	Decoder(io.Reader) (streamDecoder, error)
 }
@@ -64,11 +101,38 @@ func newEncoding(ce ContentEncoding, level EncodingCompressionLevel) encoder {
 			},
 			bufferPool: &sync.Pool{New: func() interface{} { return new(bytes.Buffer) }},
 		}
+	case ZstdEncoding:
+		return &zstdEncoder{
+			zsWriterPool: &sync.Pool{
+				New: func() interface{} {
+					w, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstdLevel(level)), zstd.WithEncoderConcurrency(1))
+					return &zstdWriter{writer: w, err: err}
+				},
+			},
+			bufferPool: &sync.Pool{New: func() interface{} { return new(bytes.Buffer) }},
+		}
 	default:
 		return nil
 	}
 }
 
+// zstdLevel maps a compression level, given on the 1 to 9 scale of gzip and deflate, to the
+// closest zstd encoder level.
+func zstdLevel(level EncodingCompressionLevel) zstd.EncoderLevel {
+	switch {
+	case level == DefaultCompression:
+		return zstd.SpeedDefault
+	case level <= BestSpeed:
+		return zstd.SpeedFastest
+	case level <= 5:
+		return zstd.SpeedDefault
+	case level < BestCompression:
+		return zstd.SpeedBetterCompression
+	default:
+		return zstd.SpeedBestCompression
+	}
+}
+
 type gzipEncoder struct {
 	gzWriterPool *sync.Pool
 	bufferPool   *sync.Pool
@@ -101,6 +165,20 @@ func (g *gzipEncoder) Encode(rc io.Reader) (io.ReadCloser, error) {
 	return &pooledBuffer{Buffer: buf, pool: g.bufferPool}, nil
 }
 
+func (g *gzipEncoder) EncodeStream(r io.Reader) (io.ReadCloser, error) {
+	w := g.gzWriterPool.Get().(*gzipWriter)
+	if w.err != nil {
+		g.gzWriterPool.Put(w)
+		return nil, w.err
+	}
+
+	pr, pw := io.Pipe()
+	w.writer.Reset(pw)
+	go pipeEncode(pw, w.writer, r, func() { g.gzWriterPool.Put(w) })
+
+	return pr, nil
+}
+
 func (g *gzipEncoder) Decode(data []byte, vPtr interface{}) error {
 	r, err := gzip.NewReader(bytes.NewReader(data))
 	if err != nil {
@@ -152,6 +230,20 @@ func (f *flateEncoder) Encode(rc io.Reader) (io.ReadCloser, error) {
 	return &pooledBuffer{Buffer: buf, pool: f.bufferPool}, nil
 }
 
+func (f *flateEncoder) EncodeStream(r io.Reader) (io.ReadCloser, error) {
+	w := f.flWriterPool.Get().(*flateWriter)
+	if w.err != nil {
+		f.flWriterPool.Put(w)
+		return nil, w.err
+	}
+
+	pr, pw := io.Pipe()
+	w.writer.Reset(pw)
+	go pipeEncode(pw, w.writer, r, func() { f.flWriterPool.Put(w) })
+
+	return pr, nil
+}
+
 func (f *flateEncoder) Decode(data []byte, vPtr interface{}) error {
 	r, err := zlib.NewReader(bytes.NewBuffer(data))
 	if err != nil {
@@ -195,6 +287,16 @@ func (b *brotliEncoder) Encode(rc io.Reader) (io.ReadCloser, error) {
 	return &pooledBuffer{Buffer: buf, pool: b.bufferPool}, nil
 }
 
+func (b *brotliEncoder) EncodeStream(r io.Reader) (io.ReadCloser, error) {
+	w := b.brWriterPool.Get().(*brotli.Writer)
+
+	pr, pw := io.Pipe()
+	w.Reset(pw)
+	go pipeEncode(pw, w, r, func() { b.brWriterPool.Put(w) })
+
+	return pr, nil
+}
+
 func (b *brotliEncoder) Decode(data []byte, vPtr interface{}) error {
 	r := brotli.NewReader(bytes.NewBuffer(data))
 	return json.NewDecoder(r).Decode(vPtr)
@@ -204,6 +306,110 @@ func (b *brotliEncoder) Decoder(r io.Reader) (streamDecoder, error) {
 	return &jsonStreamDecoder{Decoder: json.NewDecoder(brotli.NewReader(r))}, nil
 }
 
+type zstdEncoder struct {
+	zsWriterPool *sync.Pool
+	bufferPool   *sync.Pool
+}
+
+type zstdWriter struct {
+	writer *zstd.Encoder
+	err    error
+}
+
+// zstdMaxDecodedSize bounds the size a zstd response may decode to.
+const zstdMaxDecodedSize = 1 << 30
+
+var (
+	// zstdDecoder decodes whole bodies, DecodeAll is safe for concurrent use. It is created on
+	// first use by zstdDecodeAll.
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderErr  error
+	zstdDecoderOnce sync.Once
+)
+
+// zstdDecodeAll decodes a whole zstd body.
+func zstdDecodeAll(data []byte) ([]byte, error) {
+	zstdDecoderOnce.Do(func() {
+		zstdDecoder, zstdDecoderErr = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0),
+			zstd.WithDecoderMaxMemory(zstdMaxDecodedSize))
+	})
+	if zstdDecoderErr != nil {
+		return nil, zstdDecoderErr
+	}
+	return zstdDecoder.DecodeAll(data, nil)
+}
+
+func (z *zstdEncoder) Encode(rc io.Reader) (io.ReadCloser, error) {
+	w := z.zsWriterPool.Get().(*zstdWriter)
+	defer z.zsWriterPool.Put(w)
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	buf := z.bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	w.writer.Reset(buf)
+
+	if _, err := copyZeroAlloc(w.writer, rc); err != nil {
+		_ = w.writer.Close()
+		z.bufferPool.Put(buf)
+		return nil, err
+	}
+	if err := w.writer.Close(); err != nil {
+		z.bufferPool.Put(buf)
+		return nil, err
+	}
+
+	return &pooledBuffer{Buffer: buf, pool: z.bufferPool}, nil
+}
+
+func (z *zstdEncoder) EncodeStream(r io.Reader) (io.ReadCloser, error) {
+	w := z.zsWriterPool.Get().(*zstdWriter)
+	if w.err != nil {
+		z.zsWriterPool.Put(w)
+		return nil, w.err
+	}
+
+	pr, pw := io.Pipe()
+	w.writer.Reset(pw)
+	go pipeEncode(pw, w.writer, r, func() { z.zsWriterPool.Put(w) })
+
+	return pr, nil
+}
+
+func (z *zstdEncoder) Decode(data []byte, vPtr interface{}) error {
+	decoded, err := zstdDecodeAll(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(decoded, vPtr)
+}
+
+func (z *zstdEncoder) Decoder(r io.Reader) (streamDecoder, error) {
+	zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(zstdMaxDecodedSize))
+	if err != nil {
+		return nil, err
+	}
+	rc := zr.IOReadCloser()
+	return &jsonStreamDecoder{Decoder: json.NewDecoder(rc), closer: rc}, nil
+}
+
+// pipeEncode compresses r through w into pw as the other end of the pipe is read, so the
+// body is never held in memory. The source is closed once consumed and release gives w back
+// to its pool. Closing the reading end aborts the copy.
+func pipeEncode(pw *io.PipeWriter, w io.WriteCloser, r io.Reader, release func()) {
+	defer release()
+
+	_, err := copyZeroAlloc(w, r)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if rc, ok := r.(io.Closer); ok {
+		_ = rc.Close()
+	}
+	_ = pw.CloseWithError(err)
+}
+
 var copyBufPool = sync.Pool{
 	New: func() interface{} {
 		return make([]byte, 4096)
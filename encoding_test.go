@@ -6,10 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"sync"
 	"testing"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,6 +35,10 @@ func (m *mockEncoder) Encode(r io.Reader) (io.ReadCloser, error) {
 	return nil, nil
 }
 
+func (m *mockEncoder) EncodeStream(r io.Reader) (io.ReadCloser, error) {
+	return nil, nil
+}
+
 func (m *mockEncoder) Decode(data []byte, v interface{}) error {
 	msg, ok := v.(*meilisearchApiError)
 	if !ok {
@@ -90,6 +97,7 @@ func Test_Encode_ErrorInCopyZeroAlloc(t *testing.T) {
 	g := newEncoding(GzipEncoding, DefaultCompression)
 	d := newEncoding(DeflateEncoding, DefaultCompression)
 	b := newEncoding(BrotliEncoding, DefaultCompression)
+	z := newEncoding(ZstdEncoding, DefaultCompression)
 
 	_, err := g.Encode(&errorReader{})
 	require.Error(t, err)
@@ -97,6 +105,8 @@ func Test_Encode_ErrorInCopyZeroAlloc(t *testing.T) {
 	require.Error(t, err)
 	_, err = b.Encode(&errorReader{})
 	require.Error(t, err)
+	_, err = z.Encode(&errorReader{})
+	require.Error(t, err)
 }
 
 func Test_InvalidContentType(t *testing.T) {
@@ -141,10 +151,61 @@ func TestBrotliEncoder(t *testing.T) {
 	testEncoder(t, newEncoding(BrotliEncoding, DefaultCompression), &mockData{Name: "Jane Doe", Age: 25})
 }
 
+func TestZstdEncoder(t *testing.T) {
+	for _, level := range []EncodingCompressionLevel{DefaultCompression, NoCompression, BestSpeed, 5, 7, BestCompression} {
+		testEncoder(t, newEncoding(ZstdEncoding, level), &mockData{Name: "Jane Doe", Age: 25})
+	}
+}
+
+func TestZstdLevel(t *testing.T) {
+	require.Equal(t, zstd.SpeedDefault, zstdLevel(DefaultCompression))
+	require.Equal(t, zstd.SpeedFastest, zstdLevel(StatelessCompression))
+	require.Equal(t, zstd.SpeedFastest, zstdLevel(BestSpeed))
+	require.Equal(t, zstd.SpeedDefault, zstdLevel(5))
+	require.Equal(t, zstd.SpeedBetterCompression, zstdLevel(6))
+	require.Equal(t, zstd.SpeedBestCompression, zstdLevel(BestCompression))
+}
+
+func TestZstdDecodeAll_MaxDecodedSize(t *testing.T) {
+	// a single segment frame whose header declares 2 GiB of content, followed by an empty
+	// last raw block
+	frame := []byte{0x28, 0xb5, 0x2f, 0xfd, 0xe0, 0, 0, 0, 0x80, 0, 0, 0, 0, 0x01, 0, 0}
+	_, err := zstdDecodeAll(frame)
+	require.ErrorIs(t, err, zstd.ErrDecoderSizeExceeded)
+}
+
+func TestZstdEncoder_Stream(t *testing.T) {
+	enc := newEncoding(ZstdEncoding, DefaultCompression)
+	payload := strings.Repeat(`{"id":1,"title":"Le Petit Prince"}`+"\n", 1000)
+
+	compressed, err := enc.EncodeStream(io.NopCloser(strings.NewReader(payload)))
+	require.NoError(t, err)
+	data, err := io.ReadAll(compressed)
+	require.NoError(t, err)
+	require.NoError(t, compressed.Close())
+	require.Less(t, len(data), len(payload))
+
+	dec, err := enc.Decoder(bytes.NewReader(data))
+	require.NoError(t, err)
+	count := 0
+	for {
+		var doc mockData
+		if err := dec.Decode(&doc); err == io.EOF {
+			break
+		} else {
+			require.NoError(t, err)
+		}
+		count++
+	}
+	require.NoError(t, dec.Close())
+	require.Equal(t, 1000, count)
+}
+
 func TestEncoder_EmptyData(t *testing.T) {
 	testEncoder(t, newEncoding(GzipEncoding, DefaultCompression), &mockData{})
 	testEncoder(t, newEncoding(DeflateEncoding, DefaultCompression), &mockData{})
 	testEncoder(t, newEncoding(BrotliEncoding, DefaultCompression), &mockData{})
+	testEncoder(t, newEncoding(ZstdEncoding, DefaultCompression), &mockData{})
 }
 
 func TestEncoder_InvalidDecode(t *testing.T) {
@@ -152,6 +213,7 @@ func TestEncoder_InvalidDecode(t *testing.T) {
 		newEncoding(GzipEncoding, DefaultCompression),
 		newEncoding(DeflateEncoding, DefaultCompression),
 		newEncoding(BrotliEncoding, DefaultCompression),
+		newEncoding(ZstdEncoding, DefaultCompression),
 	}
 	for _, enc := range encoders {
 		var decoded mockData
@@ -225,3 +287,50 @@ func TestCopyZeroAlloc(t *testing.T) {
 		mu.Unlock()
 	})
 }
+
+func TestCompressionThresholds(t *testing.T) {
+	encodings := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+		_, err := decompressBody(r.Header.Get("Content-Encoding"), mustReadAll(t, r.Body))
+		require.NoError(t, err)
+		encodings <- r.Header.Get("Content-Encoding")
+
+		compressed, err := newEncoding(GzipEncoding, DefaultCompression).Encode(strings.NewReader(`{"taskUid":1}`))
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write(mustReadAll(t, compressed))
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL,
+		WithContentEncoding(GzipEncoding, DefaultCompression),
+		WithCompressionThresholds(CompressionThresholds{JSON: 256, NDJSON: -1, CSV: 0}))
+	idx := sv.Index("movies")
+
+	_, err := idx.AddDocuments([]map[string]string{{"id": "1"}}, nil)
+	require.NoError(t, err)
+	require.Empty(t, <-encodings, "a small JSON body is sent as is")
+
+	_, err = idx.AddDocuments([]map[string]string{{"id": "1", "overview": strings.Repeat("a", 256)}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "gzip", <-encodings)
+
+	_, err = idx.AddDocumentsNdjson([]byte(`{"id":"1","overview":"`+strings.Repeat("a", 1024)+`"}`), nil)
+	require.NoError(t, err)
+	require.Empty(t, <-encodings, "NDJSON is never compressed")
+
+	_, err = idx.AddDocumentsNdjsonFromReader(strings.NewReader(`{"id":"1"}`), nil)
+	require.NoError(t, err)
+	require.Empty(t, <-encodings, "NDJSON is never compressed, even streamed")
+
+	_, err = idx.AddDocumentsCsv([]byte("id\n1\n"), nil)
+	require.NoError(t, err)
+	require.Equal(t, "gzip", <-encodings, "CSV is always compressed")
+
+	_, err = idx.AddDocumentsCsvFromReader(io.MultiReader(strings.NewReader("id\n1\n")), nil)
+	require.NoError(t, err)
+	require.Equal(t, "gzip", <-encodings, "a streamed body of unknown size is compressed")
+}
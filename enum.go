@@ -88,6 +88,7 @@ const (
 	GzipEncoding    ContentEncoding = "gzip"
 	DeflateEncoding ContentEncoding = "deflate"
 	BrotliEncoding  ContentEncoding = "br"
+	ZstdEncoding    ContentEncoding = "zstd"
 
 	NoCompression          EncodingCompressionLevel = 0
 	BestSpeed              EncodingCompressionLevel = 1
@@ -1,9 +1,11 @@
 package meilisearch
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 )
 
@@ -26,6 +28,12 @@ const (
 	CommunicationError
 	// MaxRetriesExceeded used max retries and exceeded
 	MaxRetriesExceeded
+	// CircuitBreakerOpen the request was not sent because the circuit breaker of the host is open
+	CircuitBreakerOpen
+	// TaskFailed a task failed while Meilisearch was processing it, see Task.Err
+	TaskFailed
+	// TaskCanceled a task was canceled before Meilisearch was done processing it
+	TaskCanceled
 )
 
 const (
@@ -37,6 +45,9 @@ const (
 	rawStringMeilisearchTimeoutError       = `MeilisearchTimeoutError`
 	rawStringMeilisearchCommunicationError = `MeilisearchCommunicationError unable to execute request`
 	rawStringMeilisearchMaxRetriesExceeded = "failed to request and max retries exceeded"
+	rawStringCircuitBreakerOpen            = "circuit breaker is open, request not sent"
+	rawStringTaskFailed                    = `task failed, APIError Message: ${message}, Code: ${code}, Type: ${type}, Link: ${link} (path "${method} ${endpoint}")`
+	rawStringTaskCanceled                  = `task canceled (path "${method} ${endpoint}")`
 )
 
 func (e ErrCode) rawMessage() string {
@@ -55,14 +66,58 @@ func (e ErrCode) rawMessage() string {
 		return rawStringMeilisearchCommunicationError + " " + rawStringCtx
 	case MaxRetriesExceeded:
 		return rawStringMeilisearchMaxRetriesExceeded + " " + rawStringCtx
+	case CircuitBreakerOpen:
+		return rawStringCircuitBreakerOpen + " " + rawStringCtx
+	case TaskFailed:
+		return rawStringTaskFailed
+	case TaskCanceled:
+		return rawStringTaskCanceled
 	default:
 		return rawStringCtx
 	}
 }
 
+// String returns a short snake_case name of the error code, suitable for metric labels.
+func (e ErrCode) String() string {
+	switch e {
+	case ErrCodeMarshalRequest:
+		return "marshal_request"
+	case ErrCodeResponseUnmarshalBody:
+		return "response_unmarshal_body"
+	case APIError:
+		return "api_error"
+	case APIErrorWithoutMessage:
+		return "api_error_without_message"
+	case TimeoutError:
+		return "timeout"
+	case CommunicationError:
+		return "communication"
+	case MaxRetriesExceeded:
+		return "max_retries_exceeded"
+	case CircuitBreakerOpen:
+		return "circuit_breaker_open"
+	case TaskFailed:
+		return "task_failed"
+	case TaskCanceled:
+		return "task_canceled"
+	default:
+		return "unknown"
+	}
+}
+
 // APIErrCode represents Meilisearch API error codes returned by the Meilisearch server.
+//
+// An APIErrCode is an error, every code can be matched with errors.Is against the errors
+// returned by the client:
+//
+//	if errors.Is(err, meilisearch.APIErrCodeIndexNotFound) { ... }
 type APIErrCode string
 
+// Error returns the code itself.
+func (c APIErrCode) Error() string {
+	return string(c)
+}
+
 const (
 	// APIErrCodeAPIKeyAlreadyExists A key with this uid already exists.
 	APIErrCodeAPIKeyAlreadyExists APIErrCode = "api_key_already_exists"
@@ -471,9 +526,24 @@ type Error struct {
 	// an error.
 	ErrCode ErrCode
 
+	// dump is the request behind the error, nil unless WithRequestDump is used
+	dump *RequestDump
+
 	encoder
 }
 
+// newCallError returns the *Error of a call that failed without a request or a response to
+// report, to be completed with WithErrCode.
+func newCallError(function, method, endpoint string) *Error {
+	return &Error{
+		Endpoint:         endpoint,
+		Method:           method,
+		Function:         function,
+		RequestToString:  "empty request",
+		ResponseToString: "empty response",
+	}
+}
+
 // Error return a well human formatted message.
 func (e *Error) Error() string {
 	message := namedSprintf(e.rawMessage, map[string]interface{}{
@@ -501,6 +571,26 @@ func (e *Error) Unwrap() error {
 	return e.OriginError
 }
 
+// Is reports whether target is the APIErrCode of the error, which makes errors.Is work
+// with the APIErrCode constants and the Err sentinels.
+func (e *Error) Is(target error) bool {
+	code, ok := target.(APIErrCode)
+	return ok && code != "" && e.APIError.Code == code
+}
+
+// Dump returns the HTTP request behind the error, with the last response received for it, as
+// captured by WithRequestDump. It returns nil when the client does not use WithRequestDump or
+// when the call failed before its request was built, the methods of a nil *RequestDump return
+// an empty string:
+//
+//	var meiliErr *meilisearch.Error
+//	if errors.As(err, &meiliErr) {
+//		fmt.Println(meiliErr.Dump().Curl())
+//	}
+func (e *Error) Dump() *RequestDump {
+	return e.dump
+}
+
 // HasCode returns true if the Meilisearch API error code matches the given code.
 func (e *Error) HasCode(code APIErrCode) bool {
 	return e.APIError.Code == code
@@ -561,6 +651,132 @@ var (
 	ErrRequestBodyWithoutContentType = errors.New("request body without Content-Type is not allowed")
 	ErrNoSearchRequest               = errors.New("no search request provided")
 	ErrNoFacetSearchRequest          = errors.New("no search facet request provided")
+	ErrNoTaskDocumentFunc            = errors.New("no task document function provided")
+	ErrTaskNotBatched                = errors.New("task is not in a batch yet")
+	ErrNoMorePages                   = errors.New("no more pages")
 	ErrConnectingFailed              = errors.New("meilisearch is not connected")
 	ErrMeilisearchNotAvailable       = errors.New("meilisearch service is not available")
 )
+
+// Sentinels of the most common Meilisearch API errors, to be used with errors.Is. Any other
+// APIErrCode can be used the same way.
+const (
+	ErrIndexNotFound              = APIErrCodeIndexNotFound
+	ErrIndexAlreadyExists         = APIErrCodeIndexAlreadyExists
+	ErrDocumentNotFound           = APIErrCodeDocumentNotFound
+	ErrTaskNotFound               = APIErrCodeTaskNotFound
+	ErrBatchNotFound              = APIErrCodeBatchNotFound
+	ErrAPIKeyNotFound             = APIErrCodeAPIKeyNotFound
+	ErrAPIKeyAlreadyExists        = APIErrCodeAPIKeyAlreadyExists
+	ErrWebhookNotFound            = APIErrCodeWebhookNotFound
+	ErrInvalidAPIKey              = APIErrCodeInvalidAPIKey
+	ErrMissingAuthorizationHeader = APIErrCodeMissingAuthorizationHeader
+	ErrFeatureNotEnabled          = APIErrCodeFeatureNotEnabled
+	ErrPayloadTooLarge            = APIErrCodePayloadTooLarge
+	ErrTooManySearchRequests      = APIErrCodeTooManySearchRequests
+	ErrInternal                   = APIErrCodeInternal
+)
+
+// notFoundCodes are the API error codes telling a resource does not exist.
+var notFoundCodes = map[APIErrCode]bool{
+	APIErrCodeNotFound:          true,
+	APIErrCodeIndexNotFound:     true,
+	APIErrCodeDocumentNotFound:  true,
+	APIErrCodeTaskNotFound:      true,
+	APIErrCodeBatchNotFound:     true,
+	APIErrCodeAPIKeyNotFound:    true,
+	APIErrCodeWebhookNotFound:   true,
+	APIErrCodeNotFoundSimilarID: true,
+}
+
+// authCodes are the API error codes telling the request is not authorized.
+var authCodes = map[APIErrCode]bool{
+	APIErrCodeMissingAuthorizationHeader: true,
+	APIErrCodeInvalidAPIKey:              true,
+	APIErrCodeMissingMasterKey:           true,
+}
+
+// retryableCodes are the API error codes of failures that may not happen again.
+var retryableCodes = map[APIErrCode]bool{
+	APIErrCodeTooManySearchRequests:     true,
+	APIErrCodeRemoteTimeout:             true,
+	APIErrCodeRemoteCouldNotSendRequest: true,
+	APIErrCodeRemoteRemoteError:         true,
+}
+
+// IsRetryable reports whether err is a transient failure the same call may not meet again:
+// a transport error, an open circuit breaker, a 429 or 5xx answer other than 501, or an API
+// error telling Meilisearch or a remote is overloaded. Retries exhausted are retryable when
+// the last attempt failed that way. A call canceled or past the deadline of its context is not.
+func IsRetryable(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	switch e.ErrCode {
+	case CommunicationError, CircuitBreakerOpen:
+		return true
+	case TimeoutError:
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	case MaxRetriesExceeded:
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		if e.StatusCode == 0 {
+			// the last attempt failed at the transport level
+			return e.OriginError != nil && isTransientNetworkError(e.OriginError)
+		}
+	}
+	if retryableCodes[e.APIError.Code] {
+		return true
+	}
+	return e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode >= http.StatusInternalServerError && e.StatusCode != http.StatusNotImplemented
+}
+
+// IsNotFound reports whether err tells the requested resource does not exist.
+func IsNotFound(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return notFoundCodes[e.APIError.Code] || e.StatusCode == http.StatusNotFound
+}
+
+// IsAuth reports whether err tells the request was rejected for a missing or invalid API key,
+// or a key lacking the permission.
+func IsAuth(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return authCodes[e.APIError.Code] || e.APIError.Type == "auth" ||
+		e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// IsClientSide reports whether err comes from a request the caller has to fix before sending it
+// again: a body that could not be serialized, an API error of type invalid_request or auth, or a
+// 4xx answer other than 429.
+func IsClientSide(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	if e.ErrCode == ErrCodeMarshalRequest {
+		return true
+	}
+	switch e.APIError.Type {
+	case "invalid_request", "auth":
+		return true
+	case "internal", "system":
+		return false
+	}
+	return e.StatusCode >= http.StatusBadRequest && e.StatusCode < http.StatusInternalServerError &&
+		e.StatusCode != http.StatusTooManyRequests
+}
+
+// IsRemote reports whether err comes from a remote of the network, one of the remote_* codes.
+func IsRemote(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && strings.HasPrefix(string(e.APIError.Code), "remote_")
+}
@@ -1,9 +1,12 @@
 package meilisearch
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -54,6 +57,9 @@ type failEncoder struct{}
 func (f *failEncoder) Encode(r io.Reader) (io.ReadCloser, error) {
 	return nil, nil
 }
+func (f *failEncoder) EncodeStream(r io.Reader) (io.ReadCloser, error) {
+	return nil, nil
+}
 func (f *failEncoder) Decode(_ []byte, v interface{}) error {
 	return fmt.Errorf("decode failed")
 }
@@ -108,3 +114,84 @@ func TestError_UnwrapAndHasCode(t *testing.T) {
 	require.True(t, err.HasCode(APIErrCodeIndexNotFound))
 	require.False(t, err.HasCode(APIErrCodeAPIKeyNotFound))
 }
+
+func TestError_Is(t *testing.T) {
+	err := fmt.Errorf("fetch movies: %w", &Error{
+		APIError:   APIErrorDetails{Code: APIErrCodeIndexNotFound, Type: "invalid_request"},
+		StatusCode: http.StatusNotFound,
+	})
+
+	require.ErrorIs(t, err, ErrIndexNotFound)
+	require.ErrorIs(t, err, APIErrCodeIndexNotFound)
+	require.NotErrorIs(t, err, ErrDocumentNotFound)
+	require.NotErrorIs(t, &Error{}, APIErrCode(""))
+	require.Equal(t, "index_not_found", ErrIndexNotFound.Error())
+}
+
+func TestError_Classifiers(t *testing.T) {
+	apiErr := func(status int, code APIErrCode, typ string) error {
+		return (&Error{StatusCode: status, APIError: APIErrorDetails{Code: code, Type: typ}}).WithErrCode(APIError)
+	}
+
+	tests := []struct {
+		name                                              string
+		err                                               error
+		retryable, notFound, auth, clientSide, remoteSide bool
+	}{
+		{name: "not an *Error", err: errors.New("boom")},
+		{name: "index not found", err: apiErr(http.StatusNotFound, APIErrCodeIndexNotFound, "invalid_request"), notFound: true, clientSide: true},
+		{name: "invalid api key", err: apiErr(http.StatusForbidden, APIErrCodeInvalidAPIKey, "auth"), auth: true, clientSide: true},
+		{name: "missing authorization header", err: apiErr(http.StatusUnauthorized, APIErrCodeMissingAuthorizationHeader, "auth"), auth: true, clientSide: true},
+		{name: "too many search requests", err: apiErr(http.StatusServiceUnavailable, APIErrCodeTooManySearchRequests, "system"), retryable: true},
+		{name: "internal", err: apiErr(http.StatusInternalServerError, APIErrCodeInternal, "internal"), retryable: true},
+		{name: "remote timeout", err: apiErr(http.StatusBadGateway, APIErrCodeRemoteTimeout, "system"), retryable: true, remoteSide: true},
+		{name: "remote invalid api key", err: apiErr(http.StatusForbidden, APIErrCodeRemoteInvalidAPIKey, "system"), auth: true, remoteSide: true},
+		{name: "rate limited", err: (&Error{StatusCode: http.StatusTooManyRequests}).WithErrCode(APIErrorWithoutMessage), retryable: true},
+		{name: "not implemented", err: (&Error{StatusCode: http.StatusNotImplemented}).WithErrCode(APIErrorWithoutMessage)},
+		{name: "communication", err: (&Error{}).WithErrCode(CommunicationError, io.ErrUnexpectedEOF), retryable: true},
+		{name: "max retries", err: (&Error{StatusCode: http.StatusBadGateway}).WithErrCode(MaxRetriesExceeded), retryable: true},
+		{name: "max retries on a connection reset", err: (&Error{}).WithErrCode(MaxRetriesExceeded, syscall.ECONNRESET), retryable: true},
+		{name: "max retries on a non transient error", err: (&Error{}).WithErrCode(MaxRetriesExceeded, errors.New("tls: bad certificate"))},
+		{name: "max retries past the deadline", err: (&Error{}).WithErrCode(MaxRetriesExceeded, context.DeadlineExceeded)},
+		{name: "deadline", err: (&Error{}).WithErrCode(TimeoutError, context.DeadlineExceeded)},
+		{name: "canceled", err: (&Error{}).WithErrCode(TimeoutError, context.Canceled)},
+		{name: "marshal request", err: (&Error{}).WithErrCode(ErrCodeMarshalRequest), clientSide: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.retryable, IsRetryable(tt.err), "IsRetryable")
+			require.Equal(t, tt.notFound, IsNotFound(tt.err), "IsNotFound")
+			require.Equal(t, tt.auth, IsAuth(tt.err), "IsAuth")
+			require.Equal(t, tt.clientSide, IsClientSide(tt.err), "IsClientSide")
+			require.Equal(t, tt.remoteSide, IsRemote(tt.err), "IsRemote")
+		})
+	}
+}
+
+func TestTask_Err(t *testing.T) {
+	require.NoError(t, (&Task{UID: 1, Status: TaskStatusSucceeded}).Err())
+
+	task := &Task{
+		UID:    12,
+		Status: TaskStatusFailed,
+		Error: APIErrorDetails{
+			Message: "Index `movies` not found.",
+			Code:    APIErrCodeIndexNotFound,
+			Type:    "invalid_request",
+			Link:    "https://docs.meilisearch.com/errors#index_not_found",
+		},
+	}
+	err := task.Err()
+	require.ErrorIs(t, err, ErrIndexNotFound)
+	require.True(t, IsNotFound(err))
+	require.True(t, IsClientSide(err))
+	require.False(t, IsRetryable(err))
+
+	var meiliErr *Error
+	require.ErrorAs(t, err, &meiliErr)
+	require.Equal(t, TaskFailed, meiliErr.ErrCode)
+	require.Equal(t, "task_failed", meiliErr.ErrCode.String())
+	require.Equal(t, "task failed, APIError Message: Index `movies` not found., Code: index_not_found, "+
+		"Type: invalid_request, Link: https://docs.meilisearch.com/errors#index_not_found (path \"GET /tasks/12\")", err.Error())
+}
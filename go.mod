@@ -5,6 +5,7 @@ go 1.21
 require (
 	github.com/andybalholm/brotli v1.1.1
 	github.com/golang-jwt/jwt/v5 v5.3.1
+	github.com/klauspost/compress v1.17.11
 	github.com/stretchr/testify v1.11.1
 )
 
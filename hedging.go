@@ -0,0 +1,139 @@
+package meilisearch
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// hedgedFunctions are the SDK functions eligible to hedging. They are read-only, so sending
+// them twice is harmless, unlike writes which would enqueue duplicate tasks.
+var hedgedFunctions = map[string]bool{
+	"Search":      true,
+	"MultiSearch": true,
+}
+
+// HedgingConfig configures hedged search requests, see WithHedging.
+type HedgingConfig struct {
+	// Delay after which another attempt is sent if none has answered yet, typically the
+	// p95 latency of the search route.
+	Delay time.Duration
+	// MaxAttempts is the maximum number of concurrent attempts of a call, default 2.
+	MaxAttempts int
+}
+
+// hedgingFor returns the hedging configuration of a call, the one of its CallOptions if any or
+// the one of the client, nil when the call is not hedged.
+func (c *client) hedgingFor(ctx context.Context) *HedgingConfig {
+	co := callOptionsFromContext(ctx)
+	switch {
+	case co == nil:
+		return c.hedging
+	case co.disableHedging:
+		return nil
+	case co.hedging != nil:
+		return co.hedging
+	default:
+		return c.hedging
+	}
+}
+
+type hedgeResult struct {
+	attempt *internalRequest
+	err     error
+}
+
+// executeHedged sends identical attempts of req, a new one every Delay or as soon as one
+// fails with a retryable error, until one succeeds. A non-retryable error, such as a 400, is
+// returned at once. The winner's response is copied into req and the other attempts
+// are canceled through their context.
+func (c *client) executeHedged(ctx context.Context, req *internalRequest, cfg *HedgingConfig) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 2
+	}
+
+	results := make(chan hedgeResult, maxAttempts)
+	responseType := reflect.TypeOf(req.withResponse).Elem()
+
+	var hedge <-chan time.Time
+	launched, pending := 0, 0
+	launch := func() {
+		attempt := *req
+		attempt.stats = requestStats{}
+		// every attempt decodes into its own value, the winner is copied afterwards
+		attempt.withResponse = reflect.New(responseType).Interface()
+		launched++
+		pending++
+		if launched < maxAttempts {
+			hedge = time.After(cfg.Delay)
+		} else {
+			hedge = nil
+		}
+		extra := launched > 1
+		go func() {
+			if extra {
+				// the first attempt holds the slot taken by the rate limit middleware, every
+				// other one takes its own so MaxInFlight bounds the requests actually sent
+				release, err := c.acquireHedgeSlot(ctx, &attempt)
+				if err != nil {
+					results <- hedgeResult{attempt: &attempt, err: err}
+					return
+				}
+				defer release()
+			}
+			results <- hedgeResult{attempt: &attempt, err: c.executeOnce(ctx, &attempt)}
+		}()
+	}
+
+	launch()
+
+	var firstErr error
+	for pending > 0 {
+		select {
+		case <-hedge:
+			launch()
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				if r.attempt.withResponse == nil {
+					req.withResponse = nil
+				} else {
+					reflect.ValueOf(req.withResponse).Elem().Set(reflect.ValueOf(r.attempt.withResponse).Elem())
+				}
+				req.stats = r.attempt.stats
+				return nil
+			}
+			if !IsRetryable(r.err) {
+				// another attempt would get the same answer, the others are canceled on return
+				req.stats = r.attempt.stats
+				return r.err
+			}
+			if firstErr == nil {
+				firstErr = r.err
+				req.stats = r.attempt.stats
+			}
+			if launched < maxAttempts && ctx.Err() == nil {
+				launch()
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// acquireHedgeSlot takes a slot and a token of the limiter of the operation class of req when
+// WithRateLimits is used.
+func (c *client) acquireHedgeSlot(ctx context.Context, req *internalRequest) (func(), error) {
+	if c.limiters == nil {
+		return func() {}, nil
+	}
+	release, err := c.limiters[operationClassOf(req.functionName)].acquire(ctx)
+	if err != nil {
+		return nil, newCallError(req.functionName, req.method, req.endpoint).WithErrCode(TimeoutError, err)
+	}
+	return release, nil
+}
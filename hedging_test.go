@@ -0,0 +1,143 @@
+package meilisearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedging_SecondAttemptWins(t *testing.T) {
+	var requests atomic.Int32
+	canceled := make(chan struct{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		if requests.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+				canceled <- struct{}{}
+			case <-time.After(2 * time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte(`{"hits":[{"id":1}],"query":"hedged"}`))
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL, WithHedging(HedgingConfig{Delay: 20 * time.Millisecond}))
+
+	start := time.Now()
+	resp, err := sv.Index("movies").Search("hedged", &SearchRequest{})
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, "hedged", resp.Query)
+	require.Len(t, resp.Hits, 1)
+	require.Equal(t, int32(2), requests.Load())
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("the losing attempt was not canceled")
+	}
+}
+
+func TestHedging_FailedAttemptIsReplaced(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"boom","code":"internal","type":"internal","link":""}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[{"indexUid":"movies","hits":[]}]}`))
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL, WithHedging(HedgingConfig{Delay: time.Hour}))
+
+	resp, err := sv.MultiSearch(&MultiSearchRequest{Queries: []*SearchRequest{{IndexUID: "movies"}}})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	require.Equal(t, int32(2), requests.Load())
+}
+
+func TestHedging_ClientErrorIsNotReplaced(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"Invalid filter","code":"invalid_search_filter","type":"invalid_request","link":""}`))
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL, WithHedging(HedgingConfig{Delay: time.Hour, MaxAttempts: 3}))
+
+	_, err := sv.Index("movies").Search("", &SearchRequest{Filter: "genre ="})
+	require.ErrorIs(t, err, APIErrCodeInvalidSearchFilter)
+	require.Equal(t, int32(1), requests.Load())
+}
+
+func TestHedging_WritesAreNotHedged(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"taskUid":1}`))
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL, WithHedging(HedgingConfig{Delay: time.Millisecond}))
+
+	_, err := sv.Index("movies").AddDocuments([]map[string]interface{}{{"id": 1}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), requests.Load())
+}
+
+func TestHedging_AttemptsHonourMaxInFlight(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"hits":[]}`))
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL,
+		WithHedging(HedgingConfig{Delay: time.Millisecond, MaxAttempts: 3}),
+		WithRateLimits(RateLimits{Search: Limit{MaxInFlight: 1}}))
+
+	_, err := sv.Index("movies").Search("", &SearchRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), requests.Load(), "the hedged attempts wait for the slot of the first one")
+}
+
+func TestHedging_CallOptions(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"hits":[]}`))
+	}))
+	defer ts.Close()
+
+	hedged := WithCallOptions(context.Background(), CallWithHedging(HedgingConfig{Delay: time.Millisecond}))
+	_, err := New(ts.URL).Index("movies").SearchWithContext(hedged, "", &SearchRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), requests.Load(), "the call is hedged by its options")
+
+	requests.Store(0)
+	sv := New(ts.URL, WithHedging(HedgingConfig{Delay: time.Millisecond}))
+	once := WithCallOptions(hedged, CallWithoutHedging())
+	_, err = sv.MultiSearchWithContext(once, &MultiSearchRequest{Queries: []*SearchRequest{{IndexUID: "movies"}}})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), requests.Load(), "the call opts out of the hedging of the client")
+}
@@ -23,6 +23,10 @@ func (i *index) GetTaskReader() TaskReader {
 	return i
 }
 
+func (i *index) TaskHandle(taskInfo *TaskInfo) TaskHandle {
+	return newTaskHandle(i.client, taskInfo)
+}
+
 func (i *index) GetDocumentManager() DocumentManager {
 	return i
 }
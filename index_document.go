@@ -160,13 +160,8 @@ func (i *index) AddDocumentsNdjsonFromReader(documents io.Reader, opts *Document
 }
 
 func (i *index) AddDocumentsNdjsonFromReaderWithContext(ctx context.Context, documents io.Reader, opts *DocumentOptions) (resp *TaskInfo, err error) {
-	// Using io.Reader would avoid JSON conversion in Client.sendRequest(), but
-	// read content to memory anyway because of problems with streamed bodies
-	data, err := io.ReadAll(documents)
-	if err != nil {
-		return nil, fmt.Errorf("could not read documents: %w", err)
-	}
-	return i.addDocuments(ctx, data, contentTypeNDJSON, transformDocumentOptionsToMap(opts))
+	// The documents are streamed, see ReopenableReader to make the upload retryable
+	return i.addDocumentsFromReader(ctx, documents, contentTypeNDJSON, transformDocumentOptionsToMap(opts))
 }
 
 func (i *index) UpdateDocuments(documentsPtr interface{}, opts *DocumentOptions) (*TaskInfo, error) {
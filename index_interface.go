@@ -21,6 +21,10 @@ type IndexManager interface {
 	GetSettingsReader() SettingsReader
 	GetSearch() SearchReader
 
+	// TaskHandle binds the task summary returned by a write to the client, to wait for, cancel
+	// or inspect the task without threading the IndexManager around.
+	TaskHandle(taskInfo *TaskInfo) TaskHandle
+
 	// UpdateIndex updates the primary key of the index.
 	//
 	// docs: https://www.meilisearch.com/docs/reference/api/indexes/update-index
@@ -134,6 +138,7 @@ type DocumentManager interface {
 	AddDocumentsCsvFromReaderInBatchesWithContext(ctx context.Context, documents io.Reader, batchSize int, options *CsvDocumentsQuery) ([]TaskInfo, error)
 
 	// AddDocumentsCsvFromReader adds documents from a CSV reader to the index.
+	// The reader is streamed, the upload is only retried when it is a ReopenableReader.
 	//
 	// docs: https://www.meilisearch.com/docs/reference/api/documents/add-or-replace-documents
 	AddDocumentsCsvFromReader(documents io.Reader, options *CsvDocumentsQuery) (*TaskInfo, error)
@@ -164,6 +169,7 @@ type DocumentManager interface {
 	AddDocumentsNdjsonInBatchesWithContext(ctx context.Context, documents []byte, batchSize int, opts *DocumentOptions) ([]TaskInfo, error)
 
 	// AddDocumentsNdjsonFromReader adds documents from a NDJSON reader to the index.
+	// The reader is streamed, the upload is only retried when it is a ReopenableReader.
 	//
 	// docs: https://www.meilisearch.com/docs/reference/api/documents/add-or-replace-documents
 	AddDocumentsNdjsonFromReader(documents io.Reader, opts *DocumentOptions) (*TaskInfo, error)
@@ -77,3 +77,11 @@ func (i *index) WaitForTask(taskUID int64, interval time.Duration) (*Task, error
 func (i *index) WaitForTaskWithContext(ctx context.Context, taskUID int64, interval time.Duration) (*Task, error) {
 	return waitForTask(ctx, i.client, taskUID, interval)
 }
+
+func (i *index) WaitForTaskWithOptions(ctx context.Context, taskUID int64, opts *WaitOptions) (*Task, error) {
+	return waitForTaskWithOptions(ctx, i.client, taskUID, opts)
+}
+
+func (i *index) WaitForTasks(ctx context.Context, taskUIDs []int64, opts *WaitOptions) (map[int64]*Task, error) {
+	return waitForTasks(ctx, i.client, taskUIDs, opts)
+}
@@ -4,13 +4,16 @@ import (
 	"bufio"
 	"encoding/csv"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/meilisearch/meilisearch-go"
+	"github.com/meilisearch/meilisearch-go/meilisearchtest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -77,6 +80,44 @@ type docTestBooks struct {
 	Year   int    `json:"year"`
 }
 
+// fakeSkips are the tests relying on features the fake server of meilisearchtest does not serve:
+// embedders, chats, webhooks, network, dynamic search rules, exact ranking scores and sizes.
+var fakeSkips = []string{
+	`Test_.*Chat.*`,
+	`Test_.*Network`,
+	`Test_.*SearchRule`,
+	`Test_.*Webhooks?`,
+	`TestIndex_UpdateDocumentsByFunction`,
+	`TestIndex_DocumentOperationsWithCustomMetadata`,
+	`TestIndex_SearchSimilarDocuments`,
+	`TestIndex_UpdateEmbedders`,
+	`TestIndex_GetStats`,
+	`TestClient_MultiSearch`,
+	`TestExport`,
+	`TestRenderTemplate`,
+}
+
+// TestMain runs the tests against the fake server of meilisearchtest instead of a Meilisearch
+// instance when MEILISEARCH_FAKE is set.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if os.Getenv("MEILISEARCH_FAKE") == "" {
+		os.Exit(m.Run())
+	}
+
+	srv := meilisearchtest.NewServer(
+		meilisearchtest.WithMasterKey(masterKey),
+		meilisearchtest.WithTaskDelay(5*time.Millisecond),
+	)
+	_ = os.Setenv("MEILISEARCH_URL", srv.URL)
+	if skip := flag.Lookup("test.skip"); skip != nil && skip.Value.String() == "" && len(fakeSkips) > 0 {
+		_ = flag.Set("test.skip", "^("+strings.Join(fakeSkips, "|")+")$")
+	}
+	code := m.Run()
+	srv.Close()
+	os.Exit(code)
+}
+
 func setup(t *testing.T, host string, options ...meilisearch.Option) meilisearch.ServiceManager {
 	t.Helper()
 
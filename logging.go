@@ -0,0 +1,285 @@
+package meilisearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	redactedValue       = "[REDACTED]"
+	defaultLogBodyLimit = 1024
+)
+
+// LogLevels sets the level of every kind of record emitted by the client logger.
+type LogLevels struct {
+	// Request is used when a request is sent, default slog.LevelDebug.
+	Request slog.Level
+	// Response is used when a response is received, default slog.LevelDebug.
+	Response slog.Level
+	// Retry is used when a failed attempt is retried after a backoff, default slog.LevelWarn.
+	Retry slog.Level
+	// Failure is used when a call returns an error, decoding failures included, default slog.LevelError.
+	Failure slog.Level
+}
+
+func defaultLogLevels() LogLevels {
+	return LogLevels{
+		Request:  slog.LevelDebug,
+		Response: slog.LevelDebug,
+		Retry:    slog.LevelWarn,
+		Failure:  slog.LevelError,
+	}
+}
+
+// redactedKeys are the JSON keys whose values are never logged, compared case-insensitively.
+var redactedKeys = map[string]bool{
+	"apikey":        true,
+	"searchapikey":  true,
+	"writeapikey":   true,
+	"key":           true,
+	"headers":       true,
+	"authorization": true,
+}
+
+// loggingMiddleware logs the outcome of every failed SDK call.
+func (c *client) loggingMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, info *RequestInfo) error {
+			start := time.Now()
+			err := next(ctx, info)
+			if err == nil || !c.logger.Enabled(ctx, c.logLevels.Failure) {
+				return err
+			}
+
+			attrs := []slog.Attr{
+				slog.String("function", info.Function),
+				slog.String("method", info.Method),
+				slog.String("endpoint", info.Endpoint),
+				slog.Duration("duration", time.Since(start)),
+				slog.Int("retries", int(info.req.stats.retries)),
+			}
+			if info.StatusCode != 0 {
+				attrs = append(attrs, slog.Int("status", info.StatusCode))
+			}
+
+			var meiliErr *Error
+			if errors.As(err, &meiliErr) {
+				attrs = append(attrs, slog.String("error_code", meiliErr.ErrCode.String()))
+				if meiliErr.APIError.Code != "" {
+					attrs = append(attrs, slog.String("api_error_code", string(meiliErr.APIError.Code)))
+				}
+				if meiliErr.ErrCode == ErrCodeResponseUnmarshalBody && meiliErr.ResponseToString != "empty response" {
+					attrs = append(attrs, slog.String("body", c.logBody([]byte(meiliErr.ResponseToString))))
+				}
+			}
+			attrs = append(attrs, slog.String("error", err.Error()))
+
+			c.logger.LogAttrs(ctx, c.logLevels.Failure, "meilisearch call failed", attrs...)
+			return err
+		}
+	}
+}
+
+func (c *client) logRequest(ctx context.Context, req *internalRequest, request *http.Request, body []byte) {
+	if c.logger == nil || !c.logger.Enabled(ctx, c.logLevels.Request) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("function", req.functionName),
+		slog.String("method", request.Method),
+		slog.String("url", request.URL.String()),
+		slog.Any("headers", redactHeaders(request.Header)),
+	}
+	if body != nil {
+		attrs = append(attrs, slog.Int("body_bytes", len(body)))
+		if req.stats.requestEncoding.IsZero() {
+			attrs = append(attrs, slog.String("body", c.logBody(body)))
+		}
+	}
+
+	c.logger.LogAttrs(ctx, c.logLevels.Request, "meilisearch request", attrs...)
+}
+
+func (c *client) logResponse(ctx context.Context, req *internalRequest, resp *http.Response, body []byte) {
+	if c.logger == nil || !c.logger.Enabled(ctx, c.logLevels.Response) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("function", req.functionName),
+		slog.Int("status", resp.StatusCode),
+		slog.Int("retries", int(req.stats.retries)),
+	}
+	if body != nil {
+		attrs = append(attrs, slog.Int("body_bytes", len(body)))
+		if resp.Header.Get("Content-Encoding") == "" {
+			attrs = append(attrs, slog.String("body", c.logBody(body)))
+		}
+	}
+
+	c.logger.LogAttrs(ctx, c.logLevels.Response, "meilisearch response", attrs...)
+}
+
+func (c *client) logRetry(ctx context.Context, request *http.Request, attempt uint8, statusCode int, backoff time.Duration) {
+	if c.logger == nil || !c.logger.Enabled(ctx, c.logLevels.Retry) {
+		return
+	}
+
+	c.logger.LogAttrs(ctx, c.logLevels.Retry, "meilisearch retry",
+		slog.String("method", request.Method),
+		slog.String("url", request.URL.String()),
+		slog.Int("attempt", int(attempt)),
+		slog.Int("status", statusCode),
+		slog.Duration("backoff", backoff),
+	)
+}
+
+// logBody redacts the secrets of a JSON body and truncates it to the configured limit.
+func (c *client) logBody(body []byte) string {
+	return truncateBody(redactJSON(body), c.logBodyLimit)
+}
+
+func truncateBody(body []byte, limit int) string {
+	if limit <= 0 || len(body) <= limit {
+		return string(body)
+	}
+	return string(body[:limit]) + "...(" + strconv.Itoa(len(body)-limit) + " bytes truncated)"
+}
+
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for key := range redacted {
+		if redactedKeys[strings.ToLower(key)] {
+			redacted.Set(key, redactedValue)
+		}
+	}
+	return redacted
+}
+
+// redactJSON replaces the values of redactedKeys in a JSON document. Bodies that are not
+// a single JSON value, such as NDJSON or CSV, are returned as is.
+func redactJSON(body []byte) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return body
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(trimmed, &v); err != nil {
+		return body
+	}
+	if !redactValue(v) {
+		return body
+	}
+
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactValue redacts v in place and reports whether anything was redacted.
+func redactValue(v interface{}) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if redactedKeys[strings.ToLower(k)] && child != nil {
+				val[k] = redactedValue
+				changed = true
+				continue
+			}
+			if redactValue(child) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, child := range val {
+			if redactValue(child) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// redactedLogValue renders v as JSON with its secrets redacted, it backs the slog.LogValuer
+// implementations of the types holding credentials.
+func redactedLogValue(v interface{}) slog.Value {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return slog.StringValue(redactedValue)
+	}
+	return slog.StringValue(string(redactJSON(b)))
+}
+
+// LogValue implements slog.LogValuer so the API key is never logged.
+func (t TenantTokenOptions) LogValue() slog.Value {
+	apiKey := ""
+	if t.APIKey != "" {
+		apiKey = redactedValue
+	}
+	return slog.GroupValue(
+		slog.String("APIKey", apiKey),
+		slog.Time("ExpiresAt", t.ExpiresAt),
+	)
+}
+
+// LogValue implements slog.LogValuer so the API key and headers are never logged.
+func (e Embedder) LogValue() slog.Value { return redactedLogValue(e) }
+
+// LogValue implements slog.LogValuer so the API key is never logged.
+func (c ChatWorkspaceSettings) LogValue() slog.Value { return redactedLogValue(c) }
+
+// LogValue implements slog.LogValuer so the API keys are never logged.
+func (r Remote) LogValue() slog.Value { return redactedLogValue(r) }
+
+// LogValue implements slog.LogValuer so the API keys are never logged.
+func (r UpdateRemote) LogValue() slog.Value { return redactedLogValue(r) }
+
+// LogValue implements slog.LogValuer so the API key is never logged.
+func (e ExportParams) LogValue() slog.Value { return redactedLogValue(e) }
+
+// LogValue implements slog.LogValuer so the headers are never logged.
+func (w Webhook) LogValue() slog.Value { return redactedLogValue(w) }
+
+// LogValue implements slog.LogValuer so the headers are never logged.
+func (w AddWebhookRequest) LogValue() slog.Value { return redactedLogValue(w) }
+
+// LogValue implements slog.LogValuer so the headers are never logged.
+func (w UpdateWebhookRequest) LogValue() slog.Value { return redactedLogValue(w) }
+
+// LogValue implements slog.LogValuer, the request and response bodies are redacted and
+// truncated so an *Error can be logged as is.
+func (e *Error) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("function", e.Function),
+		slog.String("method", e.Method),
+		slog.String("endpoint", e.Endpoint),
+		slog.Int("status", e.StatusCode),
+		slog.String("error_code", e.ErrCode.String()),
+	}
+	if e.APIError.Code != "" {
+		attrs = append(attrs,
+			slog.String("api_error_code", string(e.APIError.Code)),
+			slog.String("api_error_message", e.APIError.Message),
+		)
+	}
+	attrs = append(attrs,
+		slog.String("request", truncateBody(redactJSON([]byte(e.RequestToString)), defaultLogBodyLimit)),
+		slog.String("response", truncateBody(redactJSON([]byte(e.ResponseToString)), defaultLogBodyLimit)),
+	)
+	if e.OriginError != nil {
+		attrs = append(attrs, slog.String("origin_error", e.OriginError.Error()))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,135 @@
+package meilisearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogging_RequestResponseRetry(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"taskUid":1,"indexUid":"movies","status":"enqueued","type":"settingsUpdate"}`))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	sv := New(ts.URL, WithAPIKey("masterKey"), WithLogger(logger))
+	sv.(*meilisearch).client.retryBackoff = func(uint8) time.Duration { return 0 }
+
+	_, err := sv.Index("movies").UpdateEmbedders(map[string]Embedder{
+		"default": {Source: "openAi", APIKey: "sk-secret", Headers: map[string]string{"X-Token": "hidden"}},
+	})
+	require.NoError(t, err)
+
+	out := buf.String()
+	require.NotContains(t, out, "masterKey")
+	require.NotContains(t, out, "sk-secret")
+	require.NotContains(t, out, "hidden")
+
+	records := decodeLogRecords(t, &buf)
+	require.Len(t, records, 3)
+
+	require.Equal(t, "meilisearch request", records[0]["msg"])
+	require.Equal(t, "DEBUG", records[0]["level"])
+	require.Equal(t, "UpdateEmbedders", records[0]["function"])
+	require.Equal(t, []interface{}{redactedValue}, records[0]["headers"].(map[string]interface{})["Authorization"])
+	require.Contains(t, records[0]["body"], `"apiKey":"[REDACTED]"`)
+
+	require.Equal(t, "meilisearch retry", records[1]["msg"])
+	require.Equal(t, "WARN", records[1]["level"])
+	require.Equal(t, float64(http.StatusServiceUnavailable), records[1]["status"])
+	require.Equal(t, float64(1), records[1]["attempt"])
+
+	require.Equal(t, "meilisearch response", records[2]["msg"])
+	require.Equal(t, float64(http.StatusAccepted), records[2]["status"])
+	require.Equal(t, float64(1), records[2]["retries"])
+}
+
+func TestLogging_Failure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"uid":`))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	sv := New(ts.URL, WithLogger(logger), WithLogLevels(LogLevels{
+		Request:  slog.LevelDebug,
+		Response: slog.LevelDebug,
+		Retry:    slog.LevelInfo,
+		Failure:  slog.LevelWarn,
+	}))
+
+	_, err := sv.Index("movies").FetchInfo()
+	require.Error(t, err)
+
+	// request and response records are below the handler level
+	records := decodeLogRecords(t, &buf)
+	require.Len(t, records, 1)
+	require.Equal(t, "meilisearch call failed", records[0]["msg"])
+	require.Equal(t, "WARN", records[0]["level"])
+	require.Equal(t, "FetchInfo", records[0]["function"])
+	require.Equal(t, "response_unmarshal_body", records[0]["error_code"])
+	require.Equal(t, `{"uid":`, records[0]["body"])
+}
+
+func TestLogging_BodyLimit(t *testing.T) {
+	c := &client{logBodyLimit: 4}
+	require.Equal(t, "abcd...(2 bytes truncated)", c.logBody([]byte("abcdef")))
+	require.Equal(t, "abc", c.logBody([]byte("abc")))
+}
+
+func TestLogging_Redaction(t *testing.T) {
+	body := []byte(`{"remotes":{"ms-1":{"url":"http://ms-1","searchApiKey":"s3cr3t","writeApiKey":"w"}},"self":"ms-0"}`)
+	redacted := string(redactJSON(body))
+	require.NotContains(t, redacted, "s3cr3t")
+	require.Contains(t, redacted, `"url":"http://ms-1"`)
+
+	// not JSON, returned untouched
+	require.Equal(t, "id,name\n1,a", string(redactJSON([]byte("id,name\n1,a"))))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger.Info("values",
+		"token", TenantTokenOptions{APIKey: "tenant-key"},
+		"embedder", Embedder{Source: "rest", APIKey: "embedder-key"},
+		"chat", ChatWorkspaceSettings{Source: "openAi", ApiKey: "chat-key"},
+		"remote", Remote{URL: "http://ms-1", WriteAPIKey: "write-key"},
+		"webhook", AddWebhookRequest{URL: "http://hook", Headers: map[string]string{"Authorization": "hook-key"}},
+		"error", &Error{RequestToString: `{"apiKey":"export-key"}`},
+	)
+	out := buf.String()
+	for _, secret := range []string{"tenant-key", "embedder-key", "chat-key", "write-key", "hook-key", "export-key"} {
+		require.NotContains(t, out, secret)
+	}
+	require.Contains(t, out, "http://ms-1")
+}
@@ -33,8 +33,23 @@ func New(host string, options ...Option) ServiceManager {
 				disableRetry:             opts.disableRetry,
 				retryOnStatus:            opts.retryOnStatus,
 				maxRetries:               opts.maxRetries,
+				retryPolicy:              opts.retryPolicy,
+				hosts:                    opts.hosts,
+				healthProbeInterval:      opts.probeInterval,
+				circuitBreaker:           opts.circuitBreaker,
+				rateLimits:               opts.rateLimits,
+				hedging:                  opts.hedging,
+				apiKeyProvider:           opts.apiKeyProvider,
 				jsonMarshal:              opts.jsonMarshaler,
 				jsonUnmarshal:            opts.jsonUnmarshaler,
+				middlewares:              opts.middlewares,
+				tracer:                   opts.tracer,
+				metrics:                  opts.metrics,
+				logger:                   opts.logger,
+				logLevels:                opts.logLevels,
+				logBodyLimit:             opts.logBodyLimit,
+				requestDump:              opts.requestDump,
+				compressionThresholds:    opts.compression,
 			},
 		),
 	}
@@ -69,6 +84,10 @@ func (m *meilisearch) TaskReader() TaskReader {
 	return m
 }
 
+func (m *meilisearch) TaskHandle(taskInfo *TaskInfo) TaskHandle {
+	return newTaskHandle(m.client, taskInfo)
+}
+
 func (m *meilisearch) KeyManager() KeyManager {
 	return m
 }
@@ -376,23 +395,7 @@ func (m *meilisearch) GetTasks(param *TasksQuery) (*TaskResult, error) {
 }
 
 func (m *meilisearch) GetTasksWithContext(ctx context.Context, param *TasksQuery) (*TaskResult, error) {
-	resp := new(TaskResult)
-	req := &internalRequest{
-		endpoint:            "/tasks",
-		method:              http.MethodGet,
-		withRequest:         nil,
-		withResponse:        &resp,
-		withQueryParams:     map[string]string{},
-		acceptedStatusCodes: []int{http.StatusOK},
-		functionName:        "GetTasks",
-	}
-	if param != nil {
-		encodeTasksQuery(param, req)
-	}
-	if err := m.client.executeRequest(ctx, req); err != nil {
-		return nil, err
-	}
-	return resp, nil
+	return getTasks(ctx, m.client, param)
 }
 
 func (m *meilisearch) CancelTasks(param *CancelTasksQuery) (*TaskInfo, error) {
@@ -495,6 +498,14 @@ func (m *meilisearch) WaitForTaskWithContext(ctx context.Context, taskUID int64,
 	return waitForTask(ctx, m.client, taskUID, interval)
 }
 
+func (m *meilisearch) WaitForTaskWithOptions(ctx context.Context, taskUID int64, opts *WaitOptions) (*Task, error) {
+	return waitForTaskWithOptions(ctx, m.client, taskUID, opts)
+}
+
+func (m *meilisearch) WaitForTasks(ctx context.Context, taskUIDs []int64, opts *WaitOptions) (map[int64]*Task, error) {
+	return waitForTasks(ctx, m.client, taskUIDs, opts)
+}
+
 func (m *meilisearch) GenerateTenantToken(
 	apiKeyUID string,
 	searchRules map[string]interface{},
@@ -505,7 +516,16 @@ func (m *meilisearch) GenerateTenantToken(
 		return "", fmt.Errorf("GenerateTenantToken: The search rules added in the token generation " +
 			"must be of type array or object")
 	}
-	if (options == nil || options.APIKey == "") && m.client.apiKey == "" {
+	var secret string
+	if options != nil && options.APIKey != "" {
+		secret = options.APIKey
+	} else {
+		var err error
+		if secret, err = m.client.currentAPIKey(context.Background()); err != nil {
+			return "", fmt.Errorf("GenerateTenantToken: %w", err)
+		}
+	}
+	if secret == "" {
 		return "", fmt.Errorf("GenerateTenantToken: The API key used for the token " +
 			"generation must exist and be a valid meilisearch key")
 	}
@@ -518,13 +538,6 @@ func (m *meilisearch) GenerateTenantToken(
 			"the token generation has a value, it must be a date set in the future")
 	}
 
-	var secret string
-	if options == nil || options.APIKey == "" {
-		secret = m.client.apiKey
-	} else {
-		secret = options.APIKey
-	}
-
 	// For HMAC signing method, the key should be any []byte
 	hmacSampleSecret := []byte(secret)
 
@@ -684,6 +697,9 @@ func (m *meilisearch) IsHealthy() bool {
 }
 
 func (m *meilisearch) Close() {
+	if m.client.nodes != nil {
+		m.client.nodes.close()
+	}
 	m.client.client.CloseIdleConnections()
 }
 
@@ -811,6 +827,26 @@ func getTask(ctx context.Context, cli *client, taskUID int64) (*Task, error) {
 	return resp, nil
 }
 
+func getTasks(ctx context.Context, cli *client, param *TasksQuery) (*TaskResult, error) {
+	resp := new(TaskResult)
+	req := &internalRequest{
+		endpoint:            "/tasks",
+		method:              http.MethodGet,
+		withRequest:         nil,
+		withResponse:        &resp,
+		withQueryParams:     map[string]string{},
+		acceptedStatusCodes: []int{http.StatusOK},
+		functionName:        "GetTasks",
+	}
+	if param != nil {
+		encodeTasksQuery(param, req)
+	}
+	if err := cli.executeRequest(ctx, req); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func waitForTask(ctx context.Context, cli *client, taskUID int64, interval time.Duration) (*Task, error) {
 	if interval == 0 {
 		interval = 50 * time.Millisecond
@@ -2,6 +2,7 @@ package meilisearch
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 )
 
@@ -31,6 +32,10 @@ type ServiceManager interface {
 	WebhookManager() WebhookManager
 	WebhookReader() WebhookReader
 
+	// TaskHandle binds the task summary returned by a write to the client, to wait for, cancel
+	// or inspect the task without threading the ServiceManager around.
+	TaskHandle(taskInfo *TaskInfo) TaskHandle
+
 	// CreateIndex creates a new index.
 	//
 	// docs: https://www.meilisearch.com/docs/reference/api/indexes/create-index
@@ -124,7 +129,18 @@ type ServiceManager interface {
 	// docs: https://www.meilisearch.com/docs/reference/api/template/render-documents-with-post
 	RenderTemplateWithContext(ctx context.Context, params *RenderTemplateParams) (*RenderTemplateResponse, error)
 
-	// Close closes the connection to the Meilisearch server.
+	// Do sends a request to any route of Meilisearch, typically one the SDK does not cover yet,
+	// through the same pipeline as the other calls: authentication, middlewares, retries,
+	// compression, JSON marshaller and *Error construction.
+	//
+	// The query parameters are added to path. A body other than []byte or io.Reader is marshalled
+	// to JSON, it is sent with the application/json Content-Type unless one is set with
+	// CallWithHeader. The response is unmarshalled into out, which can also be a *[]byte to get
+	// the raw body, or nil to ignore it. It is left untouched by a 204 or an empty body. Any 2xx
+	// status code is a success.
+	Do(ctx context.Context, method, path string, query map[string]string, body, out interface{}) error
+
+	// Close closes the connection to the Meilisearch server and stops the health probes started by WithHosts.
 	Close()
 }
 
@@ -336,6 +352,18 @@ type TaskManager interface {
 	//
 	// docs: https://www.meilisearch.com/docs/reference/api/async-task-management/get-tasks-documents
 	GetTaskDocumentsWithContext(ctx context.Context, taskUID int64, dst interface{}) error
+
+	// ForEachTaskDocument calls fn with every document associated with a task, decoding them one at a time
+	// so that the documents of large tasks are never held in memory together. Iteration stops at the first
+	// error returned by fn, which is returned as is.
+	//
+	// docs: https://www.meilisearch.com/docs/reference/api/async-task-management/get-tasks-documents
+	ForEachTaskDocument(taskUID int64, fn func(document json.RawMessage) error) error
+
+	// ForEachTaskDocumentWithContext calls fn with every document associated with a task using the provided context for cancellation.
+	//
+	// docs: https://www.meilisearch.com/docs/reference/api/async-task-management/get-tasks-documents
+	ForEachTaskDocumentWithContext(ctx context.Context, taskUID int64, fn func(document json.RawMessage) error) error
 }
 
 type TaskReader interface {
@@ -364,4 +392,15 @@ type TaskReader interface {
 
 	// WaitForTaskWithContext waits for a task to complete by its UID with the given interval using the provided context for cancellation.
 	WaitForTaskWithContext(ctx context.Context, taskUID int64, interval time.Duration) (*Task, error)
+
+	// WaitForTaskWithOptions waits for a task to complete by its UID, polling with an exponential
+	// backoff, an optional timeout and a status callback as set by opts, nil uses the defaults.
+	WaitForTaskWithOptions(ctx context.Context, taskUID int64, opts *WaitOptions) (*Task, error)
+
+	// WaitForTasks waits for all the given tasks to complete, polling them together with a single
+	// GetTasks call per tick as set by opts. It returns the completed tasks by UID, and a
+	// *TasksError listing the tasks that failed or were canceled. On a timeout or a cancellation
+	// the tasks completed so far are returned along with the error. When some of the UIDs match
+	// no task, it fails at once with an *Error matching ErrTaskNotFound.
+	WaitForTasks(ctx context.Context, taskUIDs []int64, opts *WaitOptions) (map[int64]*Task, error)
 }
@@ -0,0 +1,92 @@
+package meilisearchtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// decodeBody returns the body uncompressed according to the given Content-Encoding.
+func decodeBody(contentEncoding string, body []byte) ([]byte, error) {
+	var r io.Reader
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "":
+		return body, nil
+	case "gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = gr.Close()
+		}()
+		r = gr
+	case "deflate":
+		zr, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = zr.Close()
+		}()
+		r = zr
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(body))
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %q", contentEncoding)
+	}
+	return io.ReadAll(r)
+}
+
+// encodeBody compresses body with the first encoding of acceptEncoding the client supports,
+// it returns the encoding used, empty when the body is left as is.
+func encodeBody(acceptEncoding string, body []byte) ([]byte, string, error) {
+	for _, ce := range strings.Split(acceptEncoding, ",") {
+		ce = strings.ToLower(strings.TrimSpace(ce))
+		if i := strings.IndexByte(ce, ';'); i >= 0 {
+			ce = ce[:i]
+		}
+
+		var (
+			buf bytes.Buffer
+			w   io.WriteCloser
+		)
+		switch ce {
+		case "gzip":
+			w = gzip.NewWriter(&buf)
+		case "deflate":
+			w = zlib.NewWriter(&buf)
+		case "br":
+			w = brotli.NewWriter(&buf)
+		case "zstd":
+			zw, err := zstd.NewWriter(&buf)
+			if err != nil {
+				return nil, "", err
+			}
+			w = zw
+		default:
+			continue
+		}
+		if _, err := w.Write(body); err != nil {
+			return nil, "", err
+		}
+		if err := w.Close(); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), ce, nil
+	}
+	return body, "", nil
+}
@@ -0,0 +1,316 @@
+package meilisearchtest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// FaultTransport is an http.RoundTripper injecting faults in the traffic of a client, to test
+// how the retries, timeouts and decoding of the meilisearch package behave when things go wrong:
+//
+//	ft := meilisearchtest.NewFaultTransport(nil)
+//	ft.OnFunction("Search").Times(2).Status(http.StatusServiceUnavailable)
+//	client := srv.Client(meilisearch.WithCustomClient(ft.Client()))
+//
+// Every request goes through the rules in the order they were added, the first one matching it
+// with faults left to inject applies. Requests no rule applies to reach the transport as is.
+type FaultTransport struct {
+	transport http.RoundTripper
+
+	mu    sync.Mutex
+	rules []*FaultRule
+}
+
+// NewFaultTransport returns a FaultTransport sending the requests through transport, default
+// is http.DefaultTransport.
+func NewFaultTransport(transport http.RoundTripper) *FaultTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &FaultTransport{transport: transport}
+}
+
+// Client returns an *http.Client sending its requests through the FaultTransport.
+func (t *FaultTransport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// OnEndpoint adds a rule for the requests with the given method, any when empty, whose path
+// matches pattern as path.Match does, e.g. "/indexes/*/search".
+func (t *FaultTransport) OnEndpoint(method, pattern string) *FaultRule {
+	return t.add(func(req *http.Request) bool {
+		if method != "" && req.Method != method {
+			return false
+		}
+		ok, _ := path.Match(pattern, req.URL.Path)
+		return ok
+	})
+}
+
+// OnFunction adds a rule for the requests sent by the given SDK function, e.g. "Search", as
+// reported by meilisearch.FunctionFromContext.
+func (t *FaultTransport) OnFunction(name string) *FaultRule {
+	return t.add(func(req *http.Request) bool {
+		return meilisearch.FunctionFromContext(req.Context()) == name
+	})
+}
+
+// OnAny adds a rule for every request.
+func (t *FaultTransport) OnAny() *FaultRule {
+	return t.add(func(*http.Request) bool { return true })
+}
+
+// Reset removes every rule.
+func (t *FaultTransport) Reset() {
+	t.mu.Lock()
+	t.rules = nil
+	t.mu.Unlock()
+}
+
+func (t *FaultTransport) add(match func(*http.Request) bool) *FaultRule {
+	rule := &FaultRule{mu: &t.mu, match: match}
+	t.mu.Lock()
+	t.rules = append(t.rules, rule)
+	t.mu.Unlock()
+	return rule
+}
+
+// RoundTrip sends a single request, injecting the faults of the first rule applying to it.
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	var faults *FaultRule
+	for _, rule := range t.rules {
+		if rule.apply(req) {
+			faults = rule
+			break
+		}
+	}
+	t.mu.Unlock()
+
+	if faults == nil {
+		return t.transport.RoundTrip(req)
+	}
+	return faults.roundTrip(t.transport, req)
+}
+
+// FaultRule describes the faults injected in the requests matching it. Its methods configure the
+// rule and return it so that they can be chained, they must be called before the requests are
+// sent. A rule without fault lets the requests through, which is useful with Times to count them.
+type FaultRule struct {
+	// mu is the lock of the transport, it guards the counters
+	mu    *sync.Mutex
+	match func(*http.Request) bool
+
+	// skip is the number of matching requests let through before injecting faults
+	skip int
+	// times is the number of requests the faults are injected in, every one when zero
+	times int
+
+	latency     time.Duration
+	status      int
+	reset       bool
+	truncate    int
+	corrupt     bool
+	contentType string
+
+	matched  int
+	injected int
+}
+
+// After lets the first n matching requests through before injecting the faults.
+func (r *FaultRule) After(n int) *FaultRule {
+	r.skip = n
+	return r
+}
+
+// Times injects the faults in n requests only, the following ones go to the next rules.
+func (r *FaultRule) Times(n int) *FaultRule {
+	r.times = n
+	return r
+}
+
+// Latency delays the requests by d. The delay stops with the context of the request, whose
+// error is then returned.
+func (r *FaultRule) Latency(d time.Duration) *FaultRule {
+	r.latency = d
+	return r
+}
+
+// Status answers the requests with the given status code instead of sending them, e.g.
+// http.StatusBadGateway or http.StatusTooManyRequests.
+func (r *FaultRule) Status(code int) *FaultRule {
+	r.status = code
+	return r
+}
+
+// ResetConnection fails the requests with a connection reset by peer instead of sending them.
+func (r *FaultRule) ResetConnection() *FaultRule {
+	r.reset = true
+	return r
+}
+
+// TruncateBody cuts the response bodies after n bytes, reading further fails with
+// io.ErrUnexpectedEOF.
+func (r *FaultRule) TruncateBody(n int) *FaultRule {
+	r.truncate = n
+	return r
+}
+
+// CorruptEncoding damages compressed response bodies so that no decoder accepts them. A body
+// sent uncompressed is labelled as gzip.
+func (r *FaultRule) CorruptEncoding() *FaultRule {
+	r.corrupt = true
+	return r
+}
+
+// ContentType replaces the Content-Type of the responses.
+func (r *FaultRule) ContentType(contentType string) *FaultRule {
+	r.contentType = contentType
+	return r
+}
+
+// Matched returns the number of requests that matched the rule while it had faults left to inject.
+func (r *FaultRule) Matched() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.matched
+}
+
+// Injected returns the number of requests the faults were injected in.
+func (r *FaultRule) Injected() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.injected
+}
+
+// apply reports whether the faults of the rule are injected in req.
+func (r *FaultRule) apply(req *http.Request) bool {
+	if r.times > 0 && r.injected >= r.times || !r.match(req) {
+		return false
+	}
+	r.matched++
+	if r.matched <= r.skip {
+		return false
+	}
+	r.injected++
+	return true
+}
+
+func (r *FaultRule) roundTrip(transport http.RoundTripper, req *http.Request) (*http.Response, error) {
+	if r.latency > 0 {
+		timer := time.NewTimer(r.latency)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			closeBody(req)
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	if r.reset {
+		closeBody(req)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	}
+
+	var resp *http.Response
+	if r.status != 0 {
+		closeBody(req)
+		resp = statusResponse(req, r.status)
+	} else {
+		var err error
+		if resp, err = transport.RoundTrip(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.contentType != "" {
+		resp.Header.Set("Content-Type", r.contentType)
+	}
+	if r.corrupt {
+		if err := corruptBody(resp); err != nil {
+			return nil, err
+		}
+	}
+	if r.truncate > 0 {
+		resp.Body = &truncatedBody{ReadCloser: resp.Body, left: r.truncate}
+		resp.ContentLength = -1
+		resp.Header.Del("Content-Length")
+	}
+	return resp, nil
+}
+
+// statusResponse builds a response with the given status code and a plain text body.
+func statusResponse(req *http.Request, code int) *http.Response {
+	body := fmt.Sprintf("%d %s", code, http.StatusText(code))
+	header := make(http.Header)
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	return &http.Response{
+		Status:        body,
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// corruptBody cuts the body in half, breaking its trailer, and flips its first byte, breaking
+// its header.
+func corruptBody(resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	if resp.Header.Get("Content-Encoding") == "" {
+		resp.Header.Set("Content-Encoding", "gzip")
+	}
+	body = body[:(len(body)+1)/2]
+	if len(body) > 0 {
+		body[0] ^= 0xff
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", fmt.Sprint(len(body)))
+	return nil
+}
+
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
+
+// truncatedBody returns io.ErrUnexpectedEOF once left bytes were read.
+type truncatedBody struct {
+	io.ReadCloser
+	left int
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	if b.left <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if len(p) > b.left {
+		p = p[:b.left]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.left -= n
+	return n, err
+}
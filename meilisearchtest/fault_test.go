@@ -0,0 +1,158 @@
+package meilisearchtest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/meilisearch/meilisearch-go"
+	"github.com/stretchr/testify/require"
+)
+
+// faultClient returns a client of srv sending its requests through ft, retrying fast.
+func faultClient(srv *Server, ft *FaultTransport, opts ...meilisearch.Option) meilisearch.ServiceManager {
+	return srv.Client(append([]meilisearch.Option{
+		meilisearch.WithCustomClient(ft.Client()),
+		meilisearch.WithRetryPolicy(meilisearch.RetryPolicy{
+			MaxRetries:         2,
+			InitialBackoff:     time.Millisecond,
+			MaxBackoff:         time.Millisecond,
+			Multiplier:         1,
+			RetryOnStatus:      []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusTooManyRequests},
+			RetryNetworkErrors: true,
+		}),
+	}, opts...)...)
+}
+
+func requireErrCode(t *testing.T, err error, code meilisearch.ErrCode) *meilisearch.Error {
+	t.Helper()
+	var meiliErr *meilisearch.Error
+	require.True(t, errors.As(err, &meiliErr), "unexpected error %v", err)
+	require.Equal(t, code, meiliErr.ErrCode, "unexpected error %v", err)
+	return meiliErr
+}
+
+func TestFaultTransport_RetryOnStatus(t *testing.T) {
+	srv := newTestServer(t)
+	ft := NewFaultTransport(nil)
+	client := faultClient(srv, ft)
+
+	rule := ft.OnFunction("Health").Times(2).Status(http.StatusServiceUnavailable)
+	_, err := client.Health()
+	require.NoError(t, err, "the third attempt reaches the server")
+	require.Equal(t, 2, rule.Injected())
+
+	ft.Reset()
+	ft.OnFunction("Health").Status(http.StatusTooManyRequests)
+	_, err = client.Health()
+	meiliErr := requireErrCode(t, err, meilisearch.MaxRetriesExceeded)
+	require.Equal(t, http.StatusTooManyRequests, meiliErr.StatusCode)
+
+	ft.Reset()
+	ft.OnFunction("Health").Status(http.StatusBadGateway)
+	_, err = srv.Client(meilisearch.WithCustomClient(ft.Client()), meilisearch.DisableRetries()).Health()
+	requireErrCode(t, err, meilisearch.APIErrorWithoutMessage)
+}
+
+func TestFaultTransport_Scripting(t *testing.T) {
+	srv := newTestServer(t)
+	ft := NewFaultTransport(nil)
+	client := faultClient(srv, ft)
+
+	first := ft.OnEndpoint(http.MethodGet, "/indexes/*").Times(1).Status(http.StatusBadGateway)
+	second := ft.OnEndpoint(http.MethodGet, "/indexes/*").Times(1).Status(http.StatusGatewayTimeout)
+	_, err := client.GetIndex("books")
+	requireAPIError(t, err, http.StatusNotFound, meilisearch.APIErrCodeIndexNotFound)
+	require.Equal(t, 1, first.Injected())
+	require.Equal(t, 1, second.Injected())
+
+	ft.Reset()
+	rule := ft.OnAny().After(1).Status(http.StatusServiceUnavailable)
+	_, err = client.Health()
+	require.NoError(t, err)
+	_, err = client.Version()
+	requireErrCode(t, err, meilisearch.MaxRetriesExceeded)
+	require.Equal(t, 4, rule.Matched())
+	require.Equal(t, 3, rule.Injected())
+}
+
+func TestFaultTransport_Timeout(t *testing.T) {
+	srv := newTestServer(t)
+	ft := NewFaultTransport(nil)
+	client := faultClient(srv, ft)
+
+	ft.OnFunction("Version").Latency(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.VersionWithContext(ctx)
+	requireErrCode(t, err, meilisearch.TimeoutError)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ft.Reset()
+	ft.OnFunction("Version").Latency(10 * time.Millisecond)
+	_, err = client.Version()
+	require.NoError(t, err)
+}
+
+func TestFaultTransport_ResetConnection(t *testing.T) {
+	srv := newTestServer(t)
+	ft := NewFaultTransport(nil)
+	client := faultClient(srv, ft)
+
+	rule := ft.OnFunction("Version").Times(2).ResetConnection()
+	_, err := client.Version()
+	require.NoError(t, err, "a GET is retried on a connection reset")
+	require.Equal(t, 2, rule.Injected())
+
+	ft.Reset()
+	ft.OnAny().ResetConnection()
+	_, err = client.Version()
+	requireErrCode(t, err, meilisearch.MaxRetriesExceeded)
+}
+
+func TestFaultTransport_Body(t *testing.T) {
+	srv := newTestServer(t)
+	ft := NewFaultTransport(nil)
+	client := faultClient(srv, ft)
+
+	ft.OnFunction("Version").Times(1).TruncateBody(5)
+	_, err := client.Version()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	for _, encoding := range []meilisearch.ContentEncoding{
+		meilisearch.GzipEncoding,
+		meilisearch.DeflateEncoding,
+		meilisearch.BrotliEncoding,
+		meilisearch.ZstdEncoding,
+	} {
+		ft.OnFunction("Version").Times(1).CorruptEncoding()
+		_, err = faultClient(srv, ft, meilisearch.WithContentEncoding(encoding, meilisearch.DefaultCompression)).Version()
+		require.Error(t, err, encoding)
+	}
+
+	_, err = client.Version()
+	require.NoError(t, err, "every fault was injected once")
+}
+
+func TestFaultTransport_ContentType(t *testing.T) {
+	srv := newTestServer(t)
+	ft := NewFaultTransport(nil)
+	client := faultClient(srv, ft)
+
+	_, err := client.ExperimentalFeatures().SetGetTaskDocumentsRoute(true).Update()
+	require.NoError(t, err)
+	info, err := client.Index("books").AddDocuments([]map[string]interface{}{{"id": 1}}, nil)
+	require.NoError(t, err)
+
+	ft.OnFunction("GetTaskDocuments").Times(1).ContentType("application/json")
+	var docs []map[string]interface{}
+	err = client.GetTaskDocuments(info.TaskUID, &docs)
+	requireErrCode(t, err, meilisearch.ErrCodeResponseUnmarshalBody)
+	require.ErrorContains(t, err, `GetTaskDocuments: unexpected Content-Type "application/json"`)
+
+	require.NoError(t, client.GetTaskDocuments(info.TaskUID, &docs))
+	require.Len(t, docs, 1)
+}
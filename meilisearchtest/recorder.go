@@ -0,0 +1,364 @@
+// Package meilisearchtest provides utilities to test code using the meilisearch package without
+// running Meilisearch.
+package meilisearchtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// Mode tells whether a Recorder records or replays the traffic of a client.
+type Mode int
+
+const (
+	// ModeReplay serves the responses of the golden file and fails on requests it does not hold.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests to Meilisearch and saves them with their responses.
+	ModeRecord
+)
+
+// RecordEnv is the environment variable switching Record to ModeRecord when set to a non-empty value.
+const RecordEnv = "MEILISEARCH_RECORD"
+
+// redacted replaces the value of the scrubbed headers in golden files.
+const redacted = "[REDACTED]"
+
+// ErrUnmatchedRequest is returned by a replaying Recorder for requests absent from its golden file.
+var ErrUnmatchedRequest = errors.New("meilisearchtest: no recorded interaction matches the request")
+
+// volatileHeaders are not saved, they change between runs or depend on the transfer of the body.
+var volatileHeaders = []string{
+	"Accept-Encoding",
+	"Content-Encoding",
+	"Content-Length",
+	"Date",
+	"Transfer-Encoding",
+	"User-Agent",
+}
+
+// Interaction is a request and the response Meilisearch answered it with.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request as saved in a golden file. Bodies are saved uncompressed, JSON
+// bodies are normalized so that the order of the object keys does not matter.
+type RecordedRequest struct {
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	Query    string          `json:"query,omitempty"`
+	Headers  http.Header     `json:"headers,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	BodyText string          `json:"bodyText,omitempty"`
+}
+
+// RecordedResponse is a response as saved in a golden file.
+type RecordedResponse struct {
+	Status   int             `json:"status"`
+	Headers  http.Header     `json:"headers,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	BodyText string          `json:"bodyText,omitempty"`
+}
+
+type goldenFile struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper recording the requests of a client and the responses of
+// Meilisearch to a golden file, or replaying them. Use it through meilisearch.WithCustomClient:
+//
+//	rec := meilisearchtest.Record(t, "search")
+//	client := meilisearch.New("http://localhost:7700", meilisearch.WithCustomClient(rec.Client()))
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	// tb is failed on unmatched requests when the Recorder comes from Record
+	tb testing.TB
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// RecorderOption configures a Recorder.
+type RecorderOption func(*Recorder)
+
+// WithTransport sets the transport used to reach Meilisearch in ModeRecord, default is
+// http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// NewRecorder returns a Recorder over the golden file at path. In ModeReplay the file is
+// loaded and must exist, in ModeRecord it is written by Save.
+func NewRecorder(path string, mode Mode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, transport: http.DefaultTransport}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("meilisearchtest: unable to read golden file: %w", err)
+		}
+		var golden goldenFile
+		if err := json.Unmarshal(data, &golden); err != nil {
+			return nil, fmt.Errorf("meilisearchtest: unable to parse golden file %s: %w", path, err)
+		}
+		// golden files are indented and may be edited by hand
+		for i := range golden.Interactions {
+			if req := &golden.Interactions[i].Request; req.Body != nil {
+				req.Body, req.BodyText = normalizeBody(req.Body)
+			}
+		}
+		r.interactions = golden.Interactions
+		r.replayed = make([]bool, len(golden.Interactions))
+	}
+	return r, nil
+}
+
+// Record returns a Recorder over testdata/<name>.json for t. The golden file is replayed unless
+// RecordEnv is set, in which case the traffic with Meilisearch is recorded and saved when t ends.
+// Requests the golden file does not hold fail t.
+func Record(t testing.TB, name string, opts ...RecorderOption) *Recorder {
+	t.Helper()
+
+	mode := ModeReplay
+	if os.Getenv(RecordEnv) != "" {
+		mode = ModeRecord
+	}
+
+	r, err := NewRecorder(filepath.Join("testdata", name+".json"), mode, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.tb = t
+	if mode == ModeRecord {
+		t.Cleanup(func() {
+			if err := r.Save(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	return r
+}
+
+// Client returns an *http.Client sending its requests through the Recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Mode returns the mode of the Recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Interactions returns the interactions recorded, or loaded from the golden file.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// RoundTrip records or replays a single request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, recorded)
+}
+
+// Save writes the recorded interactions to the golden file, creating its directory if needed.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	golden := goldenFile{Interactions: r.interactions}
+	r.mu.Unlock()
+
+	if golden.Interactions == nil {
+		golden.Interactions = []Interaction{}
+	}
+	data, err := json.MarshalIndent(golden, "", "  ")
+	if err != nil {
+		return fmt.Errorf("meilisearchtest: unable to marshal golden file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("meilisearchtest: unable to create golden file directory: %w", err)
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("meilisearchtest: unable to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	decoded, err := decodeBody(resp.Header.Get("Content-Encoding"), data)
+	if err != nil {
+		return nil, fmt.Errorf("meilisearchtest: unable to decode response body: %w", err)
+	}
+
+	response := RecordedResponse{Status: resp.StatusCode, Headers: scrubHeaders(resp.Header)}
+	response.Body, response.BodyText = normalizeBody(decoded)
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, Interaction{Request: recorded, Response: response})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay answers with the first interaction not replayed yet matching the request, so a request
+// sent several times, such as a task being polled, gets its responses in the recorded order.
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		r.replayed[i] = true
+		return replayResponse(req, interaction.Response)
+	}
+	err := fmt.Errorf("%w: %s %s", ErrUnmatchedRequest, req.Method, req.URL.RequestURI())
+	if r.tb != nil {
+		r.tb.Error(err)
+	}
+	return nil, err
+}
+
+func replayResponse(req *http.Request, recorded RecordedResponse) (*http.Response, error) {
+	body := []byte(recorded.BodyText)
+	if recorded.Body != nil {
+		body = recorded.Body
+	}
+
+	header := recorded.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	// the client expects the response compressed with the encoding it asked for
+	body, contentEncoding, err := encodeBody(req.Header.Get("Accept-Encoding"), body)
+	if err != nil {
+		return nil, fmt.Errorf("meilisearchtest: unable to encode response body: %w", err)
+	}
+	if contentEncoding != "" {
+		header.Set("Content-Encoding", contentEncoding)
+	}
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	return &http.Response{
+		Status:        strconv.Itoa(recorded.Status) + " " + http.StatusText(recorded.Status),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// recordRequest reads the body of req, which is restored so that req can still be sent.
+func recordRequest(req *http.Request) (RecordedRequest, error) {
+	recorded := RecordedRequest{
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Query:   req.URL.Query().Encode(),
+		Headers: scrubHeaders(req.Header),
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return recorded, fmt.Errorf("meilisearchtest: unable to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	decoded, err := decodeBody(req.Header.Get("Content-Encoding"), data)
+	if err != nil {
+		return recorded, fmt.Errorf("meilisearchtest: unable to decode request body: %w", err)
+	}
+	recorded.Body, recorded.BodyText = normalizeBody(decoded)
+	return recorded, nil
+}
+
+func matches(recorded, req RecordedRequest) bool {
+	return recorded.Method == req.Method &&
+		recorded.Path == req.Path &&
+		recorded.Query == req.Query &&
+		bytes.Equal(recorded.Body, req.Body) &&
+		recorded.BodyText == req.BodyText
+}
+
+// normalizeBody returns a JSON body with its keys sorted and without spaces, any other body,
+// such as NDJSON or CSV, is returned as text.
+func normalizeBody(body []byte) (json.RawMessage, string) {
+	if len(body) == 0 {
+		return nil, ""
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, string(body)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, string(body)
+	}
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return nil, string(body)
+	}
+	return normalized, ""
+}
+
+// scrubHeaders returns the headers to save, without the volatile ones and with the
+// credentials redacted.
+func scrubHeaders(h http.Header) http.Header {
+	scrubbed := h.Clone()
+	for _, key := range volatileHeaders {
+		scrubbed.Del(key)
+	}
+	if scrubbed.Get("Authorization") != "" {
+		scrubbed.Set("Authorization", redacted)
+	}
+	if len(scrubbed) == 0 {
+		return nil
+	}
+	return scrubbed
+}
@@ -0,0 +1,159 @@
+package meilisearchtest
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/meilisearch/meilisearch-go"
+	"github.com/stretchr/testify/require"
+)
+
+// searchServer answers searches with the number of requests it received, gzip compressed
+// when the client accepts it.
+func searchServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gr, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = gr
+		}
+		var search map[string]interface{}
+		require.NoError(t, json.NewDecoder(body).Decode(&search))
+
+		resp, err := json.Marshal(map[string]interface{}{
+			"hits":               []map[string]interface{}{{"id": n, "title": search["q"]}},
+			"query":              search["q"],
+			"processingTimeMs":   1,
+			"estimatedTotalHits": 1,
+		})
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Accept-Encoding") == "gzip" {
+			w.Header().Set("Content-Encoding", "gzip")
+			gw := gzip.NewWriter(w)
+			_, _ = gw.Write(resp)
+			_ = gw.Close()
+			return
+		}
+		_, _ = w.Write(resp)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.json")
+	ts := searchServer(t)
+
+	rec, err := NewRecorder(path, ModeRecord)
+	require.NoError(t, err)
+	client := meilisearch.New(ts.URL,
+		meilisearch.WithAPIKey("masterKey"),
+		meilisearch.WithContentEncoding(meilisearch.GzipEncoding, meilisearch.DefaultCompression),
+		meilisearch.WithCustomClient(rec.Client()))
+
+	recorded, err := client.Index("movies").Search("batman", &meilisearch.SearchRequest{Limit: 5})
+	require.NoError(t, err)
+	polled, err := client.Index("movies").Search("batman", &meilisearch.SearchRequest{Limit: 5})
+	require.NoError(t, err)
+	require.NoError(t, rec.Save())
+
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(golden), "masterKey")
+	require.Contains(t, string(golden), redacted)
+	require.Len(t, rec.Interactions(), 2)
+	require.JSONEq(t, `{"hybrid":null,"limit":5,"q":"batman"}`, string(rec.Interactions()[0].Request.Body))
+
+	// the replay needs neither Meilisearch nor the same encoding
+	ts.Close()
+	replay, err := NewRecorder(path, ModeReplay)
+	require.NoError(t, err)
+	client = meilisearch.New("http://meilisearch.test", meilisearch.WithCustomClient(replay.Client()))
+
+	resp, err := client.Index("movies").Search("batman", &meilisearch.SearchRequest{Limit: 5})
+	require.NoError(t, err)
+	require.Equal(t, recorded.Hits, resp.Hits)
+
+	// identical requests are answered in the recorded order
+	resp, err = client.Index("movies").Search("batman", &meilisearch.SearchRequest{Limit: 5})
+	require.NoError(t, err)
+	require.Equal(t, polled.Hits, resp.Hits)
+	require.NotEqual(t, recorded.Hits, polled.Hits)
+
+	_, err = client.Index("movies").Search("batman", &meilisearch.SearchRequest{Limit: 5})
+	require.ErrorIs(t, err, ErrUnmatchedRequest)
+
+	_, err = client.Index("movies").Search("superman", &meilisearch.SearchRequest{Limit: 5})
+	require.ErrorIs(t, err, ErrUnmatchedRequest)
+}
+
+func TestRecorder_ReplayEncodesResponse(t *testing.T) {
+	rec := Record(t, "version")
+	require.Equal(t, ModeReplay, rec.Mode())
+
+	for _, encoding := range []meilisearch.ContentEncoding{"", meilisearch.GzipEncoding, meilisearch.DeflateEncoding, meilisearch.BrotliEncoding, meilisearch.ZstdEncoding} {
+		replay, err := NewRecorder(filepath.Join("testdata", "version.json"), ModeReplay)
+		require.NoError(t, err)
+
+		opts := []meilisearch.Option{meilisearch.WithCustomClient(replay.Client())}
+		if !encoding.IsZero() {
+			opts = append(opts, meilisearch.WithContentEncoding(encoding, meilisearch.DefaultCompression))
+		}
+		version, err := meilisearch.New("http://meilisearch.test", opts...).Version()
+		require.NoError(t, err, encoding)
+		require.Equal(t, "1.12.0", version.PkgVersion)
+	}
+
+	version, err := meilisearch.New("http://meilisearch.test", meilisearch.WithCustomClient(rec.Client())).Version()
+	require.NoError(t, err)
+	require.Equal(t, "1.12.0", version.PkgVersion)
+}
+
+func TestRecorder_MissingGoldenFile(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay)
+	require.Error(t, err)
+}
+
+func TestNormalizeBody(t *testing.T) {
+	a, text := normalizeBody([]byte(`{"q": "batman", "limit": 5}`))
+	require.Empty(t, text)
+	b, _ := normalizeBody([]byte(`{"limit":5,"q":"batman"}`))
+	require.Equal(t, a, b)
+
+	raw, text := normalizeBody([]byte("{\"id\":1}\n{\"id\":2}\n"))
+	require.Nil(t, raw)
+	require.Equal(t, "{\"id\":1}\n{\"id\":2}\n", text)
+
+	raw, text = normalizeBody([]byte("id,title\n1,Carol\n"))
+	require.Nil(t, raw)
+	require.Equal(t, "id,title\n1,Carol\n", text)
+}
+
+func TestScrubHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer masterKey")
+	h.Set("User-Agent", "Meilisearch Go")
+	h.Set("Content-Type", "application/json")
+
+	scrubbed := scrubHeaders(h)
+	require.Equal(t, redacted, scrubbed.Get("Authorization"))
+	require.Empty(t, scrubbed.Get("User-Agent"))
+	require.Equal(t, "application/json", scrubbed.Get("Content-Type"))
+	require.Equal(t, "Bearer masterKey", h.Get("Authorization"))
+
+	require.Nil(t, scrubHeaders(http.Header{"Content-Length": {"2"}}))
+}
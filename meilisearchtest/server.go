@@ -0,0 +1,376 @@
+package meilisearchtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// ServerVersion is the Meilisearch version reported by the Server.
+const ServerVersion = "1.19.0"
+
+// errorsLink is the documentation URL of the error codes, the code is appended as the fragment.
+const errorsLink = "https://docs.meilisearch.com/errors#"
+
+// Server is an in-process fake of Meilisearch serving the routes the meilisearch package talks
+// to: indexes, documents, settings, tasks, batches, keys and search. Writes are enqueued as
+// tasks processed one at a time in the background, and errors carry the payloads of
+// Meilisearch, so code waiting for tasks or checking error codes behaves as in production.
+//
+// Search is a simplified engine: it matches words, prefixes and typos and supports filter,
+// sort, facets and both pagination modes, but its ranking only approximates Meilisearch.
+// Embedders, chats, webhooks, network and dynamic search rules are not served.
+//
+//	srv := meilisearchtest.NewServer(meilisearchtest.WithMasterKey("masterKey"))
+//	defer srv.Close()
+//	client := srv.Client()
+type Server struct {
+	*httptest.Server
+
+	masterKey string
+	taskDelay time.Duration
+
+	mu           sync.Mutex
+	indexes      map[string]*indexState
+	tasks        []*task
+	batches      map[int64]*batch
+	nextTaskUID  int64
+	nextBatchUID int64
+	keys         []*apiKey
+	features     map[string]interface{}
+
+	wake   chan struct{}
+	closed chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithMasterKey protects the Server with the given master key. Like Meilisearch, it then
+// creates the default search and admin API keys and rejects requests without a valid key.
+func WithMasterKey(masterKey string) ServerOption {
+	return func(s *Server) {
+		s.masterKey = masterKey
+	}
+}
+
+// WithTaskDelay makes the Server wait for delay before processing each task, which lets tests
+// observe tasks in the enqueued and processing states.
+func WithTaskDelay(delay time.Duration) ServerOption {
+	return func(s *Server) {
+		s.taskDelay = delay
+	}
+}
+
+// NewServer starts a Server, it should be closed when done.
+func NewServer(opts ...ServerOption) *Server {
+	s := &Server{
+		indexes:  make(map[string]*indexState),
+		batches:  make(map[int64]*batch),
+		features: defaultFeatures(),
+		wake:     make(chan struct{}, 1),
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.masterKey != "" {
+		s.keys = defaultKeys(s.masterKey, now())
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	go s.processTasks()
+	return s
+}
+
+// Close stops the task processing and shuts the Server down.
+func (s *Server) Close() {
+	s.once.Do(func() {
+		close(s.closed)
+		<-s.done
+		s.Server.Close()
+	})
+}
+
+// MasterKey returns the master key of the Server, empty when it is not protected.
+func (s *Server) MasterKey() string {
+	return s.masterKey
+}
+
+// Client returns a client of the Server authenticated with its master key, opts are applied
+// after and may override it.
+func (s *Server) Client(opts ...meilisearch.Option) meilisearch.ServiceManager {
+	return meilisearch.New(s.URL, append([]meilisearch.Option{meilisearch.WithAPIKey(s.masterKey)}, opts...)...)
+}
+
+// apiError is an error answered with the payload of Meilisearch.
+type apiError struct {
+	status  int
+	details meilisearch.APIErrorDetails
+}
+
+func (e *apiError) Error() string {
+	return e.details.Message
+}
+
+func newAPIError(status int, code meilisearch.APIErrCode, format string, args ...interface{}) *apiError {
+	errType := "invalid_request"
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		errType = "auth"
+	case status >= http.StatusInternalServerError:
+		errType = "internal"
+	}
+	return &apiError{
+		status: status,
+		details: meilisearch.APIErrorDetails{
+			Message: fmt.Sprintf(format, args...),
+			Code:    code,
+			Type:    errType,
+			Link:    errorsLink + string(code),
+		},
+	}
+}
+
+// request is an incoming request with its uncompressed body.
+type request struct {
+	*http.Request
+	body []byte
+	// filters are the search rules of the tenant token of the request by index, nil otherwise
+	filters map[string]interface{}
+}
+
+// rawBody is a response value answered as is instead of being encoded to JSON.
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+// handlerFunc serves a route, it returns the status and the value answered as JSON, a nil value
+// answers an empty body and a *rawBody is answered as is.
+type handlerFunc func(r *request) (int, interface{}, error)
+
+// route is a handler with the API key action and index it requires.
+type route struct {
+	// action is the API key action required, empty for public routes
+	action string
+	// index is the index uid the route is about, empty when it is not about a single index
+	index  string
+	handle handlerFunc
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	status, body, err := s.serve(r)
+	if err != nil {
+		apiErr, ok := err.(*apiError)
+		if !ok {
+			apiErr = newAPIError(http.StatusInternalServerError, meilisearch.APIErrCodeInternal, "%s", err.Error())
+		}
+		status = apiErr.status
+		body, err = jsonBody(apiErr.details)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	data, contentEncoding, err := encodeBody(r.Header.Get("Accept-Encoding"), body.data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", body.contentType)
+	if contentEncoding != "" {
+		w.Header().Set("Content-Encoding", contentEncoding)
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// jsonBody encodes v as a JSON response body.
+func jsonBody(v interface{}) (*rawBody, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &rawBody{contentType: "application/json", data: data}, nil
+}
+
+// serve handles r and returns its response body, encoded with the lock held since values
+// returned by the handlers may share state with the task worker.
+func (s *Server) serve(r *http.Request) (int, *rawBody, error) {
+	segments := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	for i, segment := range segments {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segments[i] = unescaped
+		}
+	}
+
+	rt, status := s.route(r.Method, segments)
+	if rt == nil {
+		return status, nil, nil
+	}
+
+	req := &request{Request: r}
+	filters, err := s.authorize(r, rt)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.filters = filters
+
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeBadRequest, "unable to read the body: %s", err)
+	}
+	if req.body, err = decodeBody(r.Header.Get("Content-Encoding"), raw); err != nil {
+		return 0, nil, newAPIError(http.StatusUnsupportedMediaType, meilisearch.APIErrCodeBadRequest,
+			"The `Content-Encoding` header is invalid: %s", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, value, err := rt.handle(req)
+	if err != nil || value == nil {
+		return status, nil, err
+	}
+	if body, ok := value.(*rawBody); ok {
+		return status, body, nil
+	}
+	body, err := jsonBody(value)
+	return status, body, err
+}
+
+// route returns the route serving method on the given path segments, or nil and the status
+// to answer with when there is none.
+func (s *Server) route(method string, segments []string) (*route, int) {
+	var (
+		handlers map[string]*route
+		known    = true
+	)
+	switch segments[0] {
+	case "health":
+		handlers = map[string]*route{http.MethodGet: {handle: s.health}}
+	case "version":
+		handlers = map[string]*route{http.MethodGet: {action: "version", handle: s.version}}
+	case "stats":
+		handlers = map[string]*route{http.MethodGet: {action: "stats.get", handle: s.globalStats}}
+	case "dumps":
+		handlers = map[string]*route{http.MethodPost: {action: "dumps.create", handle: s.createDump}}
+	case "snapshots":
+		handlers = map[string]*route{http.MethodPost: {action: "snapshots.create", handle: s.createSnapshot}}
+	case "experimental-features":
+		handlers = map[string]*route{
+			http.MethodGet:   {action: "experimental.get", handle: s.getFeatures},
+			http.MethodPatch: {action: "experimental.update", handle: s.updateFeatures},
+		}
+	case "swap-indexes":
+		handlers = map[string]*route{http.MethodPost: {action: "indexes.swap", handle: s.swapIndexes}}
+	case "multi-search":
+		handlers = map[string]*route{http.MethodPost: {action: "search", handle: s.multiSearch}}
+	case "keys":
+		handlers = s.keyRoutes(segments[1:])
+	case "tasks":
+		handlers = s.taskRoutes(segments[1:])
+	case "batches":
+		handlers = s.batchRoutes(segments[1:])
+	case "indexes":
+		handlers = s.indexRoutes(segments[1:])
+	default:
+		known = false
+	}
+	if !known || handlers == nil {
+		return nil, http.StatusNotFound
+	}
+	rt, ok := handlers[method]
+	if !ok {
+		return nil, http.StatusMethodNotAllowed
+	}
+	return rt, 0
+}
+
+func (s *Server) health(*request) (int, interface{}, error) {
+	return http.StatusOK, map[string]string{"status": "available"}, nil
+}
+
+func (s *Server) version(*request) (int, interface{}, error) {
+	return http.StatusOK, map[string]string{
+		"commitSha":  "0000000000000000000000000000000000000000",
+		"commitDate": "2025-01-01T00:00:00Z",
+		"pkgVersion": ServerVersion,
+	}, nil
+}
+
+func (s *Server) createDump(r *request) (int, interface{}, error) {
+	t := s.enqueue(r, "", meilisearch.TaskTypeDumpCreation, map[string]interface{}{"dumpUid": nil}, func(t *task) error {
+		t.details["dumpUid"] = now().Format("20060102-150405000")
+		return nil
+	})
+	return http.StatusAccepted, t.info(), nil
+}
+
+func (s *Server) createSnapshot(r *request) (int, interface{}, error) {
+	t := s.enqueue(r, "", meilisearch.TaskTypeSnapshotCreation, nil, nil)
+	return http.StatusAccepted, t.info(), nil
+}
+
+// decodeJSON decodes the JSON body of r into v, numbers are kept as json.Number.
+func decodeJSON(r *request, v interface{}) error {
+	if err := checkContentType(r, "application/json"); err != nil {
+		return err
+	}
+	if len(r.body) == 0 {
+		return newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingPayload, "A json payload is missing.")
+	}
+	dec := json.NewDecoder(strings.NewReader(string(r.body)))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeBadRequest, "Json deserialize error: %s", err)
+	}
+	return nil
+}
+
+// checkContentType fails unless the body of r is of one of the given media types.
+func checkContentType(r *request, accepted ...string) error {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return newAPIError(http.StatusUnsupportedMediaType, meilisearch.APIErrCodeMissingContentType,
+			"A Content-Type header is missing. Accepted values for the Content-Type header are: %s",
+			quoteAll(accepted))
+	}
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	for _, a := range accepted {
+		if strings.EqualFold(mediaType, a) {
+			return nil
+		}
+	}
+	return newAPIError(http.StatusUnsupportedMediaType, meilisearch.APIErrCodeInvalidContentType,
+		"The Content-Type `%s` is invalid. Accepted values for the Content-Type header are: %s",
+		contentType, quoteAll(accepted))
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "`" + v + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
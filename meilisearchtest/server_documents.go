@@ -0,0 +1,630 @@
+package meilisearchtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+const (
+	contentTypeJSON   = "application/json"
+	contentTypeNDJSON = "application/x-ndjson"
+	contentTypeCSV    = "text/csv"
+)
+
+var documentIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,511}$`)
+
+func (s *Server) documentRoutes(uid string, segments []string) map[string]*route {
+	switch {
+	case len(segments) == 0:
+		return map[string]*route{
+			http.MethodGet: {action: "documents.get", index: uid, handle: func(r *request) (int, interface{}, error) {
+				return s.listDocuments(r, uid)
+			}},
+			http.MethodPost: {action: "documents.add", index: uid, handle: func(r *request) (int, interface{}, error) {
+				return s.addDocuments(r, uid, true)
+			}},
+			http.MethodPut: {action: "documents.add", index: uid, handle: func(r *request) (int, interface{}, error) {
+				return s.addDocuments(r, uid, false)
+			}},
+			http.MethodDelete: {action: "documents.delete", index: uid, handle: func(r *request) (int, interface{}, error) {
+				return s.deleteAllDocuments(r, uid)
+			}},
+		}
+	case len(segments) == 1 && segments[0] == "fetch":
+		return map[string]*route{http.MethodPost: {action: "documents.get", index: uid, handle: func(r *request) (int, interface{}, error) {
+			return s.fetchDocuments(r, uid)
+		}}}
+	case len(segments) == 1 && segments[0] == "delete-batch":
+		return map[string]*route{http.MethodPost: {action: "documents.delete", index: uid, handle: func(r *request) (int, interface{}, error) {
+			return s.deleteDocumentBatch(r, uid)
+		}}}
+	case len(segments) == 1 && segments[0] == "delete":
+		return map[string]*route{http.MethodPost: {action: "documents.delete", index: uid, handle: func(r *request) (int, interface{}, error) {
+			return s.deleteDocumentsByFilter(r, uid)
+		}}}
+	case len(segments) == 1 && segments[0] == "edit":
+		return map[string]*route{http.MethodPost: {action: "documents.all", index: uid, handle: func(r *request) (int, interface{}, error) {
+			if err := s.requireFeature("editDocumentsByFunction", "Using the documents edit route"); err != nil {
+				return 0, nil, err
+			}
+			return 0, nil, newAPIError(http.StatusNotImplemented, meilisearch.APIErrCodeInternal,
+				"Editing documents by function is not supported by meilisearchtest.")
+		}}}
+	case len(segments) == 1:
+		id := segments[0]
+		return map[string]*route{
+			http.MethodGet: {action: "documents.get", index: uid, handle: func(r *request) (int, interface{}, error) {
+				return s.getDocument(r, uid, id)
+			}},
+			http.MethodDelete: {action: "documents.delete", index: uid, handle: func(r *request) (int, interface{}, error) {
+				return s.enqueueDeletion(r, uid, []string{id})
+			}},
+		}
+	}
+	return nil
+}
+
+// addDocuments enqueues the addition of the documents of the body, replace selects between
+// replacing existing documents and merging the new fields into them.
+func (s *Server) addDocuments(r *request, uid string, replace bool) (int, interface{}, error) {
+	if !validIndexUID(uid) {
+		return 0, nil, invalidIndexUID(uid, meilisearch.APIErrCodeInvalidIndexUID)
+	}
+	docs, err := parseDocuments(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	query := r.URL.Query()
+	primaryKey := query.Get("primaryKey")
+	skipCreation := query.Get("skipCreation") == "true"
+
+	details := map[string]interface{}{"receivedDocuments": len(docs), "indexedDocuments": nil}
+	t := s.enqueue(r, uid, meilisearch.TaskTypeDocumentAdditionOrUpdate, details, func(t *task) error {
+		idx := s.indexes[uid]
+		current := ""
+		if idx != nil {
+			current = idx.primaryKey
+		}
+		pk, err := documentsPrimaryKey(uid, current, primaryKey, docs)
+		if err != nil {
+			t.details["indexedDocuments"] = 0
+			return err
+		}
+		ids := make([]string, len(docs))
+		for i, doc := range docs {
+			if ids[i], err = documentID(doc, pk); err != nil {
+				t.details["indexedDocuments"] = 0
+				return err
+			}
+		}
+
+		if idx == nil {
+			idx = newIndexState(uid, "")
+			s.indexes[uid] = idx
+		}
+		idx.primaryKey = pk
+		indexed := 0
+		for i, doc := range docs {
+			existing, ok := idx.documents[ids[i]]
+			switch {
+			case ok && !replace:
+				merged := make(document, len(existing)+len(doc))
+				for k, v := range existing {
+					merged[k] = v
+				}
+				for k, v := range doc {
+					merged[k] = v
+				}
+				idx.documents[ids[i]] = merged
+			case ok:
+				idx.documents[ids[i]] = doc
+			case skipCreation:
+				continue
+			default:
+				idx.documents[ids[i]] = doc
+				idx.order = append(idx.order, ids[i])
+			}
+			indexed++
+		}
+		idx.updatedAt = now()
+		t.details["indexedDocuments"] = indexed
+		return nil
+	})
+	t.documents = docs
+	return http.StatusAccepted, t.info(), nil
+}
+
+// documentsPrimaryKey returns the primary key of the index once docs are added: the current one,
+// the requested one or the one inferred from the documents.
+func documentsPrimaryKey(uid, current, requested string, docs []document) (string, error) {
+	switch {
+	case requested != "" && current != "" && requested != current:
+		return "", newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeIndexPrimaryKeyAlreadyExists,
+			"Index `%s`: Index already has a primary key: `%s`.", uid, current)
+	case requested != "":
+		return requested, nil
+	case current != "":
+		return current, nil
+	case len(docs) == 0:
+		return "", nil
+	}
+
+	var candidates []string
+	for field := range docs[0] {
+		if strings.HasSuffix(strings.ToLower(field), "id") {
+			candidates = append(candidates, field)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeIndexPrimaryKeyNoCandidateFound,
+			"Index `%s`: The primary key inference failed as the engine did not find any field ending with `id` "+
+				"in its name. Please specify the primary key manually using the `primaryKey` query parameter.", uid)
+	case 1:
+		return candidates[0], nil
+	}
+	return "", newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeIndexPrimaryKeyMultipleCandidatesFound,
+		"Index `%s`: The primary key inference failed as the engine found %d fields ending with `id` in their "+
+			"names: %s. Please specify the primary key manually using the `primaryKey` query parameter.",
+		uid, len(candidates), quoteAll(candidates))
+}
+
+// documentID returns the id of doc as a string.
+func documentID(doc document, primaryKey string) (string, error) {
+	value, ok := doc[primaryKey]
+	if !ok {
+		data, _ := json.Marshal(doc)
+		return "", newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingDocumentID,
+			"Document doesn't have a `%s` attribute: `%s`.", primaryKey, data)
+	}
+	if id, ok := idString(value); ok {
+		return id, nil
+	}
+	data, _ := json.Marshal(value)
+	return "", newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidDocumentID,
+		"Document identifier `%s` is invalid. A document identifier can be of type integer or string, only "+
+			"composed of alphanumeric characters (a-z A-Z 0-9), hyphens (-) and underscores (_), and can not be "+
+			"more than 511 bytes.", data)
+}
+
+// idString converts a document identifier to its string form.
+func idString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, documentIDPattern.MatchString(v)
+	case json.Number:
+		n, err := strconv.ParseUint(v.String(), 10, 64)
+		return strconv.FormatUint(n, 10), err == nil
+	}
+	return "", false
+}
+
+// parseDocuments parses the documents of the body according to its content type.
+func parseDocuments(r *request) ([]document, error) {
+	if err := checkContentType(r, contentTypeJSON, contentTypeNDJSON, contentTypeCSV); err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(r.body)) == 0 {
+		return nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingPayload,
+			"A %s payload is missing.", payloadFormat(r))
+	}
+
+	switch payloadFormat(r) {
+	case "ndjson":
+		return parseNDJSON(r.body)
+	case "csv":
+		delimiter := ','
+		if value, ok := r.URL.Query()["csvDelimiter"]; ok {
+			if utf8.RuneCountInString(value[0]) != 1 || value[0][0] >= utf8.RuneSelf {
+				return nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidDocumentCSVDelimiter,
+					"Invalid value in parameter `csvDelimiter`: expected a string of one character, but found the "+
+						"following string of %d characters: `%s`", utf8.RuneCountInString(value[0]), value[0])
+			}
+			delimiter = rune(value[0][0])
+		}
+		return parseCSV(r.body, delimiter)
+	}
+
+	var payload interface{}
+	dec := json.NewDecoder(bytes.NewReader(r.body))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		return nil, malformedPayload("json", err.Error())
+	}
+	switch v := payload.(type) {
+	case map[string]interface{}:
+		return []document{v}, nil
+	case []interface{}:
+		docs := make([]document, 0, len(v))
+		for _, item := range v {
+			doc, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, malformedPayload("json", "expected a map")
+			}
+			docs = append(docs, doc)
+		}
+		return docs, nil
+	}
+	return nil, malformedPayload("json", "expected a map or an array of maps")
+}
+
+func payloadFormat(r *request) string {
+	mediaType := strings.TrimSpace(strings.SplitN(r.Header.Get("Content-Type"), ";", 2)[0])
+	switch strings.ToLower(mediaType) {
+	case contentTypeNDJSON:
+		return "ndjson"
+	case contentTypeCSV:
+		return "csv"
+	}
+	return "json"
+}
+
+func malformedPayload(format, reason string) *apiError {
+	return newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMalformedPayload,
+		"The `%s` payload provided is malformed. `Couldn't serialize document value: %s`.", format, reason)
+}
+
+func parseNDJSON(body []byte) ([]document, error) {
+	var docs []document
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(body)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var doc document
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, malformedPayload("ndjson", err.Error())
+		}
+		docs = append(docs, doc)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, malformedPayload("ndjson", err.Error())
+	}
+	return docs, nil
+}
+
+// parseCSV parses CSV documents, header columns may be typed as in `price:number`.
+func parseCSV(body []byte, delimiter rune) ([]document, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.Comma = delimiter
+	header, err := reader.Read()
+	if err != nil {
+		return nil, malformedPayload("csv", err.Error())
+	}
+	names := make([]string, len(header))
+	types := make([]string, len(header))
+	for i, column := range header {
+		names[i], types[i] = column, "string"
+		if n := strings.LastIndex(column, ":"); n >= 0 {
+			switch typ := column[n+1:]; typ {
+			case "string", "number", "boolean":
+				names[i], types[i] = column[:n], typ
+			}
+		}
+	}
+
+	var docs []document
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, malformedPayload("csv", err.Error())
+		}
+		doc := make(document, len(record))
+		for i, value := range record {
+			if i >= len(names) {
+				break
+			}
+			switch {
+			case value == "" && types[i] != "string":
+				doc[names[i]] = nil
+			case types[i] == "number":
+				if _, err := strconv.ParseFloat(value, 64); err != nil {
+					return nil, malformedPayload("csv", "invalid number `"+value+"`")
+				}
+				doc[names[i]] = json.Number(value)
+			case types[i] == "boolean":
+				b, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, malformedPayload("csv", "invalid boolean `"+value+"`")
+				}
+				doc[names[i]] = b
+			default:
+				doc[names[i]] = value
+			}
+		}
+		docs = append(docs, doc)
+	}
+}
+
+func (s *Server) getDocument(r *request, uid, id string) (int, interface{}, error) {
+	idx, err := s.index(uid)
+	if err != nil {
+		return 0, nil, err
+	}
+	doc, ok := idx.documents[id]
+	if !ok {
+		return 0, nil, newAPIError(http.StatusNotFound, meilisearch.APIErrCodeDocumentNotFound, "Document `%s` not found.", id)
+	}
+	query := r.URL.Query()
+	return http.StatusOK, selectFields(doc, splitList(query.Get("fields")), query.Get("retrieveVectors") == "true"), nil
+}
+
+// documentsQuery is the body of POST /documents/fetch, also read from the query string of GET /documents.
+type documentsQuery struct {
+	Offset          *int        `json:"offset"`
+	Limit           *int        `json:"limit"`
+	Fields          []string    `json:"fields"`
+	Filter          interface{} `json:"filter"`
+	IDs             []string    `json:"ids"`
+	Sort            []string    `json:"sort"`
+	RetrieveVectors bool        `json:"retrieveVectors"`
+}
+
+func (s *Server) listDocuments(r *request, uid string) (int, interface{}, error) {
+	offset, limit, err := offsetLimit(r, meilisearch.APIErrCodeInvalidDocumentOffset, meilisearch.APIErrCodeInvalidDocumentLimit)
+	if err != nil {
+		return 0, nil, err
+	}
+	query := r.URL.Query()
+	q := documentsQuery{
+		Offset:          &offset,
+		Limit:           &limit,
+		Fields:          splitList(query.Get("fields")),
+		IDs:             splitList(query.Get("ids")),
+		Sort:            splitList(query.Get("sort")),
+		RetrieveVectors: query.Get("retrieveVectors") == "true",
+	}
+	if filter := query.Get("filter"); filter != "" {
+		q.Filter = filter
+	}
+	return s.queryDocuments(uid, q)
+}
+
+func (s *Server) fetchDocuments(r *request, uid string) (int, interface{}, error) {
+	var q documentsQuery
+	if err := decodeJSON(r, &q); err != nil {
+		return 0, nil, err
+	}
+	if q.Offset != nil && *q.Offset < 0 {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidDocumentOffset,
+			"Invalid value at `.offset`: value must be a positive integer")
+	}
+	if q.Limit != nil && *q.Limit < 0 {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidDocumentLimit,
+			"Invalid value at `.limit`: value must be a positive integer")
+	}
+	return s.queryDocuments(uid, q)
+}
+
+func (s *Server) queryDocuments(uid string, q documentsQuery) (int, interface{}, error) {
+	idx, err := s.index(uid)
+	if err != nil {
+		return 0, nil, err
+	}
+	offset, limit := 0, 20
+	if q.Offset != nil {
+		offset = *q.Offset
+	}
+	if q.Limit != nil {
+		limit = *q.Limit
+	}
+
+	filter, err := idx.compileFilter(q.Filter, meilisearch.APIErrCodeInvalidDocumentFilter)
+	if err != nil {
+		return 0, nil, err
+	}
+	rules, err := idx.parseSort(q.Sort, meilisearch.APIErrCodeInvalidDocumentSort)
+	if err != nil {
+		return 0, nil, err
+	}
+	var ids map[string]bool
+	if q.IDs != nil {
+		ids = make(map[string]bool, len(q.IDs))
+		for _, id := range q.IDs {
+			ids[id] = true
+		}
+	}
+
+	var matched []document
+	for _, id := range idx.order {
+		doc := idx.documents[id]
+		if ids != nil && !ids[id] {
+			continue
+		}
+		if filter != nil && !filter.match(doc) {
+			continue
+		}
+		matched = append(matched, doc)
+	}
+	sortDocuments(matched, rules)
+
+	results := make([]interface{}, 0, limit)
+	for i := offset; i < len(matched) && i < offset+limit; i++ {
+		results = append(results, selectFields(matched[i], q.Fields, q.RetrieveVectors))
+	}
+	return http.StatusOK, map[string]interface{}{
+		"results": results,
+		"offset":  offset,
+		"limit":   limit,
+		"total":   len(matched),
+	}, nil
+}
+
+func (s *Server) deleteDocumentBatch(r *request, uid string) (int, interface{}, error) {
+	var values []interface{}
+	if err := decodeJSON(r, &values); err != nil {
+		return 0, nil, err
+	}
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		id, ok := idString(value)
+		if !ok {
+			data, _ := json.Marshal(value)
+			return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidDocumentID,
+				"Document identifier `%s` is invalid.", data)
+		}
+		ids = append(ids, id)
+	}
+	return s.enqueueDeletion(r, uid, ids)
+}
+
+// enqueueDeletion enqueues the deletion of the documents of the given ids.
+func (s *Server) enqueueDeletion(r *request, uid string, ids []string) (int, interface{}, error) {
+	details := map[string]interface{}{"providedIds": len(ids), "deletedDocuments": nil}
+	t := s.enqueue(r, uid, meilisearch.TaskTypeDocumentDeletion, details, func(t *task) error {
+		idx, err := s.index(uid)
+		if err != nil {
+			t.details["deletedDocuments"] = 0
+			return err
+		}
+		set := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			set[id] = true
+		}
+		t.details["deletedDocuments"] = idx.deleteDocuments(set)
+		idx.updatedAt = now()
+		return nil
+	})
+	return http.StatusAccepted, t.info(), nil
+}
+
+func (s *Server) deleteDocumentsByFilter(r *request, uid string) (int, interface{}, error) {
+	var payload struct {
+		Filter interface{} `json:"filter"`
+	}
+	if err := decodeJSON(r, &payload); err != nil {
+		return 0, nil, err
+	}
+	if payload.Filter == nil {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingDocumentFilter,
+			"Missing field `filter`")
+	}
+	if _, err := parseFilter(payload.Filter); err != nil {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidDocumentFilter, "%s", err)
+	}
+
+	original, _ := json.Marshal(payload.Filter)
+	details := map[string]interface{}{
+		"providedIds":      0,
+		"originalFilter":   string(original),
+		"deletedDocuments": nil,
+	}
+	t := s.enqueue(r, uid, meilisearch.TaskTypeDocumentDeletion, details, func(t *task) error {
+		t.details["deletedDocuments"] = 0
+		idx, err := s.index(uid)
+		if err != nil {
+			return err
+		}
+		filter, err := idx.compileFilter(payload.Filter, meilisearch.APIErrCodeInvalidDocumentFilter)
+		if err != nil {
+			return err
+		}
+		ids := make(map[string]bool)
+		for id, doc := range idx.documents {
+			if filter.match(doc) {
+				ids[id] = true
+			}
+		}
+		t.details["deletedDocuments"] = idx.deleteDocuments(ids)
+		idx.updatedAt = now()
+		return nil
+	})
+	return http.StatusAccepted, t.info(), nil
+}
+
+func (s *Server) deleteAllDocuments(r *request, uid string) (int, interface{}, error) {
+	details := map[string]interface{}{"deletedDocuments": nil}
+	t := s.enqueue(r, uid, meilisearch.TaskTypeDocumentDeletion, details, func(t *task) error {
+		idx, err := s.index(uid)
+		if err != nil {
+			t.details["deletedDocuments"] = 0
+			return err
+		}
+		t.details["deletedDocuments"] = idx.clear()
+		idx.updatedAt = now()
+		return nil
+	})
+	return http.StatusAccepted, t.info(), nil
+}
+
+// selectFields returns the fields of doc to answer, all of them when fields is empty or holds `*`.
+func selectFields(doc document, fields []string, retrieveVectors bool) document {
+	selected := make(document, len(doc))
+	if len(fields) == 0 || containsString(fields, "*") {
+		for k, v := range doc {
+			selected[k] = v
+		}
+	} else {
+		for _, field := range fields {
+			copyField(selected, doc, field)
+		}
+	}
+	if !retrieveVectors {
+		delete(selected, "_vectors")
+	}
+	return selected
+}
+
+// copyField copies the possibly nested field path from src to dst.
+func copyField(dst, src map[string]interface{}, path string) {
+	if v, ok := src[path]; ok {
+		dst[path] = v
+		return
+	}
+	for key, v := range src {
+		if !strings.HasPrefix(path, key+".") {
+			continue
+		}
+		nested, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		sub, _ := dst[key].(map[string]interface{})
+		if sub == nil {
+			sub = make(map[string]interface{})
+		}
+		copyField(sub, nested, path[len(key)+1:])
+		if len(sub) > 0 {
+			dst[key] = sub
+		}
+	}
+}
+
+// splitList splits a comma separated query parameter, nil when empty.
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	list := strings.Split(value, ",")
+	for i := range list {
+		list[i] = strings.TrimSpace(list[i])
+	}
+	return list
+}
+
+// ndjsonBody is a response body of documents in the NDJSON format.
+func ndjsonBody(docs []document) *rawBody {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, doc := range docs {
+		_ = enc.Encode(doc)
+	}
+	return &rawBody{contentType: contentTypeNDJSON, data: buf.Bytes()}
+}
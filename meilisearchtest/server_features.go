@@ -0,0 +1,67 @@
+package meilisearchtest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// defaultFeatures returns the experimental features, all disabled.
+func defaultFeatures() map[string]interface{} {
+	return map[string]interface{}{
+		"logsRoute":               false,
+		"metrics":                 false,
+		"editDocumentsByFunction": false,
+		"containsFilter":          false,
+		"network":                 false,
+		"compositeEmbedders":      false,
+		"chatCompletions":         false,
+		"multimodal":              false,
+		"dynamicSearchRules":      false,
+		"getTaskDocumentsRoute":   false,
+		"renderRoute":             false,
+	}
+}
+
+func (s *Server) getFeatures(*request) (int, interface{}, error) {
+	return http.StatusOK, s.features, nil
+}
+
+func (s *Server) updateFeatures(r *request) (int, interface{}, error) {
+	var payload map[string]interface{}
+	if err := decodeJSON(r, &payload); err != nil {
+		return 0, nil, err
+	}
+	for name, value := range payload {
+		if _, ok := s.features[name]; !ok {
+			names := make([]string, 0, len(s.features))
+			for known := range s.features {
+				names = append(names, known)
+			}
+			sort.Strings(names)
+			return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeBadRequest,
+				"Unknown field `%s`: expected one of %s", name, quoteAll(names))
+		}
+		if _, ok := value.(bool); !ok && value != nil {
+			return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeBadRequest,
+				"Invalid value type at `.%s`: expected a boolean", name)
+		}
+	}
+	for name, value := range payload {
+		if value != nil {
+			s.features[name] = value
+		}
+	}
+	return http.StatusOK, s.features, nil
+}
+
+// requireFeature fails unless the experimental feature is enabled, action describes what needs it.
+func (s *Server) requireFeature(name, action string) error {
+	if enabled, _ := s.features[name].(bool); enabled {
+		return nil
+	}
+	return newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeFeatureNotEnabled,
+		"%s requires enabling the `%s` experimental feature. See https://github.com/orgs/meilisearch/discussions",
+		action, name)
+}
@@ -0,0 +1,593 @@
+package meilisearchtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// filterExpr is a parsed filter expression.
+type filterExpr interface {
+	match(doc document) bool
+	// attributes returns the attributes the expression filters on.
+	attributes() []string
+}
+
+type andExpr []filterExpr
+
+func (e andExpr) match(doc document) bool {
+	for _, sub := range e {
+		if !sub.match(doc) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e andExpr) attributes() []string { return subAttributes(e) }
+
+type orExpr []filterExpr
+
+func (e orExpr) match(doc document) bool {
+	for _, sub := range e {
+		if sub.match(doc) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e orExpr) attributes() []string { return subAttributes(e) }
+
+func subAttributes(exprs []filterExpr) []string {
+	var attrs []string
+	for _, sub := range exprs {
+		attrs = append(attrs, sub.attributes()...)
+	}
+	return attrs
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e notExpr) match(doc document) bool { return !e.expr.match(doc) }
+
+func (e notExpr) attributes() []string { return e.expr.attributes() }
+
+// condition is a comparison of an attribute.
+type condition struct {
+	attribute string
+	// op is one of =, !=, >, >=, <, <=, TO, IN, EXISTS, NULL, EMPTY, CONTAINS and STARTS WITH
+	op     string
+	values []string
+}
+
+func (c *condition) attributes() []string { return []string{c.attribute} }
+
+func (c *condition) match(doc document) bool {
+	values, exists := fieldValues(doc, c.attribute)
+	switch c.op {
+	case "EXISTS":
+		return exists
+	case "NULL":
+		raw, ok := lookupField(doc, c.attribute)
+		return ok && raw == nil
+	case "EMPTY":
+		raw, ok := lookupField(doc, c.attribute)
+		if !ok {
+			return false
+		}
+		switch v := raw.(type) {
+		case string:
+			return v == ""
+		case []interface{}:
+			return len(v) == 0
+		case map[string]interface{}:
+			return len(v) == 0
+		}
+		return false
+	case "!=":
+		return !(&condition{attribute: c.attribute, op: "=", values: c.values}).match(doc)
+	case "TO":
+		return (&condition{attribute: c.attribute, op: ">=", values: c.values[:1]}).match(doc) &&
+			(&condition{attribute: c.attribute, op: "<=", values: c.values[1:]}).match(doc)
+	}
+
+	for _, value := range values {
+		for _, want := range c.values {
+			if compareValue(c.op, value, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareValue applies the operator op to a document value and a filter value.
+func compareValue(op string, value interface{}, want string) bool {
+	switch op {
+	case "=", "IN":
+		return equalValue(value, want)
+	case "CONTAINS":
+		s, ok := value.(string)
+		return ok && strings.Contains(strings.ToLower(s), strings.ToLower(want))
+	case "STARTS WITH":
+		s, ok := value.(string)
+		return ok && strings.HasPrefix(strings.ToLower(s), strings.ToLower(want))
+	}
+
+	n, ok := numberValue(value)
+	if !ok {
+		return false
+	}
+	w, err := strconv.ParseFloat(want, 64)
+	if err != nil {
+		return false
+	}
+	switch op {
+	case ">":
+		return n > w
+	case ">=":
+		return n >= w
+	case "<":
+		return n < w
+	case "<=":
+		return n <= w
+	}
+	return false
+}
+
+func equalValue(value interface{}, want string) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.EqualFold(v, want)
+	case bool:
+		return strconv.FormatBool(v) == strings.ToLower(want)
+	}
+	n, ok := numberValue(value)
+	if !ok {
+		return false
+	}
+	w, err := strconv.ParseFloat(want, 64)
+	return err == nil && n == w
+}
+
+func numberValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// lookupField returns the raw value of the possibly nested field path of doc.
+func lookupField(doc map[string]interface{}, path string) (interface{}, bool) {
+	if v, ok := doc[path]; ok {
+		return v, true
+	}
+	for key, v := range doc {
+		if !strings.HasPrefix(path, key+".") {
+			continue
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			if found, ok := lookupField(nested, path[len(key)+1:]); ok {
+				return found, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// fieldValues returns the values of the possibly nested field path of doc, arrays are flattened
+// and objects inside arrays are traversed.
+func fieldValues(doc map[string]interface{}, path string) ([]interface{}, bool) {
+	var (
+		values []interface{}
+		exists bool
+	)
+	var collect func(v interface{}, rest string)
+	collect = func(v interface{}, rest string) {
+		switch v := v.(type) {
+		case []interface{}:
+			for _, item := range v {
+				collect(item, rest)
+			}
+		case map[string]interface{}:
+			if rest == "" {
+				return
+			}
+			for key, sub := range v {
+				switch {
+				case key == rest:
+					exists = true
+					collect(sub, "")
+				case strings.HasPrefix(rest, key+"."):
+					collect(sub, rest[len(key)+1:])
+				}
+			}
+		default:
+			if rest == "" {
+				values = append(values, v)
+			}
+		}
+	}
+	collect(doc, path)
+	return values, exists
+}
+
+// parseFilter parses a filter given as a string or as an array of strings and arrays of strings,
+// the elements of the outer array are ANDed and those of the inner arrays ORed.
+func parseFilter(filter interface{}) (filterExpr, error) {
+	switch f := filter.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if strings.TrimSpace(f) == "" {
+			return nil, nil
+		}
+		p := &filterParser{tokens: tokenizeFilter(f), input: f}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.tokens) {
+			return nil, fmt.Errorf("Found unexpected characters at the end of the filter: `%s`. You probably "+
+				"forgot an `OR` or an `AND` rule.", p.tokens[p.pos].text)
+		}
+		return expr, nil
+	case []interface{}:
+		var and andExpr
+		for _, item := range f {
+			switch item := item.(type) {
+			case string:
+				expr, err := parseFilter(item)
+				if err != nil {
+					return nil, err
+				}
+				if expr != nil {
+					and = append(and, expr)
+				}
+			case []interface{}:
+				var or orExpr
+				for _, sub := range item {
+					s, ok := sub.(string)
+					if !ok {
+						return nil, fmt.Errorf("Invalid type for filter subexpression: expected: String, found: %v.", sub)
+					}
+					expr, err := parseFilter(s)
+					if err != nil {
+						return nil, err
+					}
+					if expr != nil {
+						or = append(or, expr)
+					}
+				}
+				if len(or) > 0 {
+					and = append(and, or)
+				}
+			default:
+				return nil, fmt.Errorf("Invalid type for filter subexpression: expected: String, Array, found: %v.", item)
+			}
+		}
+		if len(and) == 0 {
+			return nil, nil
+		}
+		return and, nil
+	}
+	return nil, fmt.Errorf("Invalid syntax for the filter parameter: `expected String, Array, found: %v`.", filter)
+}
+
+// compileFilter parses filter and checks it only uses filterable attributes of idx, code is the
+// error code of invalid filters.
+func (idx *indexState) compileFilter(filter interface{}, code meilisearch.APIErrCode) (filterExpr, error) {
+	expr, err := parseFilter(filter)
+	if err != nil {
+		return nil, newAPIError(http.StatusBadRequest, code, "%s", err)
+	}
+	if expr == nil {
+		return nil, nil
+	}
+	patterns := idx.filterablePatterns()
+	for _, attr := range expr.attributes() {
+		if !matchAttributePatterns(patterns, attr) {
+			available := append([]string(nil), patterns...)
+			return nil, newAPIError(http.StatusBadRequest, code,
+				"Index `%s`: Attribute `%s` is not filterable. Available filterable attribute patterns are: `%s`.",
+				idx.uid, attr, strings.Join(available, ", "))
+		}
+	}
+	return expr, nil
+}
+
+// filterablePatterns returns the attribute patterns of the filterableAttributes setting, which
+// holds attribute names and objects with attributePatterns.
+func (idx *indexState) filterablePatterns() []string {
+	var patterns []string
+	list, _ := idx.setting("filterableAttributes").([]interface{})
+	for _, item := range list {
+		switch v := item.(type) {
+		case string:
+			patterns = append(patterns, v)
+		case map[string]interface{}:
+			attrs, _ := stringList(v["attributePatterns"])
+			patterns = append(patterns, attrs...)
+		}
+	}
+	return patterns
+}
+
+// matchAttributePatterns reports whether attribute matches one of the patterns, a pattern
+// matches its nested fields and may start or end with a `*` wildcard.
+func matchAttributePatterns(patterns []string, attribute string) bool {
+	for _, pattern := range patterns {
+		switch {
+		case pattern == "*" || pattern == attribute || strings.HasPrefix(attribute, pattern+"."):
+			return true
+		case strings.HasSuffix(pattern, "*") && strings.HasPrefix(attribute, pattern[:len(pattern)-1]):
+			return true
+		case strings.HasPrefix(pattern, "*") && strings.HasSuffix(attribute, pattern[1:]):
+			return true
+		}
+	}
+	return false
+}
+
+type filterToken struct {
+	text string
+	// quoted tokens are values that are never keywords
+	quoted bool
+}
+
+// tokenizeFilter splits a filter into words, quoted strings, operators and punctuation.
+func tokenizeFilter(s string) []filterToken {
+	var tokens []filterToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']' || c == ',':
+			tokens = append(tokens, filterToken{text: string(c)})
+			i++
+		case c == '!' || c == '>' || c == '<' || c == '=':
+			n := 1
+			if i+1 < len(s) && s[i+1] == '=' && c != '=' {
+				n = 2
+			}
+			tokens = append(tokens, filterToken{text: s[i : i+n]})
+			i += n
+		case c == '\'' || c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				b.WriteByte(s[j])
+			}
+			tokens = append(tokens, filterToken{text: b.String(), quoted: true})
+			i = j + 1
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\n\r()[],!<>='\"", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, filterToken{text: s[i:j]})
+			i = j
+		}
+	}
+	return tokens
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+	input  string
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// keyword reports whether the next token is the unquoted keyword kw and consumes it.
+func (p *filterParser) keyword(kw string) bool {
+	tok, ok := p.peek()
+	if ok && !tok.quoted && strings.EqualFold(tok.text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) punct(text string) bool {
+	tok, ok := p.peek()
+	if ok && !tok.quoted && tok.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := orExpr{left}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, right)
+	}
+	if len(or) == 1 {
+		return left, nil
+	}
+	return or, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	and := andExpr{left}
+	for p.keyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, right)
+	}
+	if len(and) == 1 {
+		return left, nil
+	}
+	return and, nil
+}
+
+func (p *filterParser) parseNot() (filterExpr, error) {
+	if p.keyword("NOT") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	}
+	if p.punct("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.punct(")") {
+			return nil, fmt.Errorf("Expression `%s` is missing the closing parenthesis `)`.", p.input)
+		}
+		return expr, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *filterParser) value() (string, error) {
+	tok, ok := p.peek()
+	if !ok || (!tok.quoted && strings.ContainsAny(tok.text, "()[],!<>=")) || (!tok.quoted && tok.text == "") {
+		return "", p.unexpected()
+	}
+	p.pos++
+	return tok.text, nil
+}
+
+func (p *filterParser) unexpected() error {
+	return fmt.Errorf("Was expecting an operation `=`, `!=`, `>=`, `>`, `<=`, `<`, `IN`, `NOT IN`, `TO`, "+
+		"`EXISTS`, `NOT EXISTS`, `IS NULL`, `IS NOT NULL`, `IS EMPTY`, `IS NOT EMPTY`, `CONTAINS`, "+
+		"`NOT CONTAINS`, `STARTS WITH`, `NOT STARTS WITH`, `_geoRadius`, or `_geoBoundingBox` at `%s`.", p.input)
+}
+
+func (p *filterParser) parseCondition() (filterExpr, error) {
+	tok, ok := p.peek()
+	if ok && !tok.quoted && (strings.HasPrefix(tok.text, "_geo")) {
+		return nil, fmt.Errorf("The `%s` filter is not supported by meilisearchtest.", tok.text)
+	}
+	attribute, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range []string{"=", "!=", ">=", ">", "<=", "<"} {
+		if p.punct(op) {
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			return &condition{attribute: attribute, op: op, values: []string{value}}, nil
+		}
+	}
+
+	negate := p.keyword("NOT")
+	var cond *condition
+	switch {
+	case p.keyword("EXISTS"):
+		cond = &condition{attribute: attribute, op: "EXISTS"}
+	case p.keyword("IN"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		cond = &condition{attribute: attribute, op: "IN", values: values}
+	case p.keyword("CONTAINS"):
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		cond = &condition{attribute: attribute, op: "CONTAINS", values: []string{value}}
+	case p.keyword("STARTS"):
+		if !p.keyword("WITH") {
+			return nil, p.unexpected()
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		cond = &condition{attribute: attribute, op: "STARTS WITH", values: []string{value}}
+	case !negate && p.keyword("IS"):
+		negate = p.keyword("NOT")
+		switch {
+		case p.keyword("NULL"):
+			cond = &condition{attribute: attribute, op: "NULL"}
+		case p.keyword("EMPTY"):
+			cond = &condition{attribute: attribute, op: "EMPTY"}
+		default:
+			return nil, p.unexpected()
+		}
+	case !negate:
+		from, err := p.value()
+		if err != nil {
+			return nil, p.unexpected()
+		}
+		if !p.keyword("TO") {
+			return nil, p.unexpected()
+		}
+		to, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		cond = &condition{attribute: attribute, op: "TO", values: []string{from, to}}
+	default:
+		return nil, p.unexpected()
+	}
+	if negate {
+		return notExpr{cond}, nil
+	}
+	return cond, nil
+}
+
+func (p *filterParser) parseList() ([]string, error) {
+	if !p.punct("[") {
+		return nil, fmt.Errorf("Expected `[` after `IN` keyword in `%s`.", p.input)
+	}
+	var values []string
+	for !p.punct("]") {
+		value, err := p.value()
+		if err != nil {
+			return nil, fmt.Errorf("Expected matching `]` after the list of values in `%s`.", p.input)
+		}
+		values = append(values, value)
+		if !p.punct(",") {
+			if !p.punct("]") {
+				return nil, fmt.Errorf("Expected matching `]` after the list of values in `%s`.", p.input)
+			}
+			break
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,465 @@
+package meilisearchtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// document is a stored document, its numbers are json.Number.
+type document = map[string]interface{}
+
+// indexState is an index with its documents and settings.
+type indexState struct {
+	uid        string
+	primaryKey string
+	createdAt  time.Time
+	updatedAt  time.Time
+	// documents by id, order holds the ids in insertion order
+	documents map[string]document
+	order     []string
+	// settings holds the settings differing from their default value
+	settings map[string]interface{}
+}
+
+func newIndexState(uid, primaryKey string) *indexState {
+	createdAt := now()
+	return &indexState{
+		uid:        uid,
+		primaryKey: primaryKey,
+		createdAt:  createdAt,
+		updatedAt:  createdAt,
+		documents:  make(map[string]document),
+		settings:   make(map[string]interface{}),
+	}
+}
+
+func (idx *indexState) view() map[string]interface{} {
+	return map[string]interface{}{
+		"uid":        idx.uid,
+		"createdAt":  idx.createdAt,
+		"updatedAt":  idx.updatedAt,
+		"primaryKey": nullString(idx.primaryKey),
+	}
+}
+
+// orderedDocuments returns the documents in insertion order.
+func (idx *indexState) orderedDocuments() []document {
+	docs := make([]document, 0, len(idx.order))
+	for _, id := range idx.order {
+		docs = append(docs, idx.documents[id])
+	}
+	return docs
+}
+
+func (idx *indexState) deleteDocuments(ids map[string]bool) int {
+	deleted := 0
+	kept := idx.order[:0]
+	for _, id := range idx.order {
+		if ids[id] {
+			delete(idx.documents, id)
+			deleted++
+			continue
+		}
+		kept = append(kept, id)
+	}
+	idx.order = kept
+	return deleted
+}
+
+func (idx *indexState) clear() int {
+	n := len(idx.order)
+	idx.documents = make(map[string]document)
+	idx.order = nil
+	return n
+}
+
+// validIndexUID reports whether uid is a valid index uid.
+func validIndexUID(uid string) bool {
+	if uid == "" || len(uid) > 400 {
+		return false
+	}
+	for _, c := range uid {
+		if c > unicode.MaxASCII || !(unicode.IsLetter(c) || unicode.IsDigit(c) || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func invalidIndexUID(uid string, code meilisearch.APIErrCode) *apiError {
+	return newAPIError(http.StatusBadRequest, code,
+		"`%s` is not a valid index uid. Index uid can be an integer or a string containing only alphanumeric "+
+			"characters, hyphens (-) and underscores (_), and can not be more than 512 bytes.", uid)
+}
+
+func indexNotFound(uid string) *apiError {
+	return newAPIError(http.StatusNotFound, meilisearch.APIErrCodeIndexNotFound, "Index `%s` not found.", uid)
+}
+
+// index returns the index uid, or an index_not_found error.
+func (s *Server) index(uid string) (*indexState, error) {
+	idx, ok := s.indexes[uid]
+	if !ok {
+		return nil, indexNotFound(uid)
+	}
+	return idx, nil
+}
+
+// isIndexing reports whether tasks of the index are enqueued or processing.
+func (s *Server) isIndexing(uid string) bool {
+	for _, t := range s.tasks {
+		if t.indexUID == uid && !t.finished() {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) indexRoutes(segments []string) map[string]*route {
+	if len(segments) == 0 {
+		return map[string]*route{
+			http.MethodGet:  {action: "indexes.get", handle: s.listIndexes},
+			http.MethodPost: {action: "indexes.create", handle: s.createIndex},
+		}
+	}
+
+	uid := segments[0]
+	if len(segments) == 1 {
+		return map[string]*route{
+			http.MethodGet: {action: "indexes.get", index: uid, handle: func(r *request) (int, interface{}, error) {
+				idx, err := s.index(uid)
+				if err != nil {
+					return 0, nil, err
+				}
+				return http.StatusOK, idx.view(), nil
+			}},
+			http.MethodPatch: {action: "indexes.update", index: uid, handle: func(r *request) (int, interface{}, error) {
+				return s.updateIndex(r, uid)
+			}},
+			http.MethodDelete: {action: "indexes.delete", index: uid, handle: func(r *request) (int, interface{}, error) {
+				return s.deleteIndex(r, uid)
+			}},
+		}
+	}
+
+	switch segments[1] {
+	case "stats":
+		if len(segments) == 2 {
+			return map[string]*route{http.MethodGet: {action: "stats.get", index: uid, handle: func(r *request) (int, interface{}, error) {
+				idx, err := s.index(uid)
+				if err != nil {
+					return 0, nil, err
+				}
+				return http.StatusOK, s.indexStats(idx), nil
+			}}}
+		}
+	case "compact":
+		if len(segments) == 2 {
+			return map[string]*route{http.MethodPost: {action: "indexes.compact", index: uid, handle: func(r *request) (int, interface{}, error) {
+				if _, err := s.index(uid); err != nil {
+					return 0, nil, err
+				}
+				t := s.enqueue(r, uid, "indexCompaction", map[string]interface{}{}, func(t *task) error {
+					_, err := s.index(uid)
+					return err
+				})
+				return http.StatusAccepted, t.info(), nil
+			}}}
+		}
+	case "documents":
+		return s.documentRoutes(uid, segments[2:])
+	case "settings":
+		return s.settingsRoutes(uid, segments[2:])
+	case "search":
+		if len(segments) == 2 {
+			handle := func(r *request) (int, interface{}, error) {
+				return s.search(r, uid)
+			}
+			return map[string]*route{
+				http.MethodGet:  {action: "search", index: uid, handle: handle},
+				http.MethodPost: {action: "search", index: uid, handle: handle},
+			}
+		}
+	case "facet-search":
+		if len(segments) == 2 {
+			return map[string]*route{http.MethodPost: {action: "search", index: uid, handle: func(r *request) (int, interface{}, error) {
+				return s.facetSearch(r, uid)
+			}}}
+		}
+	}
+	return nil
+}
+
+func (s *Server) listIndexes(r *request) (int, interface{}, error) {
+	offset, limit, err := offsetLimit(r, meilisearch.APIErrCodeInvalidIndexOffset, meilisearch.APIErrCodeInvalidIndexLimit)
+	if err != nil {
+		return 0, nil, err
+	}
+	uids := make([]string, 0, len(s.indexes))
+	for uid := range s.indexes {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	results := make([]interface{}, 0)
+	for i := offset; i < len(uids) && len(results) < limit; i++ {
+		results = append(results, s.indexes[uids[i]].view())
+	}
+	return http.StatusOK, map[string]interface{}{
+		"results": results,
+		"offset":  offset,
+		"limit":   limit,
+		"total":   len(uids),
+	}, nil
+}
+
+func (s *Server) createIndex(r *request) (int, interface{}, error) {
+	var payload struct {
+		UID        *string     `json:"uid"`
+		PrimaryKey interface{} `json:"primaryKey"`
+	}
+	if err := decodeJSON(r, &payload); err != nil {
+		return 0, nil, err
+	}
+	if payload.UID == nil {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingIndexUID, "Missing field `uid`")
+	}
+	uid := *payload.UID
+	if !validIndexUID(uid) {
+		return 0, nil, invalidIndexUID(uid, meilisearch.APIErrCodeInvalidIndexUID)
+	}
+	primaryKey, err := primaryKeyPayload(payload.PrimaryKey)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	t := s.enqueue(r, uid, meilisearch.TaskTypeIndexCreation, map[string]interface{}{"primaryKey": nullString(primaryKey)},
+		func(t *task) error {
+			if _, ok := s.indexes[uid]; ok {
+				return newAPIError(http.StatusConflict, meilisearch.APIErrCodeIndexAlreadyExists, "Index `%s` already exists.", uid)
+			}
+			s.indexes[uid] = newIndexState(uid, primaryKey)
+			return nil
+		})
+	return http.StatusAccepted, t.info(), nil
+}
+
+func primaryKeyPayload(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	primaryKey, ok := v.(string)
+	if !ok {
+		return "", newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidIndexPrimaryKey,
+			"Invalid value type at `.primaryKey`: expected a string, but found something else")
+	}
+	return primaryKey, nil
+}
+
+func (s *Server) updateIndex(r *request, uid string) (int, interface{}, error) {
+	var payload struct {
+		UID        *string     `json:"uid"`
+		PrimaryKey interface{} `json:"primaryKey"`
+	}
+	if err := decodeJSON(r, &payload); err != nil {
+		return 0, nil, err
+	}
+	primaryKey, err := primaryKeyPayload(payload.PrimaryKey)
+	if err != nil {
+		return 0, nil, err
+	}
+	newUID := ""
+	if payload.UID != nil && *payload.UID != uid {
+		newUID = *payload.UID
+		if !validIndexUID(newUID) {
+			return 0, nil, invalidIndexUID(newUID, meilisearch.APIErrCodeInvalidIndexUID)
+		}
+	}
+
+	details := map[string]interface{}{"primaryKey": nullString(primaryKey)}
+	if newUID != "" {
+		details["oldIndexUid"] = uid
+		details["newIndexUid"] = newUID
+	}
+	t := s.enqueue(r, uid, meilisearch.TaskTypeIndexUpdate, details, func(t *task) error {
+		idx, err := s.index(uid)
+		if err != nil {
+			return err
+		}
+		if primaryKey != "" && primaryKey != idx.primaryKey {
+			if len(idx.order) > 0 {
+				return newAPIError(http.StatusConflict, meilisearch.APIErrCodeIndexPrimaryKeyAlreadyExists,
+					"Index `%s`: Index already has a primary key: `%s`.", uid, idx.primaryKey)
+			}
+			idx.primaryKey = primaryKey
+		}
+		if newUID != "" {
+			if _, ok := s.indexes[newUID]; ok {
+				return newAPIError(http.StatusConflict, meilisearch.APIErrCodeIndexAlreadyExists, "Index `%s` already exists.", newUID)
+			}
+			delete(s.indexes, uid)
+			idx.uid = newUID
+			s.indexes[newUID] = idx
+		}
+		idx.updatedAt = now()
+		return nil
+	})
+	return http.StatusAccepted, t.info(), nil
+}
+
+func (s *Server) deleteIndex(r *request, uid string) (int, interface{}, error) {
+	t := s.enqueue(r, uid, meilisearch.TaskTypeIndexDeletion, map[string]interface{}{"deletedDocuments": 0},
+		func(t *task) error {
+			idx, err := s.index(uid)
+			if err != nil {
+				return err
+			}
+			t.details["deletedDocuments"] = len(idx.order)
+			delete(s.indexes, uid)
+			return nil
+		})
+	return http.StatusAccepted, t.info(), nil
+}
+
+func (s *Server) swapIndexes(r *request) (int, interface{}, error) {
+	var swaps []struct {
+		Indexes []string `json:"indexes"`
+		Rename  bool     `json:"rename"`
+	}
+	if err := decodeJSON(r, &swaps); err != nil {
+		return 0, nil, err
+	}
+	seen := make(map[string]bool)
+	for i, swap := range swaps {
+		if swap.Indexes == nil {
+			return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingSwapIndexes,
+				"Missing field `indexes` at `[%d]`", i)
+		}
+		if len(swap.Indexes) != 2 {
+			return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidSwapIndexes,
+				"Invalid value at `[%d].indexes`: Two indexes must be given for each swap. The list `%s` contains %d indexes.",
+				i, "["+strings.Join(swap.Indexes, ", ")+"]", len(swap.Indexes))
+		}
+		for _, uid := range swap.Indexes {
+			if !validIndexUID(uid) {
+				return 0, nil, invalidIndexUID(uid, meilisearch.APIErrCodeInvalidIndexUID)
+			}
+			if seen[uid] {
+				return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidSwapDuplicateIndexFound,
+					"Indexes must be declared only once during a swap. `%s` was specified several times.", uid)
+			}
+			seen[uid] = true
+		}
+	}
+
+	details := make([]interface{}, 0, len(swaps))
+	for _, swap := range swaps {
+		details = append(details, map[string]interface{}{"indexes": swap.Indexes, "rename": swap.Rename})
+	}
+	t := s.enqueue(r, "", meilisearch.TaskTypeIndexSwap, map[string]interface{}{"swaps": details}, func(t *task) error {
+		var missing []string
+		for _, swap := range swaps {
+			if _, ok := s.indexes[swap.Indexes[0]]; !ok {
+				missing = append(missing, swap.Indexes[0])
+			}
+			if _, ok := s.indexes[swap.Indexes[1]]; !ok && !swap.Rename {
+				missing = append(missing, swap.Indexes[1])
+			}
+		}
+		if len(missing) == 1 {
+			return newAPIError(http.StatusNotFound, meilisearch.APIErrCodeIndexNotFound, "Index `%s` not found.", missing[0])
+		}
+		if len(missing) > 1 {
+			return newAPIError(http.StatusNotFound, meilisearch.APIErrCodeIndexNotFound,
+				"Indexes `%s` not found.", strings.Join(missing, "`, `"))
+		}
+		for _, swap := range swaps {
+			a, b := swap.Indexes[0], swap.Indexes[1]
+			if swap.Rename {
+				if _, ok := s.indexes[b]; ok {
+					return newAPIError(http.StatusConflict, meilisearch.APIErrCodeIndexAlreadyExists, "Index `%s` already exists.", b)
+				}
+				idx := s.indexes[a]
+				delete(s.indexes, a)
+				idx.uid = b
+				s.indexes[b] = idx
+				continue
+			}
+			s.indexes[a], s.indexes[b] = s.indexes[b], s.indexes[a]
+			s.indexes[a].uid, s.indexes[b].uid = a, b
+		}
+		return nil
+	})
+	return http.StatusAccepted, t.info(), nil
+}
+
+func (s *Server) indexStats(idx *indexState) map[string]interface{} {
+	size := 0
+	distribution := make(map[string]int)
+	for _, doc := range idx.documents {
+		if data, err := json.Marshal(doc); err == nil {
+			size += len(data)
+		}
+		for _, field := range flattenFields(doc) {
+			distribution[field]++
+		}
+	}
+	avg := 0
+	if len(idx.order) > 0 {
+		avg = size / len(idx.order)
+	}
+	return map[string]interface{}{
+		"numberOfDocuments":         len(idx.order),
+		"rawDocumentDbSize":         size,
+		"avgDocumentSize":           avg,
+		"isIndexing":                s.isIndexing(idx.uid),
+		"numberOfEmbeddings":        0,
+		"numberOfEmbeddedDocuments": 0,
+		"fieldDistribution":         distribution,
+	}
+}
+
+func (s *Server) globalStats(*request) (int, interface{}, error) {
+	indexes := make(map[string]interface{}, len(s.indexes))
+	size := 0
+	for uid, idx := range s.indexes {
+		stats := s.indexStats(idx)
+		size += stats["rawDocumentDbSize"].(int)
+		indexes[uid] = stats
+	}
+	var lastUpdate *time.Time
+	for _, t := range s.tasks {
+		if t.finishedAt != nil && (lastUpdate == nil || t.finishedAt.After(*lastUpdate)) {
+			lastUpdate = t.finishedAt
+		}
+	}
+	return http.StatusOK, map[string]interface{}{
+		"databaseSize":     size,
+		"usedDatabaseSize": size,
+		"lastUpdate":       lastUpdate,
+		"indexes":          indexes,
+	}, nil
+}
+
+// flattenFields returns the dotted names of the leaf fields of doc, like the field distribution.
+func flattenFields(doc map[string]interface{}) []string {
+	var fields []string
+	var walk func(prefix string, v map[string]interface{})
+	walk = func(prefix string, v map[string]interface{}) {
+		for k, value := range v {
+			if nested, ok := value.(map[string]interface{}); ok {
+				walk(prefix+k+".", nested)
+				continue
+			}
+			fields = append(fields, prefix+k)
+		}
+	}
+	walk("", doc)
+	return fields
+}
@@ -0,0 +1,496 @@
+package meilisearchtest
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// apiKey is an API key, its value is derived from the master key and its uid like on
+// Meilisearch.
+type apiKey struct {
+	uid         string
+	key         string
+	name        string
+	description string
+	actions     []string
+	indexes     []string
+	expiresAt   *time.Time
+	createdAt   time.Time
+	updatedAt   time.Time
+}
+
+func defaultKeys(masterKey string, createdAt time.Time) []*apiKey {
+	keys := []*apiKey{
+		{
+			name:        "Default Search API Key",
+			description: "Use it to search from the frontend",
+			actions:     []string{"search"},
+			indexes:     []string{"*"},
+		},
+		{
+			name:        "Default Admin API Key",
+			description: "Use it for anything that is not a search operation. Caution! Do not expose it on a public frontend",
+			actions:     []string{"*"},
+			indexes:     []string{"*"},
+		},
+	}
+	for _, k := range keys {
+		k.uid = newUUID()
+		k.key = deriveKey(masterKey, k.uid)
+		k.createdAt, k.updatedAt = createdAt, createdAt
+	}
+	return keys
+}
+
+func deriveKey(masterKey, uid string) string {
+	mac := hmac.New(sha256.New, []byte(masterKey))
+	mac.Write([]byte(uid))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func (k *apiKey) view() map[string]interface{} {
+	return map[string]interface{}{
+		"name":        nullString(k.name),
+		"description": nullString(k.description),
+		"key":         k.key,
+		"uid":         k.uid,
+		"actions":     k.actions,
+		"indexes":     k.indexes,
+		"expiresAt":   k.expiresAt,
+		"createdAt":   k.createdAt,
+		"updatedAt":   k.updatedAt,
+	}
+}
+
+func (k *apiKey) expired() bool {
+	return k.expiresAt != nil && !k.expiresAt.After(now())
+}
+
+// allows reports whether the key grants action on the index, an empty index is not checked.
+func (k *apiKey) allows(action, index string) bool {
+	allowed := false
+	for _, a := range k.actions {
+		if a == "*" || a == action || (strings.HasSuffix(a, ".*") && strings.HasPrefix(action, strings.TrimSuffix(a, "*"))) {
+			allowed = true
+			break
+		}
+	}
+	return allowed && (index == "" || matchIndexPatterns(k.indexes, index))
+}
+
+// matchIndexPatterns reports whether index matches one of the patterns, which may end with *.
+func matchIndexPatterns(patterns []string, index string) bool {
+	for _, p := range patterns {
+		if p == index || (strings.HasSuffix(p, "*") && strings.HasPrefix(index, strings.TrimSuffix(p, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize checks the Authorization header of r against the action and index of rt. For
+// tenant tokens, it returns the filters of their search rules by index.
+func (s *Server) authorize(r *http.Request, rt *route) (map[string]interface{}, error) {
+	if s.masterKey == "" || rt.action == "" {
+		return nil, nil
+	}
+	auth := r.Header.Get("Authorization")
+	if auth == "" {
+		return nil, newAPIError(http.StatusUnauthorized, meilisearch.APIErrCodeMissingAuthorizationHeader,
+			"The Authorization header is missing. It must use the bearer authorization method.")
+	}
+	invalid := newAPIError(http.StatusForbidden, meilisearch.APIErrCodeInvalidAPIKey, "The provided API key is invalid.")
+	token, ok := strings.CutPrefix(auth, "Bearer ")
+	if !ok {
+		return nil, invalid
+	}
+	if token == s.masterKey {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if k := s.keyByValue(token); k != nil {
+		if k.expired() || !k.allows(rt.action, rt.index) {
+			return nil, invalid
+		}
+		return nil, nil
+	}
+	if rt.action != "search" {
+		return nil, invalid
+	}
+	filters, ok := s.tenantTokenFilters(token, rt.index)
+	if !ok {
+		return nil, invalid
+	}
+	return filters, nil
+}
+
+func (s *Server) keyByValue(value string) *apiKey {
+	for _, k := range s.keys {
+		if k.key == value {
+			return k
+		}
+	}
+	return nil
+}
+
+func (s *Server) keyByKeyOrUID(keyOrUID string) *apiKey {
+	for _, k := range s.keys {
+		if k.key == keyOrUID || k.uid == keyOrUID {
+			return k
+		}
+	}
+	return nil
+}
+
+// tenantTokenFilters validates a tenant token for a search on index, it returns the filters of
+// its search rules by index.
+func (s *Server) tenantTokenFilters(token, index string) (map[string]interface{}, bool) {
+	claims := &meilisearch.TenantTokenClaims{}
+	var parent *apiKey
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		for _, k := range s.keys {
+			if k.uid == claims.APIKeyUID {
+				parent = k
+				return []byte(k.key), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown api key uid %q", claims.APIKeyUID)
+	}, jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}))
+	if err != nil || parent.expired() || !parent.allows("search", index) {
+		return nil, false
+	}
+
+	filters := make(map[string]interface{})
+	switch rules := claims.SearchRules.(type) {
+	case []interface{}:
+		for _, rule := range rules {
+			if pattern, ok := rule.(string); ok {
+				filters[pattern] = nil
+			}
+		}
+	case map[string]interface{}:
+		for pattern, rule := range rules {
+			var filter interface{}
+			if m, ok := rule.(map[string]interface{}); ok {
+				filter = m["filter"]
+			}
+			filters[pattern] = filter
+		}
+	default:
+		return nil, false
+	}
+
+	if index != "" {
+		patterns := make([]string, 0, len(filters))
+		for p := range filters {
+			patterns = append(patterns, p)
+		}
+		if !matchIndexPatterns(patterns, index) {
+			return nil, false
+		}
+	}
+	return filters, true
+}
+
+// tenantFilter returns the filter the tenant token of r enforces on index, nil if none.
+func tenantFilter(r *request, index string) interface{} {
+	if r.filters == nil {
+		return nil
+	}
+	if filter, ok := r.filters[index]; ok {
+		return filter
+	}
+	for pattern, filter := range r.filters {
+		if matchIndexPatterns([]string{pattern}, index) {
+			return filter
+		}
+	}
+	return nil
+}
+
+func (s *Server) keyRoutes(segments []string) map[string]*route {
+	switch len(segments) {
+	case 0:
+		return map[string]*route{
+			http.MethodGet:  {action: "keys.get", handle: s.listKeys},
+			http.MethodPost: {action: "keys.create", handle: s.createKey},
+		}
+	case 1:
+		keyOrUID := segments[0]
+		return map[string]*route{
+			http.MethodGet: {action: "keys.get", handle: func(r *request) (int, interface{}, error) {
+				k, err := s.pathKey(keyOrUID)
+				if err != nil {
+					return 0, nil, err
+				}
+				return http.StatusOK, k.view(), nil
+			}},
+			http.MethodPatch: {action: "keys.update", handle: func(r *request) (int, interface{}, error) {
+				return s.updateKey(r, keyOrUID)
+			}},
+			http.MethodDelete: {action: "keys.delete", handle: func(r *request) (int, interface{}, error) {
+				k, err := s.pathKey(keyOrUID)
+				if err != nil {
+					return 0, nil, err
+				}
+				for i, other := range s.keys {
+					if other == k {
+						s.keys = append(s.keys[:i], s.keys[i+1:]...)
+						break
+					}
+				}
+				return http.StatusNoContent, nil, nil
+			}},
+		}
+	}
+	return nil
+}
+
+func (s *Server) requireMasterKey() error {
+	if s.masterKey == "" {
+		return newAPIError(http.StatusUnauthorized, meilisearch.APIErrCodeMissingMasterKey,
+			"Meilisearch is running without a master key. To access this API endpoint, you must have set a master key at launch.")
+	}
+	return nil
+}
+
+func (s *Server) pathKey(keyOrUID string) (*apiKey, error) {
+	if err := s.requireMasterKey(); err != nil {
+		return nil, err
+	}
+	k := s.keyByKeyOrUID(keyOrUID)
+	if k == nil {
+		return nil, newAPIError(http.StatusNotFound, meilisearch.APIErrCodeAPIKeyNotFound, "API key `%s` not found.", keyOrUID)
+	}
+	return k, nil
+}
+
+func (s *Server) listKeys(r *request) (int, interface{}, error) {
+	if err := s.requireMasterKey(); err != nil {
+		return 0, nil, err
+	}
+	offset, limit, err := offsetLimit(r, meilisearch.APIErrCodeInvalidAPIKeyOffset, meilisearch.APIErrCodeInvalidAPIKeyLimit)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// the most recent keys come first
+	results := make([]interface{}, 0)
+	for i := len(s.keys) - 1 - offset; i >= 0 && len(results) < limit; i-- {
+		results = append(results, s.keys[i].view())
+	}
+	return http.StatusOK, map[string]interface{}{
+		"results": results,
+		"offset":  offset,
+		"limit":   limit,
+		"total":   len(s.keys),
+	}, nil
+}
+
+type keyPayload struct {
+	Name        *string     `json:"name"`
+	Description *string     `json:"description"`
+	UID         *string     `json:"uid"`
+	Key         interface{} `json:"key"`
+	Actions     interface{} `json:"actions"`
+	Indexes     interface{} `json:"indexes"`
+	ExpiresAt   interface{} `json:"expiresAt"`
+	CreatedAt   interface{} `json:"createdAt"`
+	UpdatedAt   interface{} `json:"updatedAt"`
+}
+
+func (s *Server) createKey(r *request) (int, interface{}, error) {
+	if err := s.requireMasterKey(); err != nil {
+		return 0, nil, err
+	}
+	var payload keyPayload
+	if err := decodeJSON(r, &payload); err != nil {
+		return 0, nil, err
+	}
+
+	k := &apiKey{createdAt: now()}
+	k.updatedAt = k.createdAt
+	if payload.Name != nil {
+		k.name = *payload.Name
+	}
+	if payload.Description != nil {
+		k.description = *payload.Description
+	}
+
+	k.uid = newUUID()
+	if payload.UID != nil {
+		if !validUUID(*payload.UID) {
+			return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidAPIKeyUID,
+				"Invalid value at `.uid`: invalid character: expected an optional prefix of `urn:uuid:` followed by "+
+					"[0-9a-fA-F-], found `%s`", *payload.UID)
+		}
+		k.uid = strings.ToLower(*payload.UID)
+		if s.keyByKeyOrUID(k.uid) != nil {
+			return 0, nil, newAPIError(http.StatusConflict, meilisearch.APIErrCodeAPIKeyAlreadyExists,
+				"`uid` field value `%s` is already an existing API key.", k.uid)
+		}
+	}
+
+	var ok bool
+	if payload.Actions == nil {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingAPIKeyActions,
+			"Missing field `actions`")
+	}
+	if k.actions, ok = stringList(payload.Actions); !ok {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidAPIKeyActions,
+			"Invalid value type at `.actions`: expected an array, but found something else")
+	}
+	if payload.Indexes == nil {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingAPIKeyIndexes,
+			"Missing field `indexes`")
+	}
+	if k.indexes, ok = stringList(payload.Indexes); !ok {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidAPIKeyIndexes,
+			"Invalid value type at `.indexes`: expected an array, but found something else")
+	}
+	if payload.ExpiresAt != nil {
+		value, _ := payload.ExpiresAt.(string)
+		expiresAt, err := parseDate(value)
+		if err != nil || !expiresAt.After(now()) {
+			return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidAPIKeyExpiresAt,
+				"`%v` is not a valid date. It should follow the RFC 3339 format to represents a date or datetime "+
+					"in the future or specified as a null value. e.g. 'YYYY-MM-DD' or 'YYYY-MM-DD HH:MM:SS'.", payload.ExpiresAt)
+		}
+		k.expiresAt = &expiresAt
+	}
+
+	k.key = deriveKey(s.masterKey, k.uid)
+	s.keys = append(s.keys, k)
+	return http.StatusCreated, k.view(), nil
+}
+
+func (s *Server) updateKey(r *request, keyOrUID string) (int, interface{}, error) {
+	k, err := s.pathKey(keyOrUID)
+	if err != nil {
+		return 0, nil, err
+	}
+	var payload keyPayload
+	if err := decodeJSON(r, &payload); err != nil {
+		return 0, nil, err
+	}
+
+	immutable := []struct {
+		name  string
+		value interface{}
+		code  meilisearch.APIErrCode
+	}{
+		{"uid", payload.UID, meilisearch.APIErrCodeImmutableAPIKeyUID},
+		{"key", payload.Key, meilisearch.APIErrCodeImmutableAPIKeyKey},
+		{"actions", payload.Actions, meilisearch.APIErrCodeImmutableAPIKeyActions},
+		{"indexes", payload.Indexes, meilisearch.APIErrCodeImmutableAPIKeyIndexes},
+		{"expiresAt", payload.ExpiresAt, meilisearch.APIErrCodeImmutableAPIKeyExpiresAt},
+		{"createdAt", payload.CreatedAt, meilisearch.APIErrCodeImmutableAPIKeyCreatedAt},
+		{"updatedAt", payload.UpdatedAt, meilisearch.APIErrCodeImmutableAPIKeyUpdatedAt},
+	}
+	for _, field := range immutable {
+		if field.value != nil && field.value != (*string)(nil) {
+			return 0, nil, newAPIError(http.StatusBadRequest, field.code, "Immutable field `%s`: expected one of `description`, `name`", field.name)
+		}
+	}
+
+	if payload.Name != nil {
+		k.name = *payload.Name
+	}
+	if payload.Description != nil {
+		k.description = *payload.Description
+	}
+	k.updatedAt = now()
+	return http.StatusOK, k.view(), nil
+}
+
+func validUUID(s string) bool {
+	s = strings.ToLower(s)
+	if len(s) != 36 {
+		return false
+	}
+	for i, c := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdef", c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseDate parses the date formats accepted by Meilisearch.
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// stringList converts a decoded JSON array of strings.
+func stringList(v interface{}) ([]string, bool) {
+	values, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	list := make([]string, 0, len(values))
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		list = append(list, s)
+	}
+	return list, true
+}
+
+// offsetLimit parses the offset and limit query parameters of the keys and indexes lists.
+func offsetLimit(r *request, offsetCode, limitCode meilisearch.APIErrCode) (int, int, error) {
+	query := r.URL.Query()
+	offset, limit := 0, 20
+	if value := query.Get("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return 0, 0, newAPIError(http.StatusBadRequest, offsetCode,
+				"Invalid value in parameter `offset`: could not parse `%s` as a positive integer", value)
+		}
+		offset = n
+	}
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return 0, 0, newAPIError(http.StatusBadRequest, limitCode,
+				"Invalid value in parameter `limit`: could not parse `%s` as a positive integer", value)
+		}
+		limit = n
+	}
+	return offset, limit, nil
+}
@@ -0,0 +1,1475 @@
+package meilisearchtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// searchQuery is the body of a search, also read from the query string of GET /search and
+// from the queries of a multi-search.
+type searchQuery struct {
+	IndexUID                string      `json:"indexUid"`
+	Q                       string      `json:"q"`
+	Offset                  *int        `json:"offset"`
+	Limit                   *int        `json:"limit"`
+	Page                    *int        `json:"page"`
+	HitsPerPage             *int        `json:"hitsPerPage"`
+	Filter                  interface{} `json:"filter"`
+	Sort                    []string    `json:"sort"`
+	Facets                  []string    `json:"facets"`
+	Distinct                string      `json:"distinct"`
+	AttributesToRetrieve    []string    `json:"attributesToRetrieve"`
+	AttributesToSearchOn    []string    `json:"attributesToSearchOn"`
+	AttributesToHighlight   []string    `json:"attributesToHighlight"`
+	AttributesToCrop        []string    `json:"attributesToCrop"`
+	CropLength              *int        `json:"cropLength"`
+	CropMarker              *string     `json:"cropMarker"`
+	HighlightPreTag         *string     `json:"highlightPreTag"`
+	HighlightPostTag        *string     `json:"highlightPostTag"`
+	MatchingStrategy        string      `json:"matchingStrategy"`
+	ShowMatchesPosition     bool        `json:"showMatchesPosition"`
+	ShowRankingScore        bool        `json:"showRankingScore"`
+	ShowRankingScoreDetails bool        `json:"showRankingScoreDetails"`
+	ShowPerformanceDetails  bool        `json:"showPerformanceDetails"`
+	RankingScoreThreshold   *float64    `json:"rankingScoreThreshold"`
+	RetrieveVectors         bool        `json:"retrieveVectors"`
+	Locales                 []string    `json:"locales"`
+	Vector                  []float64   `json:"vector"`
+	Hybrid                  *struct {
+		Embedder      string  `json:"embedder"`
+		SemanticRatio float64 `json:"semanticRatio"`
+	} `json:"hybrid"`
+	FederationOptions *struct {
+		Weight *float64 `json:"weight"`
+	} `json:"federationOptions"`
+}
+
+// searchResult is a search before it is rendered as a response.
+type searchResult struct {
+	query    *searchQuery
+	idx      *indexState
+	hits     []*candidate
+	total    int
+	response map[string]interface{}
+}
+
+func (s *Server) search(r *request, uid string) (int, interface{}, error) {
+	var q searchQuery
+	if r.Method == http.MethodGet {
+		var err error
+		if q, err = searchQueryFromURL(r); err != nil {
+			return 0, nil, err
+		}
+	} else if err := decodeJSON(r, &q); err != nil {
+		return 0, nil, err
+	}
+	res, err := s.runSearch(r, uid, &q, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, res.response, nil
+}
+
+// searchQueryFromURL reads the parameters of GET /search.
+func searchQueryFromURL(r *request) (searchQuery, error) {
+	query := r.URL.Query()
+	q := searchQuery{
+		Q:                     query.Get("q"),
+		Sort:                  splitList(query.Get("sort")),
+		Facets:                splitList(query.Get("facets")),
+		Distinct:              query.Get("distinct"),
+		AttributesToRetrieve:  splitList(query.Get("attributesToRetrieve")),
+		AttributesToSearchOn:  splitList(query.Get("attributesToSearchOn")),
+		AttributesToHighlight: splitList(query.Get("attributesToHighlight")),
+		AttributesToCrop:      splitList(query.Get("attributesToCrop")),
+		MatchingStrategy:      query.Get("matchingStrategy"),
+		ShowRankingScore:      query.Get("showRankingScore") == "true",
+		ShowMatchesPosition:   query.Get("showMatchesPosition") == "true",
+	}
+	if filter := query.Get("filter"); filter != "" {
+		q.Filter = filter
+	}
+	ints := []struct {
+		name string
+		dst  **int
+		code meilisearch.APIErrCode
+	}{
+		{"offset", &q.Offset, meilisearch.APIErrCodeInvalidSearchOffset},
+		{"limit", &q.Limit, meilisearch.APIErrCodeInvalidSearchLimit},
+		{"page", &q.Page, meilisearch.APIErrCodeInvalidSearchPage},
+		{"hitsPerPage", &q.HitsPerPage, meilisearch.APIErrCodeInvalidSearchHitsPerPage},
+	}
+	for _, p := range ints {
+		value := query.Get(p.name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return q, newAPIError(http.StatusBadRequest, p.code,
+				"Invalid value in parameter `%s`: could not parse `%s` as a positive integer", p.name, value)
+		}
+		*p.dst = &n
+	}
+	return q, nil
+}
+
+// runSearch searches the index uid, federated searches keep every hit to merge them later.
+func (s *Server) runSearch(r *request, uid string, q *searchQuery, federated bool) (*searchResult, error) {
+	idx, err := s.index(uid)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSearch(idx, q); err != nil {
+		return nil, err
+	}
+
+	filter, err := idx.compileFilter(q.Filter, meilisearch.APIErrCodeInvalidSearchFilter)
+	if err != nil {
+		return nil, err
+	}
+	var tenant filterExpr
+	if f := tenantFilter(r, uid); f != nil {
+		if tenant, err = idx.compileFilter(f, meilisearch.APIErrCodeInvalidSearchFilter); err != nil {
+			return nil, err
+		}
+	}
+	rules, err := idx.parseSort(q.Sort, meilisearch.APIErrCodeInvalidSearchSort)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := newSearchEngine(idx, q)
+	var candidates []*candidate
+	for i, id := range idx.order {
+		doc := idx.documents[id]
+		if filter != nil && !filter.match(doc) {
+			continue
+		}
+		if tenant != nil && !tenant.match(doc) {
+			continue
+		}
+		c := engine.match(doc, i)
+		if c == nil {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	engine.rank(candidates, rules)
+
+	distinct := q.Distinct
+	if distinct == "" {
+		distinct, _ = idx.setting("distinctAttribute").(string)
+	}
+	if distinct != "" {
+		candidates = distinctCandidates(candidates, distinct)
+	}
+	if q.RankingScoreThreshold != nil {
+		kept := candidates[:0]
+		for _, c := range candidates {
+			if c.score >= *q.RankingScoreThreshold {
+				kept = append(kept, c)
+			}
+		}
+		candidates = kept
+	}
+
+	maxTotalHits := 1000
+	if pagination, ok := idx.setting("pagination").(map[string]interface{}); ok {
+		if n, ok := intValue(pagination["maxTotalHits"]); ok {
+			maxTotalHits = n
+		}
+	}
+	total := len(candidates)
+	if total > maxTotalHits {
+		total = maxTotalHits
+	}
+
+	response := map[string]interface{}{
+		"query":            q.Q,
+		"processingTimeMs": 0,
+	}
+	var from, to int
+	switch {
+	case federated:
+		from, to = 0, total
+	case q.Page != nil || q.HitsPerPage != nil:
+		page, hitsPerPage := 1, 20
+		if q.Page != nil {
+			page = *q.Page
+		}
+		if q.HitsPerPage != nil {
+			hitsPerPage = *q.HitsPerPage
+		}
+		totalPages := 0
+		if hitsPerPage > 0 {
+			totalPages = (total + hitsPerPage - 1) / hitsPerPage
+		}
+		if page > 0 {
+			from, to = (page-1)*hitsPerPage, page*hitsPerPage
+		}
+		response["page"] = page
+		response["hitsPerPage"] = hitsPerPage
+		response["totalHits"] = total
+		response["totalPages"] = totalPages
+	default:
+		offset, limit := 0, 20
+		if q.Offset != nil {
+			offset = *q.Offset
+		}
+		if q.Limit != nil {
+			limit = *q.Limit
+		}
+		from, to = offset, offset+limit
+		response["offset"] = offset
+		response["limit"] = limit
+		response["estimatedTotalHits"] = total
+	}
+	from, to = clamp(from, total), clamp(to, total)
+
+	hits := make([]interface{}, 0, to-from)
+	for _, c := range candidates[from:to] {
+		hits = append(hits, engine.render(c))
+	}
+	response["hits"] = hits
+
+	if q.Facets != nil {
+		facets, err := idx.facetNames(q.Facets, meilisearch.APIErrCodeInvalidSearchFacets)
+		if err != nil {
+			return nil, err
+		}
+		docs := make([]document, 0, len(candidates))
+		for _, c := range candidates {
+			docs = append(docs, c.doc)
+		}
+		distribution, stats := idx.facetDistribution(docs, facets)
+		response["facetDistribution"] = distribution
+		response["facetStats"] = stats
+	}
+	if q.ShowPerformanceDetails {
+		response["performanceDetails"] = map[string]interface{}{
+			"search":           "0.00ms",
+			"search > resolve": "0.00ms",
+		}
+	}
+	return &searchResult{query: q, idx: idx, hits: candidates, total: total, response: response}, nil
+}
+
+func clamp(n, max int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+func intValue(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case json.Number:
+		i, err := strconv.Atoi(n.String())
+		return i, err == nil
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+// validateSearch checks the parameters of q Meilisearch validates before searching.
+func validateSearch(idx *indexState, q *searchQuery) error {
+	checks := []struct {
+		value *int
+		name  string
+		code  meilisearch.APIErrCode
+	}{
+		{q.Offset, "offset", meilisearch.APIErrCodeInvalidSearchOffset},
+		{q.Limit, "limit", meilisearch.APIErrCodeInvalidSearchLimit},
+		{q.Page, "page", meilisearch.APIErrCodeInvalidSearchPage},
+		{q.HitsPerPage, "hitsPerPage", meilisearch.APIErrCodeInvalidSearchHitsPerPage},
+		{q.CropLength, "cropLength", meilisearch.APIErrCodeInvalidSearchCropLength},
+	}
+	for _, c := range checks {
+		if c.value != nil && *c.value < 0 {
+			return newAPIError(http.StatusBadRequest, c.code, "Invalid value at `.%s`: value must be a positive integer", c.name)
+		}
+	}
+	switch q.MatchingStrategy {
+	case "", "last", "all", "frequency":
+	default:
+		return newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidSearchMatchingStrategy,
+			"Unknown value `%s` at `.matchingStrategy`: expected one of `last`, `all`, `frequency`", q.MatchingStrategy)
+	}
+	if t := q.RankingScoreThreshold; t != nil && (*t < 0 || *t > 1) {
+		return newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidSearchRankingScoreThreshold,
+			"Invalid value at `.rankingScoreThreshold`: the value of `rankingScoreThreshold` is invalid, expected a "+
+				"float between `0.0` and `1.0`.")
+	}
+	for _, locale := range q.Locales {
+		if !supportedLocale(locale) {
+			return newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidSearchLocales,
+				"Unknown value `%s` at `.locales`: expected one of %s", locale, strings.Join(locales639, ", "))
+		}
+	}
+	searchable := idx.settingStrings("searchableAttributes")
+	for _, attr := range q.AttributesToSearchOn {
+		if !containsString(searchable, "*") && !matchAttributePatterns(searchable, attr) {
+			return newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidSearchAttributesToSearchOn,
+				"Attribute `%s` is not searchable. Available searchable attributes are: `%s`.",
+				attr, strings.Join(searchable, ", "))
+		}
+	}
+	if q.Distinct != "" && !matchAttributePatterns(idx.filterablePatterns(), q.Distinct) {
+		return newAPIError(http.StatusBadRequest, meilisearch.APIErrCode("invalid_search_distinct"),
+			"Attribute `%s` is not filterable and thus, cannot be used as distinct attribute. Available filterable "+
+				"attributes patterns are: `%s`.", q.Distinct, strings.Join(idx.filterablePatterns(), ", "))
+	}
+
+	embedder := ""
+	if q.Hybrid != nil {
+		embedder = q.Hybrid.Embedder
+	} else if q.Vector != nil {
+		embedder = "default"
+	}
+	if embedder != "" {
+		embedders, _ := idx.setting("embedders").(map[string]interface{})
+		if _, ok := embedders[embedder]; !ok {
+			return newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidSearchEmbedder,
+				"Cannot find embedder with name `%s`.", embedder)
+		}
+	}
+	return nil
+}
+
+// sortRule sorts on an attribute.
+type sortRule struct {
+	attribute string
+	desc      bool
+}
+
+// parseSort parses the sort parameter, the attributes must be sortable.
+func (idx *indexState) parseSort(sortParam []string, code meilisearch.APIErrCode) ([]sortRule, error) {
+	sortable := idx.settingStrings("sortableAttributes")
+	var rules []sortRule
+	for _, s := range sortParam {
+		n := strings.LastIndex(s, ":")
+		if n < 0 || (s[n+1:] != "asc" && s[n+1:] != "desc") {
+			return nil, newAPIError(http.StatusBadRequest, code,
+				"Invalid syntax for the sort parameter: expected expression ending by `:asc` or `:desc`, found `%s`.", s)
+		}
+		attr := s[:n]
+		if strings.HasPrefix(attr, "_geo") {
+			return nil, newAPIError(http.StatusBadRequest, code, "The `%s` sort is not supported by meilisearchtest.", attr)
+		}
+		if !matchAttributePatterns(sortable, attr) {
+			return nil, newAPIError(http.StatusBadRequest, code,
+				"Attribute `%s` is not sortable. Available sortable attributes are: `%s`.", attr, strings.Join(sortable, ", "))
+		}
+		rules = append(rules, sortRule{attribute: attr, desc: s[n+1:] == "desc"})
+	}
+	return rules, nil
+}
+
+// compareSort compares a and b on the rules: numbers come before strings and documents without
+// the attribute come last.
+func compareSort(a, b document, rules []sortRule) int {
+	for _, rule := range rules {
+		va, oka := sortValue(a, rule.attribute, rule.desc)
+		vb, okb := sortValue(b, rule.attribute, rule.desc)
+		switch {
+		case !oka && !okb:
+			continue
+		case !oka:
+			return 1
+		case !okb:
+			return -1
+		}
+		c := compareValues(va, vb)
+		if rule.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// sortValue returns the value of attribute doc is sorted on, the lowest or highest of arrays.
+func sortValue(doc document, attribute string, desc bool) (interface{}, bool) {
+	values, _ := fieldValues(doc, attribute)
+	var best interface{}
+	for _, v := range values {
+		if _, ok := numberValue(v); !ok {
+			if _, ok := v.(string); !ok {
+				continue
+			}
+		}
+		if best == nil {
+			best = v
+			continue
+		}
+		c := compareValues(v, best)
+		if (!desc && c < 0) || (desc && c > 0) {
+			best = v
+		}
+	}
+	return best, best != nil
+}
+
+func compareValues(a, b interface{}) int {
+	na, aNum := numberValue(a)
+	nb, bNum := numberValue(b)
+	switch {
+	case aNum && bNum:
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	sa, _ := a.(string)
+	sb, _ := b.(string)
+	return strings.Compare(strings.ToLower(sa), strings.ToLower(sb))
+}
+
+func sortDocuments(docs []document, rules []sortRule) {
+	if len(rules) == 0 {
+		return
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		return compareSort(docs[i], docs[j], rules) < 0
+	})
+}
+
+// word is a token of a text.
+type word struct {
+	text string
+	// position is the index of the word in its text, start and end its byte offsets
+	position   int
+	start, end int
+}
+
+// isCJK reports whether r is written without spaces, such characters are words on their own.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// tokenize splits text into lowercase words.
+func tokenize(text string) []word {
+	var (
+		words []word
+		start = -1
+	)
+	flush := func(end int) {
+		if start >= 0 {
+			words = append(words, word{text: strings.ToLower(text[start:end]), position: len(words), start: start, end: end})
+			start = -1
+		}
+	}
+	for i, r := range text {
+		switch {
+		case isCJK(r):
+			flush(i)
+			size := utf8.RuneLen(r)
+			words = append(words, word{text: string(r), position: len(words), start: i, end: i + size})
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			if start < 0 {
+				start = i
+			}
+		default:
+			flush(i)
+		}
+	}
+	flush(len(text))
+	return words
+}
+
+// damerauLevenshtein returns the edit distance between a and b, transpositions count as one edit.
+func damerauLevenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// term is a word of the query.
+type term struct {
+	text string
+	// prefix terms also match the words they start, only the last word of a query is a prefix
+	prefix   bool
+	synonyms []string
+}
+
+// occurrence is where a term matches a document.
+type occurrence struct {
+	attribute string
+	rank      int
+	position  int
+	typos     int
+	exact     bool
+	start     int
+	end       int
+	// value is the index of the string value of the attribute the term matches
+	value int
+}
+
+// candidate is a document matching the query with its ranking.
+type candidate struct {
+	doc   document
+	order int
+	// words is the number of matched terms, the other fields rank the matches
+	words     int
+	typos     int
+	proximity int
+	attribute int
+	position  int
+	exact     int
+	matches   []occurrence
+	score     float64
+	details   map[string]interface{}
+}
+
+// searchEngine matches and ranks the documents of an index for a query.
+type searchEngine struct {
+	idx        *indexState
+	query      *searchQuery
+	terms      []term
+	searchable []string
+	typos      map[string]interface{}
+	rules      []string
+}
+
+func newSearchEngine(idx *indexState, q *searchQuery) *searchEngine {
+	e := &searchEngine{idx: idx, query: q, searchable: idx.settingStrings("searchableAttributes")}
+	if len(q.AttributesToSearchOn) > 0 {
+		e.searchable = q.AttributesToSearchOn
+	}
+	e.typos, _ = idx.setting("typoTolerance").(map[string]interface{})
+	e.rules = idx.settingStrings("rankingRules")
+
+	stopWords := idx.settingStrings("stopWords")
+	synonyms, _ := idx.setting("synonyms").(map[string]interface{})
+	words := tokenize(q.Q)
+	var terms []term
+	for _, w := range words {
+		if containsString(stopWords, w.text) {
+			continue
+		}
+		t := term{text: w.text}
+		for key, values := range synonyms {
+			if strings.ToLower(key) != w.text {
+				continue
+			}
+			list, _ := stringList(values)
+			for _, synonym := range list {
+				t.synonyms = append(t.synonyms, strings.ToLower(synonym))
+			}
+		}
+		terms = append(terms, t)
+	}
+	if len(terms) == 0 {
+		for _, w := range words {
+			terms = append(terms, term{text: w.text})
+		}
+	}
+	if len(terms) > 0 && !strings.HasSuffix(q.Q, " ") && !isCJK(lastRune(q.Q)) {
+		terms[len(terms)-1].prefix = true
+	}
+	e.terms = terms
+	return e
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
+
+// attributeRank returns the rank of field in the searchable attributes, -1 when it is not searchable.
+func (e *searchEngine) attributeRank(field string) int {
+	for i, pattern := range e.searchable {
+		if matchAttributePatterns([]string{pattern}, field) {
+			if pattern == "*" {
+				return 0
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// allowedTypos returns the number of typos tolerated for t in attribute.
+func (e *searchEngine) allowedTypos(t term, attribute string) int {
+	if enabled, ok := e.typos["enabled"].(bool); ok && !enabled {
+		return 0
+	}
+	if words, _ := stringList(e.typos["disableOnWords"]); containsString(words, t.text) {
+		return 0
+	}
+	if attrs, _ := stringList(e.typos["disableOnAttributes"]); matchAttributePatterns(attrs, attribute) {
+		return 0
+	}
+	if disabled, _ := e.typos["disableOnNumbers"].(bool); disabled {
+		if _, err := strconv.ParseFloat(t.text, 64); err == nil {
+			return 0
+		}
+	}
+	oneTypo, twoTypos := 5, 9
+	if sizes, ok := e.typos["minWordSizeForTypos"].(map[string]interface{}); ok {
+		if n, ok := intValue(sizes["oneTypo"]); ok {
+			oneTypo = n
+		}
+		if n, ok := intValue(sizes["twoTypos"]); ok {
+			twoTypos = n
+		}
+	}
+	switch n := utf8.RuneCountInString(t.text); {
+	case n >= twoTypos:
+		return 2
+	case n >= oneTypo:
+		return 1
+	}
+	return 0
+}
+
+// matchWord reports whether t matches w with the number of typos and whether it is an exact match.
+func matchWord(t term, w string, allowed int) (bool, int, bool) {
+	if w == t.text || containsString(t.synonyms, w) {
+		return true, 0, true
+	}
+	if t.prefix && strings.HasPrefix(w, t.text) {
+		return true, 0, false
+	}
+	if allowed == 0 {
+		return false, 0, false
+	}
+	if d := damerauLevenshtein(t.text, w); d <= allowed {
+		return true, d, false
+	}
+	if t.prefix {
+		if r := []rune(w); len(r) > utf8.RuneCountInString(t.text) {
+			if d := damerauLevenshtein(t.text, string(r[:utf8.RuneCountInString(t.text)])); d <= allowed {
+				return true, d, false
+			}
+		}
+	}
+	return false, 0, false
+}
+
+// searchableTexts returns the searchable string values of doc by attribute.
+func (e *searchEngine) searchableTexts(doc document) map[string][]string {
+	texts := make(map[string][]string)
+	for _, field := range flattenFields(doc) {
+		if e.attributeRank(field) < 0 {
+			continue
+		}
+		values, _ := fieldValues(doc, field)
+		for _, v := range values {
+			switch v := v.(type) {
+			case string:
+				texts[field] = append(texts[field], v)
+			case json.Number:
+				texts[field] = append(texts[field], v.String())
+			}
+		}
+	}
+	return texts
+}
+
+// match returns the candidate of doc, nil when it does not match the query.
+func (e *searchEngine) match(doc document, order int) *candidate {
+	c := &candidate{doc: doc, order: order}
+	if len(e.terms) == 0 {
+		return c
+	}
+
+	texts := e.searchableTexts(doc)
+	fields := make([]string, 0, len(texts))
+	for field := range texts {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	best := make([]*occurrence, len(e.terms))
+	for i, t := range e.terms {
+		for _, field := range fields {
+			rank := e.attributeRank(field)
+			allowed := e.allowedTypos(t, field)
+			for v, text := range texts[field] {
+				for _, w := range tokenize(text) {
+					ok, typos, exact := matchWord(t, w.text, allowed)
+					if !ok {
+						continue
+					}
+					o := occurrence{attribute: field, rank: rank, position: w.position, typos: typos, exact: exact,
+						start: w.start, end: w.end, value: v}
+					c.matches = append(c.matches, o)
+					if b := best[i]; b == nil || o.typos < b.typos || (o.typos == b.typos && (o.rank < b.rank ||
+						(o.rank == b.rank && o.position < b.position))) {
+						best[i] = &o
+					}
+				}
+			}
+		}
+	}
+
+	matched := 0
+	switch e.query.MatchingStrategy {
+	case "all":
+		for _, b := range best {
+			if b == nil {
+				return nil
+			}
+		}
+		matched = len(best)
+	case "frequency":
+		for _, b := range best {
+			if b != nil {
+				matched++
+			}
+		}
+	default:
+		for matched < len(best) && best[matched] != nil {
+			matched++
+		}
+	}
+	if matched == 0 {
+		return nil
+	}
+
+	c.words = matched
+	c.attribute = math.MaxInt32
+	var previous *occurrence
+	for _, b := range best {
+		if b == nil {
+			continue
+		}
+		c.typos += b.typos
+		c.position += b.position
+		if b.rank < c.attribute {
+			c.attribute = b.rank
+		}
+		if b.exact {
+			c.exact++
+		}
+		if previous != nil {
+			distance := 8
+			if previous.attribute == b.attribute && previous.value == b.value {
+				if d := b.position - previous.position; d > 0 && d < 8 {
+					distance = d
+				}
+			}
+			c.proximity += distance
+		}
+		previous = b
+	}
+	return c
+}
+
+// rank sorts the candidates by the ranking rules and computes their ranking scores.
+func (e *searchEngine) rank(candidates []*candidate, sortRules []sortRule) {
+	rules := e.rules
+	if !containsString(rules, "sort") && len(sortRules) > 0 {
+		rules = append(append([]string(nil), rules...), "sort")
+	}
+	placeholder := len(e.terms) == 0
+
+	compare := func(a, b *candidate) int {
+		for _, rule := range rules {
+			var c int
+			switch rule {
+			case "words":
+				c = b.words - a.words
+			case "typo":
+				c = a.typos - b.typos
+			case "proximity":
+				c = a.proximity - b.proximity
+			case "attribute", "attributeRank":
+				c = a.attribute - b.attribute
+			case "wordPosition":
+				c = a.position - b.position
+			case "exactness":
+				c = b.exact - a.exact
+			case "sort":
+				c = compareSort(a.doc, b.doc, sortRules)
+			default:
+				if n := strings.LastIndex(rule, ":"); n > 0 {
+					c = compareSort(a.doc, b.doc, []sortRule{{attribute: rule[:n], desc: rule[n+1:] == "desc"}})
+				}
+			}
+			if placeholder && rule != "sort" && !strings.Contains(rule, ":") {
+				c = 0
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return a.order - b.order
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return compare(candidates[i], candidates[j]) < 0
+	})
+
+	for _, c := range candidates {
+		e.score(c, rules, placeholder)
+	}
+}
+
+// score computes the ranking score of c, each relevancy rule refines the score of the previous
+// ones like the digits of a number.
+func (e *searchEngine) score(c *candidate, rules []string, placeholder bool) {
+	c.score = 1
+	c.details = make(map[string]interface{})
+	if placeholder {
+		return
+	}
+	n := len(e.terms)
+	var rank, maxRank float64 = 0, 1
+	for order, rule := range rules {
+		var r, m int
+		detail := map[string]interface{}{"order": order}
+		switch rule {
+		case "words":
+			r, m = c.words, n
+			detail["matchingWords"], detail["maxMatchingWords"] = c.words, n
+		case "typo":
+			m = 2 * n
+			r = m - c.typos
+			detail["typoCount"], detail["maxTypoCount"] = c.typos, m
+		case "proximity":
+			m = 8 * (n - 1)
+			r = m - c.proximity
+		case "attribute", "attributeRank":
+			m = len(e.searchable) - 1
+			r = m - c.attribute
+			if m <= 0 {
+				r, m = 0, 0
+			}
+			detail["attributeRankingOrderScore"] = 1.0
+		case "wordPosition":
+			m = 10
+			r = m - c.position
+			if r < 0 {
+				r = 0
+			}
+		case "exactness":
+			r, m = c.exact, n
+			detail["matchType"] = "noExactMatch"
+			if c.exact == n {
+				detail["matchType"] = "exactMatch"
+			}
+		default:
+			continue
+		}
+		local := 1.0
+		if m > 0 {
+			local = float64(r) / float64(m)
+		}
+		detail["score"] = local
+		c.details[rule] = detail
+		rank = rank*float64(m+1) + float64(r)
+		maxRank *= float64(m + 1)
+	}
+	if maxRank > 1 {
+		c.score = rank / (maxRank - 1)
+	}
+}
+
+// distinctCandidates keeps the best candidate of each value of the distinct attribute.
+func distinctCandidates(candidates []*candidate, attribute string) []*candidate {
+	seen := make(map[string]bool)
+	kept := candidates[:0]
+	for _, c := range candidates {
+		values, _ := fieldValues(c.doc, attribute)
+		if len(values) > 0 {
+			key := fmt.Sprint(values[0])
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// displayedFields returns the fields of the hits, nil for all of them.
+func (e *searchEngine) displayedFields() []string {
+	displayed := e.idx.settingStrings("displayedAttributes")
+	retrieve := e.query.AttributesToRetrieve
+	allDisplayed := containsString(displayed, "*")
+	if len(retrieve) == 0 || containsString(retrieve, "*") {
+		if allDisplayed {
+			return nil
+		}
+		return displayed
+	}
+	fields := make([]string, 0, len(retrieve))
+	for _, field := range retrieve {
+		if allDisplayed || matchAttributePatterns(displayed, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// render returns the hit of c.
+func (e *searchEngine) render(c *candidate) document {
+	fields := e.displayedFields()
+	hit := make(document)
+	if fields == nil || len(fields) > 0 {
+		hit = selectFields(c.doc, fields, e.query.RetrieveVectors)
+	}
+
+	if len(e.query.AttributesToHighlight) > 0 || len(e.query.AttributesToCrop) > 0 {
+		hit["_formatted"] = e.format(c, hit)
+	}
+	if e.query.ShowMatchesPosition {
+		positions := make(map[string][]map[string]int)
+		for _, o := range c.matches {
+			positions[o.attribute] = append(positions[o.attribute], map[string]int{"start": o.start, "length": o.end - o.start})
+		}
+		hit["_matchesPosition"] = positions
+	}
+	if e.query.ShowRankingScore {
+		hit["_rankingScore"] = c.score
+	}
+	if e.query.ShowRankingScoreDetails {
+		hit["_rankingScoreDetails"] = c.details
+	}
+	return hit
+}
+
+// format returns the _formatted object of a hit: its values as strings, highlighted and cropped.
+func (e *searchEngine) format(c *candidate, hit document) document {
+	preTag, postTag, marker, cropLength := "<em>", "</em>", "…", 10
+	if e.query.HighlightPreTag != nil {
+		preTag = *e.query.HighlightPreTag
+	}
+	if e.query.HighlightPostTag != nil {
+		postTag = *e.query.HighlightPostTag
+	}
+	if e.query.CropMarker != nil {
+		marker = *e.query.CropMarker
+	}
+	if e.query.CropLength != nil {
+		cropLength = *e.query.CropLength
+	}
+
+	formatted := make(document, len(hit))
+	for field, value := range hit {
+		highlight := matchesAttributeList(e.query.AttributesToHighlight, field)
+		crop := matchesAttributeList(e.query.AttributesToCrop, field)
+		formatted[field] = formatValue(value, func(text string) string {
+			if crop {
+				text = cropText(text, e.terms, cropLength, marker)
+			}
+			if highlight {
+				text = highlightText(text, e.terms, preTag, postTag)
+			}
+			return text
+		})
+	}
+	return formatted
+}
+
+// matchesAttributeList reports whether field is in the list of attributes, which may hold `*`
+// or crop lengths as in `overview:20`.
+func matchesAttributeList(list []string, field string) bool {
+	for _, attr := range list {
+		if n := strings.LastIndex(attr, ":"); n > 0 {
+			attr = attr[:n]
+		}
+		if matchAttributePatterns([]string{attr}, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValue applies f to the strings of value, numbers are converted to strings.
+func formatValue(value interface{}, f func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return f(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = formatValue(item, f)
+		}
+		return list
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[k] = formatValue(item, f)
+		}
+		return m
+	}
+	return value
+}
+
+func termMatches(terms []term, w string) bool {
+	for _, t := range terms {
+		if ok, _, _ := matchWord(t, w, 0); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func highlightText(text string, terms []term, preTag, postTag string) string {
+	var b strings.Builder
+	last := 0
+	for _, w := range tokenize(text) {
+		if !termMatches(terms, w.text) {
+			continue
+		}
+		b.WriteString(text[last:w.start])
+		b.WriteString(preTag)
+		b.WriteString(text[w.start:w.end])
+		b.WriteString(postTag)
+		last = w.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// cropText keeps length words of text around the first match.
+func cropText(text string, terms []term, length int, marker string) string {
+	words := tokenize(text)
+	if len(words) <= length {
+		return text
+	}
+	first := 0
+	for i, w := range words {
+		if termMatches(terms, w.text) {
+			first = i
+			break
+		}
+	}
+	start := first - length/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + length
+	if end > len(words) {
+		end, start = len(words), len(words)-length
+	}
+	cropped := text[words[start].start:words[end-1].end]
+	if start > 0 {
+		cropped = marker + cropped
+	}
+	if end < len(words) {
+		cropped += marker
+	}
+	return cropped
+}
+
+// facetNames expands the requested facets, `*` stands for every filterable attribute.
+func (idx *indexState) facetNames(requested []string, code meilisearch.APIErrCode) ([]string, error) {
+	patterns := idx.filterablePatterns()
+	var names []string
+	for _, facet := range requested {
+		if facet == "*" {
+			for _, pattern := range patterns {
+				if !strings.Contains(pattern, "*") {
+					names = append(names, pattern)
+				}
+			}
+			continue
+		}
+		if !matchAttributePatterns(patterns, facet) {
+			return nil, newAPIError(http.StatusBadRequest, code,
+				"Invalid facet distribution: Attribute `%s` is not filterable. Available filterable attributes "+
+					"patterns are: `%s`.", facet, strings.Join(patterns, ", "))
+		}
+		names = append(names, facet)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// facetValue is a value of a facet with the number of documents having it.
+type facetValue struct {
+	value string
+	count int
+}
+
+// facetValues counts the values of the facet in docs, in the order of the faceting settings.
+func (idx *indexState) facetValues(docs []document, facet string) ([]facetValue, []float64) {
+	counts := make(map[string]int)
+	display := make(map[string]string)
+	var numbers []float64
+	for _, doc := range docs {
+		values, _ := fieldValues(doc, facet)
+		seen := make(map[string]bool)
+		for _, v := range values {
+			var s string
+			switch v := v.(type) {
+			case string:
+				s = v
+			case json.Number:
+				s = v.String()
+				if n, err := v.Float64(); err == nil {
+					numbers = append(numbers, n)
+				}
+			case bool:
+				s = strconv.FormatBool(v)
+			default:
+				continue
+			}
+			key := strings.ToLower(s)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if _, ok := display[key]; !ok {
+				display[key] = s
+			}
+			counts[key]++
+		}
+	}
+
+	values := make([]facetValue, 0, len(counts))
+	for key, count := range counts {
+		values = append(values, facetValue{value: display[key], count: count})
+	}
+	byCount := false
+	maxValues := 100
+	if faceting, ok := idx.setting("faceting").(map[string]interface{}); ok {
+		if n, ok := intValue(faceting["maxValuesPerFacet"]); ok {
+			maxValues = n
+		}
+		if orders, ok := faceting["sortFacetValuesBy"].(map[string]interface{}); ok {
+			order, ok := orders[facet]
+			if !ok {
+				order = orders["*"]
+			}
+			byCount = order == "count"
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if byCount && values[i].count != values[j].count {
+			return values[i].count > values[j].count
+		}
+		return values[i].value < values[j].value
+	})
+	if len(values) > maxValues {
+		values = values[:maxValues]
+	}
+	return values, numbers
+}
+
+// facetDistribution returns the facet distribution of docs as JSON, to keep the values in the
+// order of the faceting settings, and the stats of the numeric facets.
+func (idx *indexState) facetDistribution(docs []document, facets []string) (json.RawMessage, map[string]interface{}) {
+	var buf bytes.Buffer
+	stats := make(map[string]interface{})
+	buf.WriteByte('{')
+	for i, facet := range facets {
+		values, numbers := idx.facetValues(docs, facet)
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(facet)
+		buf.Write(name)
+		buf.WriteString(":{")
+		for j, v := range values {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			value, _ := json.Marshal(v.value)
+			buf.Write(value)
+			buf.WriteByte(':')
+			buf.WriteString(strconv.Itoa(v.count))
+		}
+		buf.WriteByte('}')
+
+		if len(numbers) > 0 {
+			minimum, maximum := numbers[0], numbers[0]
+			for _, n := range numbers {
+				minimum, maximum = math.Min(minimum, n), math.Max(maximum, n)
+			}
+			stats[facet] = map[string]float64{"min": minimum, "max": maximum}
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), stats
+}
+
+// facetSearchQuery is the body of a facet search.
+type facetSearchQuery struct {
+	FacetName            string      `json:"facetName"`
+	FacetQuery           string      `json:"facetQuery"`
+	Q                    string      `json:"q"`
+	Filter               interface{} `json:"filter"`
+	MatchingStrategy     string      `json:"matchingStrategy"`
+	AttributesToSearchOn []string    `json:"attributesToSearchOn"`
+	ExhaustiveFacetCount bool        `json:"exhaustiveFacetCount"`
+}
+
+func (s *Server) facetSearch(r *request, uid string) (int, interface{}, error) {
+	var q facetSearchQuery
+	if err := decodeJSON(r, &q); err != nil {
+		return 0, nil, err
+	}
+	idx, err := s.index(uid)
+	if err != nil {
+		return 0, nil, err
+	}
+	if q.FacetName == "" {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingFacetSearchFacetName,
+			"Missing field `facetName`")
+	}
+	if enabled, ok := idx.setting("facetSearch").(bool); ok && !enabled {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeFacetSearchDisabled,
+			"The facet search is disabled for this index")
+	}
+	if !matchAttributePatterns(idx.filterablePatterns(), q.FacetName) {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidFacetSearchFacetName,
+			"Attribute `%s` is not facet-searchable. Available facet-searchable attributes patterns are: `%s`. "+
+				"To make it facet-searchable add it to the `filterableAttributes` index settings.",
+			q.FacetName, strings.Join(idx.filterablePatterns(), ", "))
+	}
+
+	res, err := s.runSearch(r, uid, &searchQuery{
+		Q:                    q.Q,
+		Filter:               q.Filter,
+		MatchingStrategy:     q.MatchingStrategy,
+		AttributesToSearchOn: q.AttributesToSearchOn,
+	}, true)
+	if err != nil {
+		return 0, nil, err
+	}
+	docs := make([]document, 0, len(res.hits))
+	for _, c := range res.hits {
+		docs = append(docs, c.doc)
+	}
+
+	values, _ := idx.facetValues(docs, q.FacetName)
+	prefix := term{text: strings.ToLower(q.FacetQuery), prefix: true}
+	hits := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		if q.FacetQuery != "" && !facetValueMatches(v.value, prefix) {
+			continue
+		}
+		hits = append(hits, map[string]interface{}{"value": v.value, "count": v.count})
+	}
+	return http.StatusOK, map[string]interface{}{
+		"facetHits":        hits,
+		"facetQuery":       nullString(q.FacetQuery),
+		"processingTimeMs": 0,
+	}, nil
+}
+
+// facetValueMatches reports whether a word of value starts with the facet query.
+func facetValueMatches(value string, query term) bool {
+	if strings.HasPrefix(strings.ToLower(value), query.text) {
+		return true
+	}
+	for _, w := range tokenize(value) {
+		if ok, _, _ := matchWord(query, w.text, 0); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// multiSearchQuery is the body of a multi-search.
+type multiSearchQuery struct {
+	Queries    []*searchQuery `json:"queries"`
+	Federation *struct {
+		Offset        *int                `json:"offset"`
+		Limit         *int                `json:"limit"`
+		FacetsByIndex map[string][]string `json:"facetsByIndex"`
+		MergeFacets   *struct {
+			MaxValuesPerFacet *int `json:"maxValuesPerFacet"`
+		} `json:"mergeFacets"`
+	} `json:"federation"`
+}
+
+func (s *Server) multiSearch(r *request) (int, interface{}, error) {
+	var q multiSearchQuery
+	if err := decodeJSON(r, &q); err != nil {
+		return 0, nil, err
+	}
+	federated := q.Federation != nil
+
+	results := make([]*searchResult, 0, len(q.Queries))
+	for i, query := range q.Queries {
+		if query == nil || query.IndexUID == "" {
+			return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingIndexUID,
+				"Missing field `indexUid` inside `.queries[%d]`", i)
+		}
+		if federated && (query.Offset != nil || query.Limit != nil || query.Page != nil || query.HitsPerPage != nil) {
+			return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidMultiSearchQueryPagination,
+				"Inside `.queries[%d]`: Using pagination options is not allowed in federated queries.\n "+
+					"Hint: remove `offset`, `limit`, `page` or `hitsPerPage` from query parameters", i)
+		}
+		if federated && query.Facets != nil {
+			return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidMultiSearchQueryFacets,
+				"Inside `.queries[%d]`: Using facet options is not allowed in federated queries.\n "+
+					"Hint: remove `facets` from query #%d or remove `federation` from the request", i, i)
+		}
+		res, err := s.runSearch(r, query.IndexUID, query, federated)
+		if err != nil {
+			if apiErr, ok := err.(*apiError); ok {
+				apiErr.details.Message = fmt.Sprintf("Inside `.queries[%d]`: %s", i, apiErr.details.Message)
+			}
+			return 0, nil, err
+		}
+		results = append(results, res)
+	}
+
+	if !federated {
+		list := make([]interface{}, 0, len(results))
+		for _, res := range results {
+			res.response["indexUid"] = res.query.IndexUID
+			list = append(list, res.response)
+		}
+		return http.StatusOK, map[string]interface{}{"results": list}, nil
+	}
+	return http.StatusOK, s.federate(&q, results), nil
+}
+
+// federate merges the hits of the results by weighted ranking score.
+func (s *Server) federate(q *multiSearchQuery, results []*searchResult) map[string]interface{} {
+	type federatedHit struct {
+		hit      document
+		score    float64
+		position int
+	}
+	var hits []federatedHit
+	for position, res := range results {
+		weight := 1.0
+		if opts := res.query.FederationOptions; opts != nil && opts.Weight != nil {
+			weight = *opts.Weight
+		}
+		engine := newSearchEngine(res.idx, res.query)
+		for _, c := range res.hits {
+			hit := engine.render(c)
+			score := c.score * weight
+			hit["_federation"] = map[string]interface{}{
+				"indexUid":             res.query.IndexUID,
+				"queriesPosition":      position,
+				"weightedRankingScore": score,
+			}
+			hits = append(hits, federatedHit{hit: hit, score: score, position: position})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].score > hits[j].score
+	})
+
+	offset, limit := 0, 20
+	if q.Federation.Offset != nil {
+		offset = *q.Federation.Offset
+	}
+	if q.Federation.Limit != nil {
+		limit = *q.Federation.Limit
+	}
+	from, to := clamp(offset, len(hits)), clamp(offset+limit, len(hits))
+	page := make([]interface{}, 0, to-from)
+	for _, h := range hits[from:to] {
+		page = append(page, h.hit)
+	}
+
+	response := map[string]interface{}{
+		"hits":               page,
+		"processingTimeMs":   0,
+		"offset":             offset,
+		"limit":              limit,
+		"estimatedTotalHits": len(hits),
+	}
+	if len(q.Federation.FacetsByIndex) == 0 {
+		return response
+	}
+
+	byIndex := make(map[string]interface{})
+	merged := make(map[string]map[string]int)
+	for uid, facets := range q.Federation.FacetsByIndex {
+		var docs []document
+		var idx *indexState
+		for _, res := range results {
+			if res.query.IndexUID != uid {
+				continue
+			}
+			idx = res.idx
+			for _, c := range res.hits {
+				docs = append(docs, c.doc)
+			}
+		}
+		if idx == nil {
+			continue
+		}
+		sort.Strings(facets)
+		distribution, stats := idx.facetDistribution(docs, facets)
+		byIndex[uid] = map[string]interface{}{"distribution": distribution, "stats": stats}
+		for _, facet := range facets {
+			values, _ := idx.facetValues(docs, facet)
+			if merged[facet] == nil {
+				merged[facet] = make(map[string]int)
+			}
+			for _, v := range values {
+				merged[facet][v.value] += v.count
+			}
+		}
+	}
+	if q.Federation.MergeFacets == nil {
+		response["facetsByIndex"] = byIndex
+		return response
+	}
+	response["facetDistribution"] = merged
+	response["facetStats"] = map[string]interface{}{}
+	return response
+}
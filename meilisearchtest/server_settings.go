@@ -0,0 +1,385 @@
+package meilisearchtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// settingRoutes maps the setting sub-routes to the name of the setting.
+var settingRoutes = map[string]string{
+	"displayed-attributes":  "displayedAttributes",
+	"searchable-attributes": "searchableAttributes",
+	"filterable-attributes": "filterableAttributes",
+	"sortable-attributes":   "sortableAttributes",
+	"ranking-rules":         "rankingRules",
+	"stop-words":            "stopWords",
+	"non-separator-tokens":  "nonSeparatorTokens",
+	"separator-tokens":      "separatorTokens",
+	"dictionary":            "dictionary",
+	"synonyms":              "synonyms",
+	"distinct-attribute":    "distinctAttribute",
+	"proximity-precision":   "proximityPrecision",
+	"typo-tolerance":        "typoTolerance",
+	"faceting":              "faceting",
+	"pagination":            "pagination",
+	"embedders":             "embedders",
+	"search-cutoff-ms":      "searchCutoffMs",
+	"localized-attributes":  "localizedAttributes",
+	"facet-search":          "facetSearch",
+	"prefix-search":         "prefixSearch",
+	"chat":                  "chat",
+}
+
+// mergedSettings are the object settings whose updates are merged into the current value.
+var mergedSettings = map[string]bool{
+	"typoTolerance": true,
+	"faceting":      true,
+	"pagination":    true,
+	"embedders":     true,
+	"chat":          true,
+}
+
+var rankingRules = []string{"words", "typo", "proximity", "attributeRank", "sort", "wordPosition", "exactness"}
+
+// defaultSettings returns the settings of a new index.
+func defaultSettings() map[string]interface{} {
+	return map[string]interface{}{
+		"displayedAttributes":  []interface{}{"*"},
+		"searchableAttributes": []interface{}{"*"},
+		"filterableAttributes": []interface{}{},
+		"sortableAttributes":   []interface{}{},
+		"rankingRules":         stringsToValues(rankingRules),
+		"stopWords":            []interface{}{},
+		"nonSeparatorTokens":   []interface{}{},
+		"separatorTokens":      []interface{}{},
+		"dictionary":           []interface{}{},
+		"synonyms":             map[string]interface{}{},
+		"distinctAttribute":    nil,
+		"proximityPrecision":   "byWord",
+		"typoTolerance": map[string]interface{}{
+			"enabled":             true,
+			"minWordSizeForTypos": map[string]interface{}{"oneTypo": 5, "twoTypos": 9},
+			"disableOnWords":      []interface{}{},
+			"disableOnAttributes": []interface{}{},
+			"disableOnNumbers":    false,
+		},
+		"faceting": map[string]interface{}{
+			"maxValuesPerFacet": 100,
+			"sortFacetValuesBy": map[string]interface{}{"*": "alpha"},
+		},
+		"pagination":          map[string]interface{}{"maxTotalHits": 1000},
+		"embedders":           map[string]interface{}{},
+		"searchCutoffMs":      nil,
+		"localizedAttributes": nil,
+		"facetSearch":         true,
+		"prefixSearch":        "indexingTime",
+		"chat": map[string]interface{}{
+			"description": "",
+			"documentTemplate": "{% for field in fields %}{% if field.is_searchable and field.value != nil %}" +
+				"{{ field.name }}: {{ field.value }}\n{% endif %}{% endfor %}",
+			"documentTemplateMaxBytes": 400,
+			"searchParameters":         map[string]interface{}{},
+		},
+	}
+}
+
+func stringsToValues(values []string) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, v := range values {
+		list[i] = v
+	}
+	return list
+}
+
+// setting returns the current value of the setting name of idx.
+func (idx *indexState) setting(name string) interface{} {
+	if v, ok := idx.settings[name]; ok {
+		return v
+	}
+	return defaultSettings()[name]
+}
+
+func (idx *indexState) allSettings() map[string]interface{} {
+	settings := defaultSettings()
+	for name, v := range idx.settings {
+		settings[name] = v
+	}
+	return settings
+}
+
+// settingStrings returns a setting holding a list of strings.
+func (idx *indexState) settingStrings(name string) []string {
+	list, _ := stringList(idx.setting(name))
+	return list
+}
+
+// applySetting sets the setting name of idx to value, a nil value resets it.
+func (idx *indexState) applySetting(name string, value interface{}) {
+	if value == nil {
+		delete(idx.settings, name)
+		return
+	}
+	if mergedSettings[name] {
+		value = mergeObject(idx.setting(name), value, defaultSettings()[name])
+	}
+	idx.settings[name] = value
+}
+
+// mergeObject merges the object update into current, null fields of update are reset to their
+// value in defaults or removed when they have none.
+func mergeObject(current, update, defaults interface{}) interface{} {
+	base, ok := current.(map[string]interface{})
+	patch, ok2 := update.(map[string]interface{})
+	if !ok || !ok2 {
+		return update
+	}
+	defaultFields, _ := defaults.(map[string]interface{})
+	merged := make(map[string]interface{}, len(base)+len(patch))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range patch {
+		switch nested, ok := merged[k].(map[string]interface{}); {
+		case v == nil && defaultFields[k] != nil:
+			merged[k] = defaultFields[k]
+		case v == nil:
+			delete(merged, k)
+		case ok:
+			merged[k] = mergeObject(nested, v, defaultFields[k])
+		default:
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func (s *Server) settingsRoutes(uid string, segments []string) map[string]*route {
+	if len(segments) == 0 {
+		return map[string]*route{
+			http.MethodGet: {action: "settings.get", index: uid, handle: func(r *request) (int, interface{}, error) {
+				idx, err := s.index(uid)
+				if err != nil {
+					return 0, nil, err
+				}
+				return http.StatusOK, idx.allSettings(), nil
+			}},
+			http.MethodPatch: {action: "settings.update", index: uid, handle: func(r *request) (int, interface{}, error) {
+				var payload map[string]interface{}
+				if err := decodeJSON(r, &payload); err != nil {
+					return 0, nil, err
+				}
+				for name, value := range payload {
+					if !knownSetting(name) {
+						return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeBadRequest,
+							"Unknown field `%s`: expected one of %s", name, quoteAll(settingNames()))
+					}
+					if err := validateSetting(name, value); err != nil {
+						return 0, nil, err
+					}
+				}
+				return s.enqueueSettings(r, uid, payload)
+			}},
+			http.MethodDelete: {action: "settings.update", index: uid, handle: func(r *request) (int, interface{}, error) {
+				payload := make(map[string]interface{})
+				for _, name := range settingNames() {
+					payload[name] = nil
+				}
+				return s.enqueueSettings(r, uid, payload)
+			}},
+		}
+	}
+
+	name, ok := settingRoutes[segments[0]]
+	if len(segments) != 1 || !ok {
+		return nil
+	}
+	update := func(r *request) (int, interface{}, error) {
+		var value interface{}
+		if err := decodeJSON(r, &value); err != nil {
+			return 0, nil, err
+		}
+		if err := validateSetting(name, value); err != nil {
+			return 0, nil, err
+		}
+		return s.enqueueSettings(r, uid, map[string]interface{}{name: value})
+	}
+	return map[string]*route{
+		http.MethodGet: {action: "settings.get", index: uid, handle: func(r *request) (int, interface{}, error) {
+			idx, err := s.index(uid)
+			if err != nil {
+				return 0, nil, err
+			}
+			value := idx.setting(name)
+			if value == nil {
+				return http.StatusOK, json.RawMessage("null"), nil
+			}
+			return http.StatusOK, value, nil
+		}},
+		http.MethodPut:   {action: "settings.update", index: uid, handle: update},
+		http.MethodPatch: {action: "settings.update", index: uid, handle: update},
+		http.MethodDelete: {action: "settings.update", index: uid, handle: func(r *request) (int, interface{}, error) {
+			return s.enqueueSettings(r, uid, map[string]interface{}{name: nil})
+		}},
+	}
+}
+
+func knownSetting(name string) bool {
+	_, ok := defaultSettings()[name]
+	return ok
+}
+
+func settingNames() []string {
+	names := make([]string, 0, len(settingRoutes))
+	for _, name := range settingRoutes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// enqueueSettings enqueues a settingsUpdate task applying the settings of payload, creating the
+// index when it does not exist.
+func (s *Server) enqueueSettings(r *request, uid string, payload map[string]interface{}) (int, interface{}, error) {
+	if !validIndexUID(uid) {
+		return 0, nil, invalidIndexUID(uid, meilisearch.APIErrCodeInvalidIndexUID)
+	}
+	t := s.enqueue(r, uid, meilisearch.TaskTypeSettingsUpdate, payload, func(t *task) error {
+		idx, ok := s.indexes[uid]
+		if !ok {
+			idx = newIndexState(uid, "")
+			s.indexes[uid] = idx
+		}
+		for name, value := range payload {
+			idx.applySetting(name, value)
+		}
+		idx.updatedAt = now()
+		return nil
+	})
+	return http.StatusAccepted, t.info(), nil
+}
+
+// validateSetting checks the value of a setting update like Meilisearch does before enqueuing it.
+func validateSetting(name string, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	invalid := func(code meilisearch.APIErrCode, format string, args ...interface{}) error {
+		return newAPIError(http.StatusBadRequest, code, "Invalid value at `.%s`: "+format, append([]interface{}{name}, args...)...)
+	}
+
+	switch name {
+	case "rankingRules":
+		rules, ok := stringList(value)
+		if !ok {
+			return invalid(meilisearch.APIErrCodeInvalidSettingsRankingRules, "expected an array of strings")
+		}
+		for _, rule := range rules {
+			if containsString(rankingRules, rule) || rule == "attribute" {
+				continue
+			}
+			if strings.HasSuffix(rule, ":asc") || strings.HasSuffix(rule, ":desc") {
+				continue
+			}
+			return invalid(meilisearch.APIErrCodeInvalidSettingsRankingRules,
+				"`%s` ranking rule is invalid. Valid ranking rules are words, typo, sort, proximity, attribute, "+
+					"exactness and custom ranking rules.", rule)
+		}
+	case "displayedAttributes", "searchableAttributes", "sortableAttributes", "stopWords", "nonSeparatorTokens",
+		"separatorTokens", "dictionary":
+		if _, ok := stringList(value); !ok {
+			return invalid(settingCode(name), "expected an array of strings")
+		}
+	case "filterableAttributes":
+		if _, ok := value.([]interface{}); !ok {
+			return invalid(meilisearch.APIErrCodeInvalidSettingsFilterableAttributes, "expected an array")
+		}
+	case "distinctAttribute":
+		if _, ok := value.(string); !ok {
+			return invalid(meilisearch.APIErrCodeInvalidSettingsDistinctAttribute, "expected a string")
+		}
+	case "synonyms", "typoTolerance", "faceting", "pagination", "embedders", "chat":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return invalid(settingCode(name), "expected an object")
+		}
+	case "localizedAttributes":
+		rules, ok := value.([]interface{})
+		if !ok {
+			return invalid(meilisearch.APIErrCodeInvalidSettingsLocalizedAttributes, "expected an array")
+		}
+		for _, rule := range rules {
+			m, _ := rule.(map[string]interface{})
+			locales, _ := stringList(m["locales"])
+			for _, locale := range locales {
+				if !supportedLocale(locale) {
+					return invalid(meilisearch.APIErrCodeInvalidSettingsLocalizedAttributes,
+						"Unsupported locale `%s`, expected one of %s", locale, strings.Join(locales639, ", "))
+				}
+			}
+		}
+	case "searchCutoffMs":
+		if n, ok := value.(json.Number); !ok || strings.HasPrefix(n.String(), "-") {
+			return invalid(meilisearch.APIErrCodeInvalidSettingsSearchCutoffMS, "expected a positive integer")
+		}
+	case "facetSearch":
+		if _, ok := value.(bool); !ok {
+			return invalid(meilisearch.APIErrCodeInvalidSettingsFacetSearch, "expected a boolean")
+		}
+	case "prefixSearch":
+		if v, _ := value.(string); v != "indexingTime" && v != "disabled" {
+			return invalid(meilisearch.APIErrCodeInvalidSettingsPrefixSearch,
+				"Unknown value `%v`, expected one of `indexingTime`, `disabled`", value)
+		}
+	case "proximityPrecision":
+		if v, _ := value.(string); v != "byWord" && v != "byAttribute" {
+			return invalid(meilisearch.APIErrCodeBadRequest,
+				"Unknown value `%v`, expected one of `byWord`, `byAttribute`", value)
+		}
+	}
+	return nil
+}
+
+func settingCode(name string) meilisearch.APIErrCode {
+	var b strings.Builder
+	for _, c := range name {
+		if c >= 'A' && c <= 'Z' {
+			b.WriteByte('_')
+			c += 'a' - 'A'
+		}
+		b.WriteRune(c)
+	}
+	return meilisearch.APIErrCode("invalid_settings_" + b.String())
+}
+
+// locales639 are the ISO 639-3 locales supported by Meilisearch.
+var locales639 = []string{
+	"afr", "aka", "amh", "ara", "aze", "bel", "ben", "bul", "cat", "ces", "cmn", "dan", "deu", "ell", "eng",
+	"epo", "est", "fin", "fra", "guj", "heb", "hin", "hrv", "hun", "hye", "ind", "ita", "jav", "jpn", "kan",
+	"kat", "khm", "kor", "lat", "lav", "lit", "mal", "mar", "mkd", "mya", "nep", "nld", "nob", "ori", "pan",
+	"pes", "pol", "por", "ron", "rus", "sin", "slk", "slv", "sna", "spa", "srp", "swe", "tam", "tel", "tgl",
+	"tha", "tuk", "tur", "ukr", "urd", "uzb", "vie", "yid", "zho", "zul",
+}
+
+// locales6391 are the ISO 639-1 aliases of the supported locales.
+var locales6391 = []string{
+	"af", "ak", "am", "ar", "az", "be", "bn", "bg", "ca", "cs", "zh", "da", "de", "el", "en", "eo", "et",
+	"fi", "fr", "gu", "he", "hi", "hr", "hu", "hy", "id", "it", "jv", "ja", "kn", "ka", "km", "ko", "la",
+	"lv", "lt", "ml", "mr", "mk", "my", "ne", "nl", "nb", "or", "pa", "fa", "pl", "pt", "ro", "ru", "si",
+	"sk", "sl", "sn", "es", "sr", "sv", "ta", "te", "tl", "th", "tk", "tr", "uk", "ur", "uz", "vi", "yi", "zu",
+}
+
+func supportedLocale(locale string) bool {
+	return containsString(locales639, locale) || containsString(locales6391, locale)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
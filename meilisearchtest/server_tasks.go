@@ -0,0 +1,705 @@
+package meilisearchtest
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// taskTypes are the task types accepted by the task filters.
+var taskTypes = []meilisearch.TaskType{
+	"documentAdditionOrUpdate",
+	"documentEdition",
+	"documentDeletion",
+	"settingsUpdate",
+	"indexCreation",
+	"indexDeletion",
+	"indexUpdate",
+	"indexSwap",
+	"indexCompaction",
+	"taskCancelation",
+	"taskDeletion",
+	"dumpCreation",
+	"snapshotCreation",
+	"export",
+	"upgradeDatabase",
+}
+
+var taskStatuses = []meilisearch.TaskStatus{
+	meilisearch.TaskStatusEnqueued,
+	meilisearch.TaskStatusProcessing,
+	meilisearch.TaskStatusSucceeded,
+	meilisearch.TaskStatusFailed,
+	meilisearch.TaskStatusCanceled,
+}
+
+// task is an asynchronous operation, it is applied to the state of the server by process.
+type task struct {
+	uid            int64
+	batchUID       *int64
+	indexUID       string
+	status         meilisearch.TaskStatus
+	typ            meilisearch.TaskType
+	canceledBy     *int64
+	details        map[string]interface{}
+	err            *meilisearch.APIErrorDetails
+	enqueuedAt     time.Time
+	startedAt      *time.Time
+	finishedAt     *time.Time
+	customMetadata string
+
+	// process applies the task with the server lock held, a returned *apiError fails the task
+	process func(t *task) error
+	// documents is the payload of document tasks, served by GET /tasks/{uid}/documents
+	documents []document
+}
+
+// batch groups the tasks processed together, the Server processes a single task per batch.
+type batch struct {
+	uid        int64
+	task       *task
+	startedAt  time.Time
+	finishedAt *time.Time
+}
+
+// enqueue registers a task for the worker and returns it, the server lock must be held.
+func (s *Server) enqueue(r *request, indexUID string, typ meilisearch.TaskType, details map[string]interface{},
+	process func(t *task) error) *task {
+	t := &task{
+		uid:        s.nextTaskUID,
+		indexUID:   indexUID,
+		status:     meilisearch.TaskStatusEnqueued,
+		typ:        typ,
+		details:    details,
+		enqueuedAt: now(),
+		process:    process,
+	}
+	if r != nil {
+		t.customMetadata = r.URL.Query().Get("customMetadata")
+	}
+	s.nextTaskUID++
+	s.tasks = append(s.tasks, t)
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return t
+}
+
+// processTasks processes the enqueued tasks in order until the Server is closed.
+func (s *Server) processTasks() {
+	defer close(s.done)
+
+	for {
+		s.mu.Lock()
+		t := s.nextEnqueued()
+		if t == nil {
+			s.mu.Unlock()
+			select {
+			case <-s.wake:
+				continue
+			case <-s.closed:
+				return
+			}
+		}
+		startedAt := now()
+		b := &batch{uid: s.nextBatchUID, task: t, startedAt: startedAt}
+		s.nextBatchUID++
+		s.batches[b.uid] = b
+		t.batchUID = &b.uid
+		t.status = meilisearch.TaskStatusProcessing
+		t.startedAt = &startedAt
+		s.mu.Unlock()
+
+		if s.taskDelay > 0 {
+			select {
+			case <-time.After(s.taskDelay):
+			case <-s.closed:
+				return
+			}
+		}
+
+		s.mu.Lock()
+		// the task may have been canceled while it was processing
+		if t.status == meilisearch.TaskStatusProcessing {
+			var err error
+			if t.process != nil {
+				err = t.process(t)
+			}
+			t.status = meilisearch.TaskStatusSucceeded
+			if err != nil {
+				apiErr, ok := err.(*apiError)
+				if !ok {
+					apiErr = newAPIError(http.StatusInternalServerError, meilisearch.APIErrCodeInternal, "%s", err.Error())
+				}
+				t.status = meilisearch.TaskStatusFailed
+				t.err = &apiErr.details
+			}
+			finishedAt := now()
+			t.finishedAt = &finishedAt
+		}
+		b.finishedAt = t.finishedAt
+		s.mu.Unlock()
+	}
+}
+
+func (s *Server) nextEnqueued() *task {
+	for _, t := range s.tasks {
+		if t.status == meilisearch.TaskStatusEnqueued {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Server) taskByUID(uid int64) *task {
+	i := sort.Search(len(s.tasks), func(i int) bool { return s.tasks[i].uid >= uid })
+	if i < len(s.tasks) && s.tasks[i].uid == uid {
+		return s.tasks[i]
+	}
+	return nil
+}
+
+// info is the summary of t answered when it is enqueued.
+func (t *task) info() map[string]interface{} {
+	return map[string]interface{}{
+		"taskUid":    t.uid,
+		"indexUid":   nullString(t.indexUID),
+		"status":     t.status,
+		"type":       t.typ,
+		"enqueuedAt": t.enqueuedAt,
+	}
+}
+
+func (t *task) view() map[string]interface{} {
+	v := map[string]interface{}{
+		"uid":        t.uid,
+		"batchUid":   t.batchUID,
+		"indexUid":   nullString(t.indexUID),
+		"status":     t.status,
+		"type":       t.typ,
+		"canceledBy": t.canceledBy,
+		"details":    t.details,
+		"error":      t.err,
+		"duration":   nil,
+		"enqueuedAt": t.enqueuedAt,
+		"startedAt":  t.startedAt,
+		"finishedAt": t.finishedAt,
+	}
+	if t.startedAt != nil && t.finishedAt != nil {
+		v["duration"] = isoDuration(t.finishedAt.Sub(*t.startedAt))
+	}
+	if t.customMetadata != "" {
+		v["customMetadata"] = t.customMetadata
+	}
+	return v
+}
+
+func (t *task) finished() bool {
+	return t.status == meilisearch.TaskStatusSucceeded || t.status == meilisearch.TaskStatusFailed ||
+		t.status == meilisearch.TaskStatusCanceled
+}
+
+func (s *Server) taskRoutes(segments []string) map[string]*route {
+	switch {
+	case len(segments) == 0:
+		return map[string]*route{
+			http.MethodGet:    {action: "tasks.get", handle: s.listTasks},
+			http.MethodDelete: {action: "tasks.delete", handle: s.deleteTasks},
+		}
+	case len(segments) == 1 && segments[0] == "cancel":
+		return map[string]*route{http.MethodPost: {action: "tasks.cancel", handle: s.cancelTasks}}
+	case len(segments) == 1:
+		return map[string]*route{http.MethodGet: {action: "tasks.get", handle: func(r *request) (int, interface{}, error) {
+			t, err := s.pathTask(segments[0])
+			if err != nil {
+				return 0, nil, err
+			}
+			return http.StatusOK, t.view(), nil
+		}}}
+	case len(segments) == 2 && segments[1] == "documents":
+		return map[string]*route{http.MethodGet: {action: "tasks.get", handle: func(r *request) (int, interface{}, error) {
+			return s.taskDocuments(r, segments[0])
+		}}}
+	}
+	return nil
+}
+
+func (s *Server) pathTask(segment string) (*task, error) {
+	uid, err := strconv.ParseInt(segment, 10, 64)
+	if err != nil || uid < 0 {
+		return nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeBadRequest,
+			"invalid task uid `%s`: the value must be a positive integer", segment)
+	}
+	t := s.taskByUID(uid)
+	if t == nil {
+		return nil, newAPIError(http.StatusNotFound, meilisearch.APIErrCodeTaskNotFound, "Task `%d` not found.", uid)
+	}
+	return t, nil
+}
+
+func (s *Server) taskDocuments(r *request, segment string) (int, interface{}, error) {
+	if err := s.requireFeature("getTaskDocumentsRoute", "getting the documents of an enqueued task"); err != nil {
+		return 0, nil, err
+	}
+	t, err := s.pathTask(segment)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, ndjsonBody(t.documents), nil
+}
+
+// taskFilter is the parsed query string of the task routes.
+type taskFilter struct {
+	uids, batchUIDs, canceledBy map[int64]bool
+	indexUIDs                   map[string]bool
+	statuses                    map[meilisearch.TaskStatus]bool
+	types                       map[meilisearch.TaskType]bool
+	after, before               map[string]time.Time
+	// set reports whether the query holds at least one filter
+	set bool
+}
+
+var taskDates = []string{"EnqueuedAt", "StartedAt", "FinishedAt"}
+
+func parseTaskFilter(query url.Values) (*taskFilter, error) {
+	f := &taskFilter{after: map[string]time.Time{}, before: map[string]time.Time{}}
+
+	parseInts := func(name string, code meilisearch.APIErrCode) (map[int64]bool, error) {
+		value := query.Get(name)
+		if value == "" || value == "*" {
+			return nil, nil
+		}
+		f.set = true
+		set := make(map[int64]bool)
+		for _, v := range strings.Split(value, ",") {
+			n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil || n < 0 {
+				return nil, newAPIError(http.StatusBadRequest, code,
+					"Invalid value in parameter `%s`: could not parse `%s` as a positive integer", name, v)
+			}
+			set[n] = true
+		}
+		return set, nil
+	}
+
+	var err error
+	if f.uids, err = parseInts("uids", meilisearch.APIErrCodeInvalidTaskUIDs); err != nil {
+		return nil, err
+	}
+	if f.batchUIDs, err = parseInts("batchUids", meilisearch.APIErrCodeInvalidTaskUIDs); err != nil {
+		return nil, err
+	}
+	if f.canceledBy, err = parseInts("canceledBy", meilisearch.APIErrCodeInvalidTaskCanceledBy); err != nil {
+		return nil, err
+	}
+
+	if value := query.Get("indexUids"); value != "" && value != "*" {
+		f.set = true
+		f.indexUIDs = make(map[string]bool)
+		for _, uid := range strings.Split(value, ",") {
+			if !validIndexUID(uid) {
+				return nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidTaskIndexUIDs,
+					"Invalid value in parameter `indexUids`: `%s` is not a valid index uid. Index uid can be an "+
+						"integer or a string containing only alphanumeric characters, hyphens (-) and underscores (_), "+
+						"and can not be more than 512 bytes.", uid)
+			}
+			f.indexUIDs[uid] = true
+		}
+	}
+	if value := query.Get("statuses"); value != "" && value != "*" {
+		f.set = true
+		f.statuses = make(map[meilisearch.TaskStatus]bool)
+		for _, v := range strings.Split(value, ",") {
+			status := meilisearch.TaskStatus(v)
+			if !containsStatus(taskStatuses, status) {
+				return nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidTaskStatuses,
+					"Invalid value in parameter `statuses`: `%s` is not a valid task status. Available statuses "+
+						"are `enqueued`, `processing`, `succeeded`, `failed`, `canceled`.", v)
+			}
+			f.statuses[status] = true
+		}
+	}
+	if value := query.Get("types"); value != "" && value != "*" {
+		f.set = true
+		f.types = make(map[meilisearch.TaskType]bool)
+		for _, v := range strings.Split(value, ",") {
+			typ := meilisearch.TaskType(v)
+			if !containsType(taskTypes, typ) {
+				return nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidTaskTypes,
+					"Invalid value in parameter `types`: `%s` is not a valid task type.", v)
+			}
+			f.types[typ] = true
+		}
+	}
+
+	codes := map[string]meilisearch.APIErrCode{
+		"afterEnqueuedAt":  meilisearch.APIErrCodeInvalidTaskAfterEnqueuedAt,
+		"beforeEnqueuedAt": meilisearch.APIErrCodeInvalidTaskBeforeEnqueuedAt,
+		"afterStartedAt":   meilisearch.APIErrCodeInvalidTaskAfterStartedAt,
+		"beforeStartedAt":  meilisearch.APIErrCodeInvalidTaskBeforeStartedAt,
+		"afterFinishedAt":  meilisearch.APIErrCodeInvalidTaskAfterFinishedAt,
+		"beforeFinishedAt": meilisearch.APIErrCodeInvalidTaskBeforeFinishedAt,
+	}
+	for _, date := range taskDates {
+		for _, bound := range []string{"after", "before"} {
+			name := bound + date
+			value := query.Get(name)
+			if value == "" {
+				continue
+			}
+			f.set = true
+			tm, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				if tm, err = time.Parse("2006-01-02", value); err != nil {
+					return nil, newAPIError(http.StatusBadRequest, codes[name],
+						"Invalid value in parameter `%s`: `%s` is an invalid date-time. It should follow the "+
+							"YYYY-MM-DD or RFC 3339 date-time format.", name, value)
+				}
+			}
+			if bound == "after" {
+				f.after[date] = tm
+			} else {
+				f.before[date] = tm
+			}
+		}
+	}
+	return f, nil
+}
+
+func (f *taskFilter) match(t *task) bool {
+	if f.uids != nil && !f.uids[t.uid] {
+		return false
+	}
+	if f.batchUIDs != nil && (t.batchUID == nil || !f.batchUIDs[*t.batchUID]) {
+		return false
+	}
+	if f.canceledBy != nil && (t.canceledBy == nil || !f.canceledBy[*t.canceledBy]) {
+		return false
+	}
+	if f.indexUIDs != nil && !f.indexUIDs[t.indexUID] {
+		return false
+	}
+	if f.statuses != nil && !f.statuses[t.status] {
+		return false
+	}
+	if f.types != nil && !f.types[t.typ] {
+		return false
+	}
+	for _, date := range taskDates {
+		var at *time.Time
+		switch date {
+		case "EnqueuedAt":
+			at = &t.enqueuedAt
+		case "StartedAt":
+			at = t.startedAt
+		case "FinishedAt":
+			at = t.finishedAt
+		}
+		if after, ok := f.after[date]; ok && (at == nil || !at.After(after)) {
+			return false
+		}
+		if before, ok := f.before[date]; ok && (at == nil || !at.Before(before)) {
+			return false
+		}
+	}
+	return true
+}
+
+// pageParams parses the limit, from and reverse parameters of the task and batch lists.
+func pageParams(query url.Values) (limit int64, from *int64, reverse bool, err error) {
+	limit = 20
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.ParseInt(value, 10, 64); err != nil || limit < 0 {
+			return 0, nil, false, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeInvalidTaskLimit,
+				"Invalid value in parameter `limit`: could not parse `%s` as a positive integer", value)
+		}
+	}
+	if value := query.Get("from"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return 0, nil, false, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeBadRequest,
+				"Invalid value in parameter `from`: could not parse `%s` as a positive integer", value)
+		}
+		from = &n
+	}
+	reverse = query.Get("reverse") == "true"
+	return limit, from, reverse, nil
+}
+
+func (s *Server) listTasks(r *request) (int, interface{}, error) {
+	query := r.URL.Query()
+	f, err := parseTaskFilter(query)
+	if err != nil {
+		return 0, nil, err
+	}
+	limit, from, reverse, err := pageParams(query)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var matched []*task
+	for _, t := range s.tasks {
+		if f.match(t) {
+			matched = append(matched, t)
+		}
+	}
+	if !reverse {
+		reverseTasks(matched)
+	}
+
+	results := make([]interface{}, 0)
+	var next *int64
+	for _, t := range matched {
+		if from != nil && ((!reverse && t.uid > *from) || (reverse && t.uid < *from)) {
+			continue
+		}
+		if int64(len(results)) == limit {
+			uid := t.uid
+			next = &uid
+			break
+		}
+		results = append(results, t.view())
+	}
+	return http.StatusOK, map[string]interface{}{
+		"results": results,
+		"total":   len(matched),
+		"limit":   limit,
+		"from":    firstUID(results),
+		"next":    next,
+	}, nil
+}
+
+func firstUID(results []interface{}) interface{} {
+	if len(results) == 0 {
+		return nil
+	}
+	return results[0].(map[string]interface{})["uid"]
+}
+
+func reverseTasks(tasks []*task) {
+	for i, j := 0, len(tasks)-1; i < j; i, j = i+1, j-1 {
+		tasks[i], tasks[j] = tasks[j], tasks[i]
+	}
+}
+
+// originalFilter is the query string of r as reported in the details of the task.
+func originalFilter(r *request) string {
+	return "?" + r.URL.RawQuery
+}
+
+func (s *Server) cancelTasks(r *request) (int, interface{}, error) {
+	f, err := parseTaskFilter(r.URL.Query())
+	if err != nil {
+		return 0, nil, err
+	}
+	if !f.set {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingTaskFilters,
+			"Query parameters to filter the tasks to cancel are missing. Available query parameters are: "+
+				"`uids`, `indexUids`, `statuses`, `types`, `canceledBy`, `beforeEnqueuedAt`, `afterEnqueuedAt`, "+
+				"`beforeStartedAt`, `afterStartedAt`, `beforeFinishedAt`, `afterFinishedAt`.")
+	}
+
+	details := map[string]interface{}{
+		"matchedTasks":   0,
+		"canceledTasks":  nil,
+		"originalFilter": originalFilter(r),
+	}
+	// the tasks are matched when the cancelation is enqueued, like on Meilisearch
+	var matched []*task
+	for _, t := range s.tasks {
+		if f.match(t) {
+			matched = append(matched, t)
+		}
+	}
+	details["matchedTasks"] = len(matched)
+
+	t := s.enqueue(r, "", meilisearch.TaskTypeTaskCancelation, details, func(cancelation *task) error {
+		canceled := 0
+		for _, t := range matched {
+			if t.status != meilisearch.TaskStatusEnqueued && t.status != meilisearch.TaskStatusProcessing {
+				continue
+			}
+			finishedAt := now()
+			t.status = meilisearch.TaskStatusCanceled
+			t.canceledBy = &cancelation.uid
+			t.finishedAt = &finishedAt
+			canceled++
+		}
+		cancelation.details["canceledTasks"] = canceled
+		return nil
+	})
+	return http.StatusOK, t.info(), nil
+}
+
+func (s *Server) deleteTasks(r *request) (int, interface{}, error) {
+	f, err := parseTaskFilter(r.URL.Query())
+	if err != nil {
+		return 0, nil, err
+	}
+	if !f.set {
+		return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeMissingTaskFilters,
+			"Query parameters to filter the tasks to delete are missing. Available query parameters are: "+
+				"`uids`, `indexUids`, `statuses`, `types`, `canceledBy`, `beforeEnqueuedAt`, `afterEnqueuedAt`, "+
+				"`beforeStartedAt`, `afterStartedAt`, `beforeFinishedAt`, `afterFinishedAt`.")
+	}
+
+	matched := make(map[int64]bool)
+	for _, t := range s.tasks {
+		if f.match(t) {
+			matched[t.uid] = true
+		}
+	}
+	details := map[string]interface{}{
+		"matchedTasks":   len(matched),
+		"deletedTasks":   nil,
+		"originalFilter": originalFilter(r),
+	}
+
+	t := s.enqueue(r, "", meilisearch.TaskTypeTaskDeletion, details, func(deletion *task) error {
+		kept := s.tasks[:0]
+		deleted := 0
+		for _, t := range s.tasks {
+			if matched[t.uid] && t.finished() {
+				deleted++
+				continue
+			}
+			kept = append(kept, t)
+		}
+		s.tasks = kept
+		deletion.details["deletedTasks"] = deleted
+		return nil
+	})
+	return http.StatusOK, t.info(), nil
+}
+
+func (s *Server) batchRoutes(segments []string) map[string]*route {
+	switch len(segments) {
+	case 0:
+		return map[string]*route{http.MethodGet: {action: "tasks.get", handle: s.listBatches}}
+	case 1:
+		return map[string]*route{http.MethodGet: {action: "tasks.get", handle: func(r *request) (int, interface{}, error) {
+			uid, err := strconv.ParseInt(segments[0], 10, 64)
+			if err != nil {
+				return 0, nil, newAPIError(http.StatusBadRequest, meilisearch.APIErrCodeBadRequest,
+					"invalid batch uid `%s`: the value must be a positive integer", segments[0])
+			}
+			b, ok := s.batches[uid]
+			if !ok {
+				return 0, nil, newAPIError(http.StatusNotFound, meilisearch.APIErrCodeBatchNotFound, "Batch `%d` not found.", uid)
+			}
+			return http.StatusOK, b.view(), nil
+		}}}
+	}
+	return nil
+}
+
+func (s *Server) listBatches(r *request) (int, interface{}, error) {
+	query := r.URL.Query()
+	f, err := parseTaskFilter(query)
+	if err != nil {
+		return 0, nil, err
+	}
+	limit, from, reverse, err := pageParams(query)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var matched []*batch
+	for uid := int64(0); uid < s.nextBatchUID; uid++ {
+		if b, ok := s.batches[uid]; ok && f.match(b.task) {
+			matched = append(matched, b)
+		}
+	}
+	if !reverse {
+		for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+			matched[i], matched[j] = matched[j], matched[i]
+		}
+	}
+
+	results := make([]interface{}, 0)
+	var next *int64
+	for _, b := range matched {
+		if from != nil && ((!reverse && b.uid > *from) || (reverse && b.uid < *from)) {
+			continue
+		}
+		if int64(len(results)) == limit {
+			uid := b.uid
+			next = &uid
+			break
+		}
+		results = append(results, b.view())
+	}
+	return http.StatusOK, map[string]interface{}{
+		"results": results,
+		"total":   len(matched),
+		"limit":   limit,
+		"from":    firstUID(results),
+		"next":    next,
+	}, nil
+}
+
+func (b *batch) view() map[string]interface{} {
+	t := b.task
+	status := t.status
+	v := map[string]interface{}{
+		"uid":      b.uid,
+		"progress": nil,
+		"details":  t.details,
+		"stats": map[string]interface{}{
+			"totalNbTasks": 1,
+			"status":       map[string]int{string(status): 1},
+			"types":        map[string]int{string(t.typ): 1},
+			"indexUids":    map[string]int{},
+		},
+		"duration":      nil,
+		"startedAt":     b.startedAt,
+		"finishedAt":    b.finishedAt,
+		"batchStrategy": "batched all enqueued tasks",
+	}
+	if t.indexUID != "" {
+		v["stats"].(map[string]interface{})["indexUids"] = map[string]int{t.indexUID: 1}
+	}
+	if b.finishedAt == nil {
+		v["progress"] = map[string]interface{}{"steps": []interface{}{}, "percentage": 0}
+	} else {
+		v["duration"] = isoDuration(b.finishedAt.Sub(b.startedAt))
+	}
+	return v
+}
+
+// isoDuration formats d as the ISO 8601 durations of Meilisearch, like PT0.012345S.
+func isoDuration(d time.Duration) string {
+	return "PT" + strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S"
+}
+
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func containsStatus(statuses []meilisearch.TaskStatus, status meilisearch.TaskStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func containsType(types []meilisearch.TaskType, typ meilisearch.TaskType) bool {
+	for _, t := range types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package meilisearchtest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/meilisearch/meilisearch-go"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, opts ...ServerOption) *Server {
+	t.Helper()
+	srv := NewServer(append([]ServerOption{WithMasterKey("masterKey")}, opts...)...)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func requireAPIError(t *testing.T, err error, status int, code meilisearch.APIErrCode) {
+	t.Helper()
+	var apiErr *meilisearch.Error
+	require.True(t, errors.As(err, &apiErr), "unexpected error %v", err)
+	require.Equal(t, status, apiErr.StatusCode)
+	require.Equal(t, code, apiErr.APIError.Code)
+	require.Equal(t, errorsLink+string(code), apiErr.APIError.Link)
+	require.NotEmpty(t, apiErr.APIError.Message)
+}
+
+func TestServer_TaskLifecycle(t *testing.T) {
+	srv := newTestServer(t, WithTaskDelay(100*time.Millisecond))
+	client := srv.Client()
+
+	info, err := client.CreateIndex(&meilisearch.IndexConfig{Uid: "books", PrimaryKey: "id"})
+	require.NoError(t, err)
+	require.Equal(t, meilisearch.TaskStatusEnqueued, info.Status)
+	require.Equal(t, meilisearch.TaskTypeIndexCreation, info.Type)
+
+	task, err := client.GetTask(info.TaskUID)
+	require.NoError(t, err)
+	require.Contains(t, []meilisearch.TaskStatus{meilisearch.TaskStatusEnqueued, meilisearch.TaskStatusProcessing}, task.Status)
+
+	task, err = client.WaitForTask(info.TaskUID, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, meilisearch.TaskStatusSucceeded, task.Status)
+	require.NotZero(t, task.StartedAt)
+	require.NotZero(t, task.FinishedAt)
+
+	info, err = client.CreateIndex(&meilisearch.IndexConfig{Uid: "books"})
+	require.NoError(t, err)
+	task, err = client.WaitForTask(info.TaskUID, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, meilisearch.TaskStatusFailed, task.Status)
+	require.Equal(t, meilisearch.APIErrCodeIndexAlreadyExists, task.Error.Code)
+	require.Equal(t, "invalid_request", task.Error.Type)
+}
+
+func TestServer_Errors(t *testing.T) {
+	srv := newTestServer(t)
+
+	_, err := srv.Client().GetIndex("missing")
+	requireAPIError(t, err, http.StatusNotFound, meilisearch.APIErrCodeIndexNotFound)
+
+	_, err = srv.Client(meilisearch.WithAPIKey("wrong")).GetIndex("missing")
+	requireAPIError(t, err, http.StatusForbidden, meilisearch.APIErrCodeInvalidAPIKey)
+
+	_, err = meilisearch.New(srv.URL).GetIndex("missing")
+	requireAPIError(t, err, http.StatusUnauthorized, meilisearch.APIErrCodeMissingAuthorizationHeader)
+
+	_, err = srv.Client().CreateIndex(&meilisearch.IndexConfig{Uid: "not valid"})
+	requireAPIError(t, err, http.StatusBadRequest, meilisearch.APIErrCodeInvalidIndexUID)
+}
+
+func TestServer_Search(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client()
+	index := client.Index("books")
+
+	info, err := index.AddDocumentsNdjson([]byte(`{"id": 1, "title": "Le Petit Prince", "genre": "Tale", "year": 1943}
+{"id": 2, "title": "Harry Potter and the Half-Blood Prince", "genre": "Fantasy", "year": 2005}
+{"id": 3, "title": "The Hobbit", "genre": "Fantasy", "year": 1937}
+`), nil)
+	require.NoError(t, err)
+	_, err = index.WaitForTask(info.TaskUID, 10*time.Millisecond)
+	require.NoError(t, err)
+	info, err = index.UpdateSettings(&meilisearch.Settings{
+		FilterableAttributes: []string{"genre", "year"},
+		SortableAttributes:   []string{"year"},
+	})
+	require.NoError(t, err)
+	_, err = index.WaitForTask(info.TaskUID, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	res, err := index.Search("prince", &meilisearch.SearchRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Hits, 2)
+	require.Equal(t, json.RawMessage(`"Le Petit Prince"`), res.Hits[0]["title"])
+	require.Equal(t, int64(2), res.EstimatedTotalHits)
+
+	res, err = index.Search("hobit", &meilisearch.SearchRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Hits, 1, "one typo is tolerated on words of five letters")
+
+	res, err = index.Search("", &meilisearch.SearchRequest{
+		Filter: "genre = fantasy AND year > 1940",
+		Facets: []string{"genre"},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Hits, 1)
+	require.Equal(t, json.RawMessage(`2`), res.Hits[0]["id"])
+	require.JSONEq(t, `{"genre":{"Fantasy":1}}`, string(res.FacetDistribution))
+
+	hitsPerPage := int64(2)
+	res, err = index.Search("", &meilisearch.SearchRequest{Sort: []string{"year:asc"}, HitsPerPage: &hitsPerPage, Page: 2})
+	require.NoError(t, err)
+	require.Len(t, res.Hits, 1)
+	require.Equal(t, json.RawMessage(`2`), res.Hits[0]["id"])
+	require.Equal(t, int64(3), res.TotalHits)
+	require.Equal(t, int64(2), res.TotalPages)
+
+	_, err = index.Search("", &meilisearch.SearchRequest{Filter: "title = Hobbit"})
+	requireAPIError(t, err, http.StatusBadRequest, meilisearch.APIErrCodeInvalidSearchFilter)
+}
+
+func TestServer_DeleteDocumentsByFilter(t *testing.T) {
+	srv := newTestServer(t)
+	index := srv.Client().Index("books")
+
+	info, err := index.AddDocumentsCsv([]byte("id,title,year:number\n1,Hamlet,1598\n2,Ulysses,1922\n"), nil)
+	require.NoError(t, err)
+	_, err = index.WaitForTask(info.TaskUID, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	info, err = index.DeleteDocumentsByFilter("year < 1900", nil)
+	require.NoError(t, err)
+	task, err := index.WaitForTask(info.TaskUID, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, meilisearch.TaskStatusFailed, task.Status, "year is not filterable")
+	require.Equal(t, meilisearch.APIErrCodeInvalidDocumentFilter, task.Error.Code)
+
+	info, err = index.UpdateFilterableAttributes(&[]interface{}{"year"})
+	require.NoError(t, err)
+	_, err = index.WaitForTask(info.TaskUID, 10*time.Millisecond)
+	require.NoError(t, err)
+	info, err = index.DeleteDocumentsByFilter("year < 1900", nil)
+	require.NoError(t, err)
+	task, err = index.WaitForTask(info.TaskUID, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, meilisearch.TaskStatusSucceeded, task.Status)
+	require.Equal(t, int64(1), task.Details.DeletedDocuments)
+
+	var docs meilisearch.DocumentsResult
+	require.NoError(t, index.GetDocuments(&meilisearch.DocumentsQuery{}, &docs))
+	require.Equal(t, int64(1), docs.Total)
+	require.Equal(t, json.RawMessage(`"Ulysses"`), docs.Results[0]["title"])
+	require.Equal(t, json.RawMessage(`1922`), docs.Results[0]["year"])
+}
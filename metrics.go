@@ -0,0 +1,249 @@
+package meilisearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CallMetrics holds the measurements of a single logical SDK call, retries included.
+type CallMetrics struct {
+	// Function is the SDK function that issued the call, e.g. "Search".
+	Function string
+	// Method is the HTTP verb of the request.
+	Method string
+	// Duration of the whole call, backoff between retries included.
+	Duration time.Duration
+	// RequestBytes is the size of the request body sent on the wire, after compression.
+	RequestBytes int64
+	// ResponseBytes is the size of the response body read from the wire, before decompression.
+	ResponseBytes int64
+	// Retries is the number of retries done by the client, the first attempt is not counted.
+	Retries int
+	// ContentEncoding of the request body, empty when it was not compressed.
+	ContentEncoding ContentEncoding
+	// StatusCode of the last HTTP response, zero when none was received.
+	StatusCode int
+	// Failed reports whether the call returned an error.
+	Failed bool
+	// ErrCode of the returned *Error, ErrCodeUnknown when the call succeeded.
+	ErrCode ErrCode
+	// APIErrCode returned by Meilisearch, empty when the server did not send one.
+	APIErrCode APIErrCode
+}
+
+// MetricsRecorder receives the measurements of every SDK call. RecordCall is called
+// synchronously at the end of the call and must be safe for concurrent use.
+type MetricsRecorder interface {
+	RecordCall(m CallMetrics)
+}
+
+func metricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, info *RequestInfo) error {
+			start := time.Now()
+			err := next(ctx, info)
+
+			stats := info.req.stats
+			m := CallMetrics{
+				Function:        info.Function,
+				Method:          info.Method,
+				Duration:        time.Since(start),
+				RequestBytes:    stats.requestBytes,
+				ResponseBytes:   stats.responseBytes,
+				Retries:         int(stats.retries),
+				ContentEncoding: stats.requestEncoding,
+				StatusCode:      stats.statusCode,
+				Failed:          err != nil,
+			}
+
+			var meiliErr *Error
+			if errors.As(err, &meiliErr) {
+				m.ErrCode = meiliErr.ErrCode
+				m.APIErrCode = meiliErr.APIError.Code
+			}
+
+			recorder.RecordCall(m)
+			return err
+		}
+	}
+}
+
+// durationBuckets are the upper bounds, in seconds, of the latency histogram kept by InMemoryMetrics.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// FunctionMetrics aggregates the calls made by a single SDK function.
+type FunctionMetrics struct {
+	Calls         int64
+	Failures      int64
+	Retries       int64
+	Compressed    int64
+	RequestBytes  int64
+	ResponseBytes int64
+	TotalDuration time.Duration
+	// DurationBuckets counts the calls per latency bucket, they are not cumulative. The upper bounds
+	// are 5ms, 10ms, 25ms, 50ms, 100ms, 250ms, 500ms, 1s, 2.5s, 5s and 10s, the last element
+	// counts the calls above 10s.
+	DurationBuckets []int64
+	ErrCodes        map[ErrCode]int64
+	APIErrCodes     map[APIErrCode]int64
+}
+
+// InMemoryMetrics is a MetricsRecorder that aggregates calls per function in memory.
+// It is meant for tests and for exposing metrics without pulling a metrics library.
+type InMemoryMetrics struct {
+	mu        sync.Mutex
+	functions map[string]*FunctionMetrics
+}
+
+// NewInMemoryMetrics returns an empty InMemoryMetrics.
+func NewInMemoryMetrics() *InMemoryMetrics {
+	return &InMemoryMetrics{functions: make(map[string]*FunctionMetrics)}
+}
+
+// RecordCall implements MetricsRecorder.
+func (im *InMemoryMetrics) RecordCall(m CallMetrics) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	fm, ok := im.functions[m.Function]
+	if !ok {
+		fm = &FunctionMetrics{
+			DurationBuckets: make([]int64, len(durationBuckets)+1),
+			ErrCodes:        make(map[ErrCode]int64),
+			APIErrCodes:     make(map[APIErrCode]int64),
+		}
+		im.functions[m.Function] = fm
+	}
+
+	fm.Calls++
+	fm.Retries += int64(m.Retries)
+	fm.RequestBytes += m.RequestBytes
+	fm.ResponseBytes += m.ResponseBytes
+	fm.TotalDuration += m.Duration
+	if !m.ContentEncoding.IsZero() {
+		fm.Compressed++
+	}
+	if m.Failed {
+		fm.Failures++
+		fm.ErrCodes[m.ErrCode]++
+		if m.APIErrCode != "" {
+			fm.APIErrCodes[m.APIErrCode]++
+		}
+	}
+
+	bucket := sort.SearchFloat64s(durationBuckets, m.Duration.Seconds())
+	fm.DurationBuckets[bucket]++
+}
+
+// Snapshot returns a deep copy of the metrics aggregated so far, keyed by function name.
+func (im *InMemoryMetrics) Snapshot() map[string]FunctionMetrics {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	snapshot := make(map[string]FunctionMetrics, len(im.functions))
+	for name, fm := range im.functions {
+		cp := *fm
+		cp.DurationBuckets = append([]int64(nil), fm.DurationBuckets...)
+		cp.ErrCodes = make(map[ErrCode]int64, len(fm.ErrCodes))
+		for k, v := range fm.ErrCodes {
+			cp.ErrCodes[k] = v
+		}
+		cp.APIErrCodes = make(map[APIErrCode]int64, len(fm.APIErrCodes))
+		for k, v := range fm.APIErrCodes {
+			cp.APIErrCodes[k] = v
+		}
+		snapshot[name] = cp
+	}
+	return snapshot
+}
+
+// Reset drops every metric aggregated so far.
+func (im *InMemoryMetrics) Reset() {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	im.functions = make(map[string]*FunctionMetrics)
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format, so they can be
+// served as is from a /metrics handler.
+func (im *InMemoryMetrics) WritePrometheus(w io.Writer) error {
+	snapshot := im.Snapshot()
+
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+
+	writeCounter := func(metric, help string, value func(fm FunctionMetrics) int64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", metric, help, metric)
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s{function=%q} %d\n", metric, name, value(snapshot[name]))
+		}
+	}
+
+	writeCounter("meilisearch_client_requests_total", "Number of SDK calls.",
+		func(fm FunctionMetrics) int64 { return fm.Calls })
+	writeCounter("meilisearch_client_retries_total", "Number of retries done by the client.",
+		func(fm FunctionMetrics) int64 { return fm.Retries })
+	writeCounter("meilisearch_client_compressed_requests_total", "Number of SDK calls with a compressed request body.",
+		func(fm FunctionMetrics) int64 { return fm.Compressed })
+	writeCounter("meilisearch_client_request_bytes_total", "Bytes sent in request bodies.",
+		func(fm FunctionMetrics) int64 { return fm.RequestBytes })
+	writeCounter("meilisearch_client_response_bytes_total", "Bytes received in response bodies.",
+		func(fm FunctionMetrics) int64 { return fm.ResponseBytes })
+
+	b.WriteString("# HELP meilisearch_client_errors_total Number of failed SDK calls.\n")
+	b.WriteString("# TYPE meilisearch_client_errors_total counter\n")
+	for _, name := range names {
+		fm := snapshot[name]
+		codes := make([]ErrCode, 0, len(fm.ErrCodes))
+		for code := range fm.ErrCodes {
+			codes = append(codes, code)
+		}
+		sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+		for _, code := range codes {
+			fmt.Fprintf(&b, "meilisearch_client_errors_total{function=%q,code=%q} %d\n", name, code.String(), fm.ErrCodes[code])
+		}
+	}
+
+	b.WriteString("# HELP meilisearch_client_api_errors_total Number of errors returned by Meilisearch, per error code.\n")
+	b.WriteString("# TYPE meilisearch_client_api_errors_total counter\n")
+	for _, name := range names {
+		fm := snapshot[name]
+		codes := make([]string, 0, len(fm.APIErrCodes))
+		for code := range fm.APIErrCodes {
+			codes = append(codes, string(code))
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			fmt.Fprintf(&b, "meilisearch_client_api_errors_total{function=%q,code=%q} %d\n", name, code, fm.APIErrCodes[APIErrCode(code)])
+		}
+	}
+
+	b.WriteString("# HELP meilisearch_client_request_duration_seconds Duration of SDK calls, retries included.\n")
+	b.WriteString("# TYPE meilisearch_client_request_duration_seconds histogram\n")
+	for _, name := range names {
+		fm := snapshot[name]
+		var cumulative int64
+		for i, le := range durationBuckets {
+			cumulative += fm.DurationBuckets[i]
+			fmt.Fprintf(&b, "meilisearch_client_request_duration_seconds_bucket{function=%q,le=\"%g\"} %d\n", name, le, cumulative)
+		}
+		cumulative += fm.DurationBuckets[len(durationBuckets)]
+		fmt.Fprintf(&b, "meilisearch_client_request_duration_seconds_bucket{function=%q,le=\"+Inf\"} %d\n", name, cumulative)
+		fmt.Fprintf(&b, "meilisearch_client_request_duration_seconds_sum{function=%q} %g\n", name, fm.TotalDuration.Seconds())
+		fmt.Fprintf(&b, "meilisearch_client_request_duration_seconds_count{function=%q} %d\n", name, fm.Calls)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
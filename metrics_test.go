@@ -0,0 +1,117 @@
+package meilisearch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_RecordCall(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte(`{"taskUid":1}`))
+	require.NoError(t, zw.Close())
+	gzipped := gz.Bytes()
+
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/indexes/movies/documents":
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Encoding", "gzip")
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write(gzipped)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Index movies not found.","code":"index_not_found","type":"invalid_request","link":""}`))
+		}
+	}))
+	defer ts.Close()
+
+	metrics := NewInMemoryMetrics()
+	sv := New(ts.URL, WithMetrics(metrics), WithContentEncoding(GzipEncoding, DefaultCompression))
+	sv.(*meilisearch).client.retryBackoff = func(uint8) time.Duration { return 0 }
+
+	_, err := sv.Index("movies").AddDocuments([]map[string]interface{}{{"id": 1}}, nil)
+	require.NoError(t, err)
+
+	sv = New(ts.URL, WithMetrics(metrics))
+	_, err = sv.Index("movies").FetchInfo()
+	require.Error(t, err)
+
+	snapshot := metrics.Snapshot()
+	require.Len(t, snapshot, 2)
+
+	add := snapshot["AddDocuments"]
+	require.Equal(t, int64(1), add.Calls)
+	require.Equal(t, int64(0), add.Failures)
+	require.Equal(t, int64(1), add.Retries)
+	require.Equal(t, int64(1), add.Compressed)
+	require.Greater(t, add.RequestBytes, int64(0))
+	require.Equal(t, int64(len(gzipped)), add.ResponseBytes)
+	require.Greater(t, add.TotalDuration, time.Duration(0))
+
+	fetch := snapshot["FetchInfo"]
+	require.Equal(t, int64(1), fetch.Calls)
+	require.Equal(t, int64(1), fetch.Failures)
+	require.Equal(t, int64(1), fetch.ErrCodes[APIError])
+	require.Equal(t, int64(1), fetch.APIErrCodes[APIErrCodeIndexNotFound])
+
+	// the snapshot is a copy
+	fetch.ErrCodes[APIError] = 10
+	require.Equal(t, int64(1), metrics.Snapshot()["FetchInfo"].ErrCodes[APIError])
+
+	metrics.Reset()
+	require.Empty(t, metrics.Snapshot())
+}
+
+func TestMetrics_WritePrometheus(t *testing.T) {
+	metrics := NewInMemoryMetrics()
+	metrics.RecordCall(CallMetrics{
+		Function:      "Search",
+		Duration:      20 * time.Millisecond,
+		RequestBytes:  10,
+		ResponseBytes: 100,
+	})
+	metrics.RecordCall(CallMetrics{
+		Function:   "Search",
+		Duration:   2 * time.Second,
+		Retries:    2,
+		Failed:     true,
+		ErrCode:    APIError,
+		APIErrCode: APIErrCodeTooManySearchRequests,
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, metrics.WritePrometheus(&buf))
+	out := buf.String()
+
+	for _, line := range []string{
+		`meilisearch_client_requests_total{function="Search"} 2`,
+		`meilisearch_client_retries_total{function="Search"} 2`,
+		`meilisearch_client_request_bytes_total{function="Search"} 10`,
+		`meilisearch_client_response_bytes_total{function="Search"} 100`,
+		`meilisearch_client_errors_total{function="Search",code="api_error"} 1`,
+		`meilisearch_client_api_errors_total{function="Search",code="too_many_search_requests"} 1`,
+		`meilisearch_client_request_duration_seconds_bucket{function="Search",le="0.01"} 0`,
+		`meilisearch_client_request_duration_seconds_bucket{function="Search",le="0.025"} 1`,
+		`meilisearch_client_request_duration_seconds_bucket{function="Search",le="2.5"} 2`,
+		`meilisearch_client_request_duration_seconds_bucket{function="Search",le="+Inf"} 2`,
+		`meilisearch_client_request_duration_seconds_count{function="Search"} 2`,
+		`# TYPE meilisearch_client_request_duration_seconds histogram`,
+	} {
+		require.True(t, strings.Contains(out, line+"\n"), "missing %q in:\n%s", line, out)
+	}
+}
@@ -0,0 +1,92 @@
+package meilisearch
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestInfo describes a single logical SDK call as seen by a Middleware.
+//
+// Only Header is read back by the client once the middleware chain reaches the
+// HTTP layer, every other field is informational.
+type RequestInfo struct {
+	// Function is the SDK function that issued the call, e.g. "Search" or "AddDocuments".
+	Function string
+
+	// Endpoint is the path of the request (host is not in)
+	Endpoint string
+
+	// Method is the HTTP verb of the request
+	Method string
+
+	// ContentType of the request body, empty when the call has no body
+	ContentType string
+
+	// Query holds the query parameters sent with the request
+	Query map[string]string
+
+	// Body is the request payload before it is serialized, nil when the call has no body
+	Body interface{}
+
+	// Response is the destination the response body is decoded into. It holds the
+	// decoded value once the next Handler returned without error.
+	Response interface{}
+
+	// StatusCode of the last HTTP response, set once the next Handler returned.
+	// It is zero when no response was received.
+	StatusCode int
+
+	// Header holds extra headers added to the HTTP request, they take precedence
+	// over the headers set by the client and by CallWithHeader.
+	Header http.Header
+
+	// Metadata holds the values attached to the call with CallWithMetadata, it is never
+	// sent to Meilisearch.
+	Metadata map[string]interface{}
+
+	req *internalRequest
+}
+
+type functionKey struct{}
+
+// FunctionFromContext returns the SDK function that issued the HTTP request whose context is ctx,
+// e.g. "Search", empty for a context the client did not create. It lets an http.RoundTripper
+// given to WithCustomClient tell the calls apart.
+func FunctionFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(functionKey{}).(string)
+	return name
+}
+
+// Handler executes a logical SDK call. The returned error is a *Error whenever
+// the failure happened while building, sending or decoding the request.
+type Handler func(ctx context.Context, info *RequestInfo) error
+
+// Middleware wraps a Handler to run code before and after every logical SDK call.
+// A middleware must call next to let the request through.
+type Middleware func(next Handler) Handler
+
+func newRequestInfo(req *internalRequest) *RequestInfo {
+	return &RequestInfo{
+		Function:    req.functionName,
+		Endpoint:    req.endpoint,
+		Method:      req.method,
+		ContentType: req.contentType,
+		Query:       req.withQueryParams,
+		Body:        req.withRequest,
+		Response:    req.withResponse,
+		Header:      make(http.Header),
+		req:         req,
+	}
+}
+
+// chainMiddlewares composes middlewares around final, the first middleware is the outermost one.
+func chainMiddlewares(final Handler, middlewares ...Middleware) Handler {
+	h := final
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if middlewares[i] == nil {
+			continue
+		}
+		h = middlewares[i](h)
+	}
+	return h
+}
@@ -0,0 +1,129 @@
+package meilisearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_Order(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, info *RequestInfo) error {
+				calls = append(calls, name+":before")
+				err := next(ctx, info)
+				calls = append(calls, name+":after")
+				return err
+			}
+		}
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "server")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"available"}`))
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL, WithMiddleware(record("first"), nil), WithMiddleware(record("second")))
+	_, err := sv.Health()
+	require.NoError(t, err)
+	require.Equal(t, []string{"first:before", "second:before", "server", "second:after", "first:after"}, calls)
+}
+
+func TestMiddleware_RequestInfo(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "audit-1", r.Header.Get("X-Audit-Id"))
+		require.Equal(t, "custom-agent", r.Header.Get("User-Agent"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"pkgVersion":"1.0.0"}`))
+	}))
+	defer ts.Close()
+
+	var got *RequestInfo
+	mw := func(next Handler) Handler {
+		return func(ctx context.Context, info *RequestInfo) error {
+			info.Header.Set("X-Audit-Id", "audit-1")
+			info.Header.Set("User-Agent", "custom-agent")
+			err := next(ctx, info)
+			got = info
+			return err
+		}
+	}
+
+	sv := New(ts.URL, WithMiddleware(mw))
+	ver, err := sv.Version()
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", ver.PkgVersion)
+
+	require.NotNil(t, got)
+	require.Equal(t, "Version", got.Function)
+	require.Equal(t, "/version", got.Endpoint)
+	require.Equal(t, http.MethodGet, got.Method)
+	require.Same(t, ver, got.Response)
+}
+
+func TestMiddleware_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Index movies not found.","code":"index_not_found","type":"invalid_request","link":""}`))
+	}))
+	defer ts.Close()
+
+	var apiErr *Error
+	mw := func(next Handler) Handler {
+		return func(ctx context.Context, info *RequestInfo) error {
+			err := next(ctx, info)
+			require.True(t, errors.As(err, &apiErr))
+			return err
+		}
+	}
+
+	sv := New(ts.URL, WithMiddleware(mw), DisableRetries())
+	_, err := sv.GetIndex("movies")
+	require.Error(t, err)
+	require.NotNil(t, apiErr)
+	require.Equal(t, "FetchInfo", apiErr.Function)
+	require.True(t, apiErr.HasCode(APIErrCodeIndexNotFound))
+}
+
+func TestMiddleware_ShortCircuit(t *testing.T) {
+	errDenied := errors.New("denied by policy")
+	mw := func(next Handler) Handler {
+		return func(ctx context.Context, info *RequestInfo) error {
+			if info.Method != http.MethodGet {
+				return errDenied
+			}
+			return next(ctx, info)
+		}
+	}
+
+	sv := New("http://localhost:1", WithMiddleware(mw))
+	_, err := sv.DeleteIndex("movies")
+	require.ErrorIs(t, err, errDenied)
+}
+
+func TestFunctionFromContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"available"}`))
+	}))
+	defer ts.Close()
+
+	var functions []string
+	transport := taskDocumentRoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		functions = append(functions, FunctionFromContext(req.Context()))
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	sv := New(ts.URL, WithCustomClient(&http.Client{Transport: transport}))
+	_, err := sv.Health()
+	require.NoError(t, err)
+	require.Equal(t, []string{"Health"}, functions)
+	require.Empty(t, FunctionFromContext(context.Background()))
+}
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/meilisearch/meilisearch-go"
@@ -1662,6 +1663,87 @@ func (_c *MockmeilisearchServiceManager_DeleteWebhookWithContext_Call) RunAndRet
 	return _c
 }
 
+// Do provides a mock function for the type MockmeilisearchServiceManager
+func (_mock *MockmeilisearchServiceManager) Do(ctx context.Context, method string, path string, query map[string]string, body interface{}, out interface{}) error {
+	ret := _mock.Called(ctx, method, path, query, body, out)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Do")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, map[string]string, interface{}, interface{}) error); ok {
+		r0 = returnFunc(ctx, method, path, query, body, out)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockmeilisearchServiceManager_Do_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Do'
+type MockmeilisearchServiceManager_Do_Call struct {
+	*mock.Call
+}
+
+// Do is a helper method to define mock.On call
+//   - ctx context.Context
+//   - method string
+//   - path string
+//   - query map[string]string
+//   - body interface{}
+//   - out interface{}
+func (_e *MockmeilisearchServiceManager_Expecter) Do(ctx interface{}, method interface{}, path interface{}, query interface{}, body interface{}, out interface{}) *MockmeilisearchServiceManager_Do_Call {
+	return &MockmeilisearchServiceManager_Do_Call{Call: _e.mock.On("Do", ctx, method, path, query, body, out)}
+}
+
+func (_c *MockmeilisearchServiceManager_Do_Call) Run(run func(ctx context.Context, method string, path string, query map[string]string, body interface{}, out interface{})) *MockmeilisearchServiceManager_Do_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 map[string]string
+		if args[3] != nil {
+			arg3 = args[3].(map[string]string)
+		}
+		var arg4 interface{}
+		if args[4] != nil {
+			arg4 = args[4].(interface{})
+		}
+		var arg5 interface{}
+		if args[5] != nil {
+			arg5 = args[5].(interface{})
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_Do_Call) Return(err error) *MockmeilisearchServiceManager_Do_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_Do_Call) RunAndReturn(run func(ctx context.Context, method string, path string, query map[string]string, body interface{}, out interface{}) error) *MockmeilisearchServiceManager_Do_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ExperimentalFeatures provides a mock function for the type MockmeilisearchServiceManager
 func (_mock *MockmeilisearchServiceManager) ExperimentalFeatures() *meilisearch.ExperimentalFeatures {
 	ret := _mock.Called()
@@ -1838,6 +1920,126 @@ func (_c *MockmeilisearchServiceManager_ExportWithContext_Call) RunAndReturn(run
 	return _c
 }
 
+// ForEachTaskDocument provides a mock function for the type MockmeilisearchServiceManager
+func (_mock *MockmeilisearchServiceManager) ForEachTaskDocument(taskUID int64, fn func(document json.RawMessage) error) error {
+	ret := _mock.Called(taskUID, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForEachTaskDocument")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(int64, func(document json.RawMessage) error) error); ok {
+		r0 = returnFunc(taskUID, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockmeilisearchServiceManager_ForEachTaskDocument_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForEachTaskDocument'
+type MockmeilisearchServiceManager_ForEachTaskDocument_Call struct {
+	*mock.Call
+}
+
+// ForEachTaskDocument is a helper method to define mock.On call
+//   - taskUID int64
+//   - fn func(document json.RawMessage) error
+func (_e *MockmeilisearchServiceManager_Expecter) ForEachTaskDocument(taskUID interface{}, fn interface{}) *MockmeilisearchServiceManager_ForEachTaskDocument_Call {
+	return &MockmeilisearchServiceManager_ForEachTaskDocument_Call{Call: _e.mock.On("ForEachTaskDocument", taskUID, fn)}
+}
+
+func (_c *MockmeilisearchServiceManager_ForEachTaskDocument_Call) Run(run func(taskUID int64, fn func(document json.RawMessage) error)) *MockmeilisearchServiceManager_ForEachTaskDocument_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int64
+		if args[0] != nil {
+			arg0 = args[0].(int64)
+		}
+		var arg1 func(document json.RawMessage) error
+		if args[1] != nil {
+			arg1 = args[1].(func(document json.RawMessage) error)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_ForEachTaskDocument_Call) Return(err error) *MockmeilisearchServiceManager_ForEachTaskDocument_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_ForEachTaskDocument_Call) RunAndReturn(run func(taskUID int64, fn func(document json.RawMessage) error) error) *MockmeilisearchServiceManager_ForEachTaskDocument_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ForEachTaskDocumentWithContext provides a mock function for the type MockmeilisearchServiceManager
+func (_mock *MockmeilisearchServiceManager) ForEachTaskDocumentWithContext(ctx context.Context, taskUID int64, fn func(document json.RawMessage) error) error {
+	ret := _mock.Called(ctx, taskUID, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForEachTaskDocumentWithContext")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, func(document json.RawMessage) error) error); ok {
+		r0 = returnFunc(ctx, taskUID, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockmeilisearchServiceManager_ForEachTaskDocumentWithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForEachTaskDocumentWithContext'
+type MockmeilisearchServiceManager_ForEachTaskDocumentWithContext_Call struct {
+	*mock.Call
+}
+
+// ForEachTaskDocumentWithContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - taskUID int64
+//   - fn func(document json.RawMessage) error
+func (_e *MockmeilisearchServiceManager_Expecter) ForEachTaskDocumentWithContext(ctx interface{}, taskUID interface{}, fn interface{}) *MockmeilisearchServiceManager_ForEachTaskDocumentWithContext_Call {
+	return &MockmeilisearchServiceManager_ForEachTaskDocumentWithContext_Call{Call: _e.mock.On("ForEachTaskDocumentWithContext", ctx, taskUID, fn)}
+}
+
+func (_c *MockmeilisearchServiceManager_ForEachTaskDocumentWithContext_Call) Run(run func(ctx context.Context, taskUID int64, fn func(document json.RawMessage) error)) *MockmeilisearchServiceManager_ForEachTaskDocumentWithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 func(document json.RawMessage) error
+		if args[2] != nil {
+			arg2 = args[2].(func(document json.RawMessage) error)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_ForEachTaskDocumentWithContext_Call) Return(err error) *MockmeilisearchServiceManager_ForEachTaskDocumentWithContext_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_ForEachTaskDocumentWithContext_Call) RunAndReturn(run func(ctx context.Context, taskUID int64, fn func(document json.RawMessage) error) error) *MockmeilisearchServiceManager_ForEachTaskDocumentWithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GenerateTenantToken provides a mock function for the type MockmeilisearchServiceManager
 func (_mock *MockmeilisearchServiceManager) GenerateTenantToken(apiKeyUID string, searchRules map[string]interface{}, options *meilisearch.TenantTokenOptions) (string, error) {
 	ret := _mock.Called(apiKeyUID, searchRules, options)
@@ -5295,6 +5497,59 @@ func (_c *MockmeilisearchServiceManager_SwapIndexesWithContext_Call) RunAndRetur
 	return _c
 }
 
+// TaskHandle provides a mock function for the type MockmeilisearchServiceManager
+func (_mock *MockmeilisearchServiceManager) TaskHandle(taskInfo *meilisearch.TaskInfo) meilisearch.TaskHandle {
+	ret := _mock.Called(taskInfo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskHandle")
+	}
+
+	var r0 meilisearch.TaskHandle
+	if returnFunc, ok := ret.Get(0).(func(*meilisearch.TaskInfo) meilisearch.TaskHandle); ok {
+		r0 = returnFunc(taskInfo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(meilisearch.TaskHandle)
+		}
+	}
+	return r0
+}
+
+// MockmeilisearchServiceManager_TaskHandle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TaskHandle'
+type MockmeilisearchServiceManager_TaskHandle_Call struct {
+	*mock.Call
+}
+
+// TaskHandle is a helper method to define mock.On call
+//   - taskInfo *meilisearch.TaskInfo
+func (_e *MockmeilisearchServiceManager_Expecter) TaskHandle(taskInfo interface{}) *MockmeilisearchServiceManager_TaskHandle_Call {
+	return &MockmeilisearchServiceManager_TaskHandle_Call{Call: _e.mock.On("TaskHandle", taskInfo)}
+}
+
+func (_c *MockmeilisearchServiceManager_TaskHandle_Call) Run(run func(taskInfo *meilisearch.TaskInfo)) *MockmeilisearchServiceManager_TaskHandle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *meilisearch.TaskInfo
+		if args[0] != nil {
+			arg0 = args[0].(*meilisearch.TaskInfo)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_TaskHandle_Call) Return(taskHandle meilisearch.TaskHandle) *MockmeilisearchServiceManager_TaskHandle_Call {
+	_c.Call.Return(taskHandle)
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_TaskHandle_Call) RunAndReturn(run func(taskInfo *meilisearch.TaskInfo) meilisearch.TaskHandle) *MockmeilisearchServiceManager_TaskHandle_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // TaskManager provides a mock function for the type MockmeilisearchServiceManager
 func (_mock *MockmeilisearchServiceManager) TaskManager() meilisearch.TaskManager {
 	ret := _mock.Called()
@@ -6344,6 +6599,154 @@ func (_c *MockmeilisearchServiceManager_WaitForTaskWithContext_Call) RunAndRetur
 	return _c
 }
 
+// WaitForTaskWithOptions provides a mock function for the type MockmeilisearchServiceManager
+func (_mock *MockmeilisearchServiceManager) WaitForTaskWithOptions(ctx context.Context, taskUID int64, opts *meilisearch.WaitOptions) (*meilisearch.Task, error) {
+	ret := _mock.Called(ctx, taskUID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WaitForTaskWithOptions")
+	}
+
+	var r0 *meilisearch.Task
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, *meilisearch.WaitOptions) (*meilisearch.Task, error)); ok {
+		return returnFunc(ctx, taskUID, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, *meilisearch.WaitOptions) *meilisearch.Task); ok {
+		r0 = returnFunc(ctx, taskUID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*meilisearch.Task)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, *meilisearch.WaitOptions) error); ok {
+		r1 = returnFunc(ctx, taskUID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockmeilisearchServiceManager_WaitForTaskWithOptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WaitForTaskWithOptions'
+type MockmeilisearchServiceManager_WaitForTaskWithOptions_Call struct {
+	*mock.Call
+}
+
+// WaitForTaskWithOptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - taskUID int64
+//   - opts *meilisearch.WaitOptions
+func (_e *MockmeilisearchServiceManager_Expecter) WaitForTaskWithOptions(ctx interface{}, taskUID interface{}, opts interface{}) *MockmeilisearchServiceManager_WaitForTaskWithOptions_Call {
+	return &MockmeilisearchServiceManager_WaitForTaskWithOptions_Call{Call: _e.mock.On("WaitForTaskWithOptions", ctx, taskUID, opts)}
+}
+
+func (_c *MockmeilisearchServiceManager_WaitForTaskWithOptions_Call) Run(run func(ctx context.Context, taskUID int64, opts *meilisearch.WaitOptions)) *MockmeilisearchServiceManager_WaitForTaskWithOptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 *meilisearch.WaitOptions
+		if args[2] != nil {
+			arg2 = args[2].(*meilisearch.WaitOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_WaitForTaskWithOptions_Call) Return(task *meilisearch.Task, err error) *MockmeilisearchServiceManager_WaitForTaskWithOptions_Call {
+	_c.Call.Return(task, err)
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_WaitForTaskWithOptions_Call) RunAndReturn(run func(ctx context.Context, taskUID int64, opts *meilisearch.WaitOptions) (*meilisearch.Task, error)) *MockmeilisearchServiceManager_WaitForTaskWithOptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WaitForTasks provides a mock function for the type MockmeilisearchServiceManager
+func (_mock *MockmeilisearchServiceManager) WaitForTasks(ctx context.Context, taskUIDs []int64, opts *meilisearch.WaitOptions) (map[int64]*meilisearch.Task, error) {
+	ret := _mock.Called(ctx, taskUIDs, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WaitForTasks")
+	}
+
+	var r0 map[int64]*meilisearch.Task
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64, *meilisearch.WaitOptions) (map[int64]*meilisearch.Task, error)); ok {
+		return returnFunc(ctx, taskUIDs, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64, *meilisearch.WaitOptions) map[int64]*meilisearch.Task); ok {
+		r0 = returnFunc(ctx, taskUIDs, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64]*meilisearch.Task)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []int64, *meilisearch.WaitOptions) error); ok {
+		r1 = returnFunc(ctx, taskUIDs, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockmeilisearchServiceManager_WaitForTasks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WaitForTasks'
+type MockmeilisearchServiceManager_WaitForTasks_Call struct {
+	*mock.Call
+}
+
+// WaitForTasks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - taskUIDs []int64
+//   - opts *meilisearch.WaitOptions
+func (_e *MockmeilisearchServiceManager_Expecter) WaitForTasks(ctx interface{}, taskUIDs interface{}, opts interface{}) *MockmeilisearchServiceManager_WaitForTasks_Call {
+	return &MockmeilisearchServiceManager_WaitForTasks_Call{Call: _e.mock.On("WaitForTasks", ctx, taskUIDs, opts)}
+}
+
+func (_c *MockmeilisearchServiceManager_WaitForTasks_Call) Run(run func(ctx context.Context, taskUIDs []int64, opts *meilisearch.WaitOptions)) *MockmeilisearchServiceManager_WaitForTasks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []int64
+		if args[1] != nil {
+			arg1 = args[1].([]int64)
+		}
+		var arg2 *meilisearch.WaitOptions
+		if args[2] != nil {
+			arg2 = args[2].(*meilisearch.WaitOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_WaitForTasks_Call) Return(int64ToTask map[int64]*meilisearch.Task, err error) *MockmeilisearchServiceManager_WaitForTasks_Call {
+	_c.Call.Return(int64ToTask, err)
+	return _c
+}
+
+func (_c *MockmeilisearchServiceManager_WaitForTasks_Call) RunAndReturn(run func(ctx context.Context, taskUIDs []int64, opts *meilisearch.WaitOptions) (map[int64]*meilisearch.Task, error)) *MockmeilisearchServiceManager_WaitForTasks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // WebhookManager provides a mock function for the type MockmeilisearchServiceManager
 func (_mock *MockmeilisearchServiceManager) WebhookManager() meilisearch.WebhookManager {
 	ret := _mock.Called()
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/meilisearch/meilisearch-go"
@@ -299,6 +300,126 @@ func (_c *MockmeilisearchTaskManager_DeleteTasksWithContext_Call) RunAndReturn(r
 	return _c
 }
 
+// ForEachTaskDocument provides a mock function for the type MockmeilisearchTaskManager
+func (_mock *MockmeilisearchTaskManager) ForEachTaskDocument(taskUID int64, fn func(document json.RawMessage) error) error {
+	ret := _mock.Called(taskUID, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForEachTaskDocument")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(int64, func(document json.RawMessage) error) error); ok {
+		r0 = returnFunc(taskUID, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockmeilisearchTaskManager_ForEachTaskDocument_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForEachTaskDocument'
+type MockmeilisearchTaskManager_ForEachTaskDocument_Call struct {
+	*mock.Call
+}
+
+// ForEachTaskDocument is a helper method to define mock.On call
+//   - taskUID int64
+//   - fn func(document json.RawMessage) error
+func (_e *MockmeilisearchTaskManager_Expecter) ForEachTaskDocument(taskUID interface{}, fn interface{}) *MockmeilisearchTaskManager_ForEachTaskDocument_Call {
+	return &MockmeilisearchTaskManager_ForEachTaskDocument_Call{Call: _e.mock.On("ForEachTaskDocument", taskUID, fn)}
+}
+
+func (_c *MockmeilisearchTaskManager_ForEachTaskDocument_Call) Run(run func(taskUID int64, fn func(document json.RawMessage) error)) *MockmeilisearchTaskManager_ForEachTaskDocument_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int64
+		if args[0] != nil {
+			arg0 = args[0].(int64)
+		}
+		var arg1 func(document json.RawMessage) error
+		if args[1] != nil {
+			arg1 = args[1].(func(document json.RawMessage) error)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchTaskManager_ForEachTaskDocument_Call) Return(err error) *MockmeilisearchTaskManager_ForEachTaskDocument_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockmeilisearchTaskManager_ForEachTaskDocument_Call) RunAndReturn(run func(taskUID int64, fn func(document json.RawMessage) error) error) *MockmeilisearchTaskManager_ForEachTaskDocument_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ForEachTaskDocumentWithContext provides a mock function for the type MockmeilisearchTaskManager
+func (_mock *MockmeilisearchTaskManager) ForEachTaskDocumentWithContext(ctx context.Context, taskUID int64, fn func(document json.RawMessage) error) error {
+	ret := _mock.Called(ctx, taskUID, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForEachTaskDocumentWithContext")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, func(document json.RawMessage) error) error); ok {
+		r0 = returnFunc(ctx, taskUID, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockmeilisearchTaskManager_ForEachTaskDocumentWithContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForEachTaskDocumentWithContext'
+type MockmeilisearchTaskManager_ForEachTaskDocumentWithContext_Call struct {
+	*mock.Call
+}
+
+// ForEachTaskDocumentWithContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - taskUID int64
+//   - fn func(document json.RawMessage) error
+func (_e *MockmeilisearchTaskManager_Expecter) ForEachTaskDocumentWithContext(ctx interface{}, taskUID interface{}, fn interface{}) *MockmeilisearchTaskManager_ForEachTaskDocumentWithContext_Call {
+	return &MockmeilisearchTaskManager_ForEachTaskDocumentWithContext_Call{Call: _e.mock.On("ForEachTaskDocumentWithContext", ctx, taskUID, fn)}
+}
+
+func (_c *MockmeilisearchTaskManager_ForEachTaskDocumentWithContext_Call) Run(run func(ctx context.Context, taskUID int64, fn func(document json.RawMessage) error)) *MockmeilisearchTaskManager_ForEachTaskDocumentWithContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 func(document json.RawMessage) error
+		if args[2] != nil {
+			arg2 = args[2].(func(document json.RawMessage) error)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchTaskManager_ForEachTaskDocumentWithContext_Call) Return(err error) *MockmeilisearchTaskManager_ForEachTaskDocumentWithContext_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockmeilisearchTaskManager_ForEachTaskDocumentWithContext_Call) RunAndReturn(run func(ctx context.Context, taskUID int64, fn func(document json.RawMessage) error) error) *MockmeilisearchTaskManager_ForEachTaskDocumentWithContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTask provides a mock function for the type MockmeilisearchTaskManager
 func (_mock *MockmeilisearchTaskManager) GetTask(taskUID int64) (*meilisearch.Task, error) {
 	ret := _mock.Called(taskUID)
@@ -820,3 +941,151 @@ func (_c *MockmeilisearchTaskManager_WaitForTaskWithContext_Call) RunAndReturn(r
 	_c.Call.Return(run)
 	return _c
 }
+
+// WaitForTaskWithOptions provides a mock function for the type MockmeilisearchTaskManager
+func (_mock *MockmeilisearchTaskManager) WaitForTaskWithOptions(ctx context.Context, taskUID int64, opts *meilisearch.WaitOptions) (*meilisearch.Task, error) {
+	ret := _mock.Called(ctx, taskUID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WaitForTaskWithOptions")
+	}
+
+	var r0 *meilisearch.Task
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, *meilisearch.WaitOptions) (*meilisearch.Task, error)); ok {
+		return returnFunc(ctx, taskUID, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, *meilisearch.WaitOptions) *meilisearch.Task); ok {
+		r0 = returnFunc(ctx, taskUID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*meilisearch.Task)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, *meilisearch.WaitOptions) error); ok {
+		r1 = returnFunc(ctx, taskUID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockmeilisearchTaskManager_WaitForTaskWithOptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WaitForTaskWithOptions'
+type MockmeilisearchTaskManager_WaitForTaskWithOptions_Call struct {
+	*mock.Call
+}
+
+// WaitForTaskWithOptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - taskUID int64
+//   - opts *meilisearch.WaitOptions
+func (_e *MockmeilisearchTaskManager_Expecter) WaitForTaskWithOptions(ctx interface{}, taskUID interface{}, opts interface{}) *MockmeilisearchTaskManager_WaitForTaskWithOptions_Call {
+	return &MockmeilisearchTaskManager_WaitForTaskWithOptions_Call{Call: _e.mock.On("WaitForTaskWithOptions", ctx, taskUID, opts)}
+}
+
+func (_c *MockmeilisearchTaskManager_WaitForTaskWithOptions_Call) Run(run func(ctx context.Context, taskUID int64, opts *meilisearch.WaitOptions)) *MockmeilisearchTaskManager_WaitForTaskWithOptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 *meilisearch.WaitOptions
+		if args[2] != nil {
+			arg2 = args[2].(*meilisearch.WaitOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchTaskManager_WaitForTaskWithOptions_Call) Return(task *meilisearch.Task, err error) *MockmeilisearchTaskManager_WaitForTaskWithOptions_Call {
+	_c.Call.Return(task, err)
+	return _c
+}
+
+func (_c *MockmeilisearchTaskManager_WaitForTaskWithOptions_Call) RunAndReturn(run func(ctx context.Context, taskUID int64, opts *meilisearch.WaitOptions) (*meilisearch.Task, error)) *MockmeilisearchTaskManager_WaitForTaskWithOptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WaitForTasks provides a mock function for the type MockmeilisearchTaskManager
+func (_mock *MockmeilisearchTaskManager) WaitForTasks(ctx context.Context, taskUIDs []int64, opts *meilisearch.WaitOptions) (map[int64]*meilisearch.Task, error) {
+	ret := _mock.Called(ctx, taskUIDs, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WaitForTasks")
+	}
+
+	var r0 map[int64]*meilisearch.Task
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64, *meilisearch.WaitOptions) (map[int64]*meilisearch.Task, error)); ok {
+		return returnFunc(ctx, taskUIDs, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64, *meilisearch.WaitOptions) map[int64]*meilisearch.Task); ok {
+		r0 = returnFunc(ctx, taskUIDs, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64]*meilisearch.Task)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []int64, *meilisearch.WaitOptions) error); ok {
+		r1 = returnFunc(ctx, taskUIDs, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockmeilisearchTaskManager_WaitForTasks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WaitForTasks'
+type MockmeilisearchTaskManager_WaitForTasks_Call struct {
+	*mock.Call
+}
+
+// WaitForTasks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - taskUIDs []int64
+//   - opts *meilisearch.WaitOptions
+func (_e *MockmeilisearchTaskManager_Expecter) WaitForTasks(ctx interface{}, taskUIDs interface{}, opts interface{}) *MockmeilisearchTaskManager_WaitForTasks_Call {
+	return &MockmeilisearchTaskManager_WaitForTasks_Call{Call: _e.mock.On("WaitForTasks", ctx, taskUIDs, opts)}
+}
+
+func (_c *MockmeilisearchTaskManager_WaitForTasks_Call) Run(run func(ctx context.Context, taskUIDs []int64, opts *meilisearch.WaitOptions)) *MockmeilisearchTaskManager_WaitForTasks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []int64
+		if args[1] != nil {
+			arg1 = args[1].([]int64)
+		}
+		var arg2 *meilisearch.WaitOptions
+		if args[2] != nil {
+			arg2 = args[2].(*meilisearch.WaitOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchTaskManager_WaitForTasks_Call) Return(int64ToTask map[int64]*meilisearch.Task, err error) *MockmeilisearchTaskManager_WaitForTasks_Call {
+	_c.Call.Return(int64ToTask, err)
+	return _c
+}
+
+func (_c *MockmeilisearchTaskManager_WaitForTasks_Call) RunAndReturn(run func(ctx context.Context, taskUIDs []int64, opts *meilisearch.WaitOptions) (map[int64]*meilisearch.Task, error)) *MockmeilisearchTaskManager_WaitForTasks_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -440,3 +440,151 @@ func (_c *MockmeilisearchTaskReader_WaitForTaskWithContext_Call) RunAndReturn(ru
 	_c.Call.Return(run)
 	return _c
 }
+
+// WaitForTaskWithOptions provides a mock function for the type MockmeilisearchTaskReader
+func (_mock *MockmeilisearchTaskReader) WaitForTaskWithOptions(ctx context.Context, taskUID int64, opts *meilisearch.WaitOptions) (*meilisearch.Task, error) {
+	ret := _mock.Called(ctx, taskUID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WaitForTaskWithOptions")
+	}
+
+	var r0 *meilisearch.Task
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, *meilisearch.WaitOptions) (*meilisearch.Task, error)); ok {
+		return returnFunc(ctx, taskUID, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, *meilisearch.WaitOptions) *meilisearch.Task); ok {
+		r0 = returnFunc(ctx, taskUID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*meilisearch.Task)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, *meilisearch.WaitOptions) error); ok {
+		r1 = returnFunc(ctx, taskUID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockmeilisearchTaskReader_WaitForTaskWithOptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WaitForTaskWithOptions'
+type MockmeilisearchTaskReader_WaitForTaskWithOptions_Call struct {
+	*mock.Call
+}
+
+// WaitForTaskWithOptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - taskUID int64
+//   - opts *meilisearch.WaitOptions
+func (_e *MockmeilisearchTaskReader_Expecter) WaitForTaskWithOptions(ctx interface{}, taskUID interface{}, opts interface{}) *MockmeilisearchTaskReader_WaitForTaskWithOptions_Call {
+	return &MockmeilisearchTaskReader_WaitForTaskWithOptions_Call{Call: _e.mock.On("WaitForTaskWithOptions", ctx, taskUID, opts)}
+}
+
+func (_c *MockmeilisearchTaskReader_WaitForTaskWithOptions_Call) Run(run func(ctx context.Context, taskUID int64, opts *meilisearch.WaitOptions)) *MockmeilisearchTaskReader_WaitForTaskWithOptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 *meilisearch.WaitOptions
+		if args[2] != nil {
+			arg2 = args[2].(*meilisearch.WaitOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchTaskReader_WaitForTaskWithOptions_Call) Return(task *meilisearch.Task, err error) *MockmeilisearchTaskReader_WaitForTaskWithOptions_Call {
+	_c.Call.Return(task, err)
+	return _c
+}
+
+func (_c *MockmeilisearchTaskReader_WaitForTaskWithOptions_Call) RunAndReturn(run func(ctx context.Context, taskUID int64, opts *meilisearch.WaitOptions) (*meilisearch.Task, error)) *MockmeilisearchTaskReader_WaitForTaskWithOptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WaitForTasks provides a mock function for the type MockmeilisearchTaskReader
+func (_mock *MockmeilisearchTaskReader) WaitForTasks(ctx context.Context, taskUIDs []int64, opts *meilisearch.WaitOptions) (map[int64]*meilisearch.Task, error) {
+	ret := _mock.Called(ctx, taskUIDs, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WaitForTasks")
+	}
+
+	var r0 map[int64]*meilisearch.Task
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64, *meilisearch.WaitOptions) (map[int64]*meilisearch.Task, error)); ok {
+		return returnFunc(ctx, taskUIDs, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int64, *meilisearch.WaitOptions) map[int64]*meilisearch.Task); ok {
+		r0 = returnFunc(ctx, taskUIDs, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64]*meilisearch.Task)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []int64, *meilisearch.WaitOptions) error); ok {
+		r1 = returnFunc(ctx, taskUIDs, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockmeilisearchTaskReader_WaitForTasks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WaitForTasks'
+type MockmeilisearchTaskReader_WaitForTasks_Call struct {
+	*mock.Call
+}
+
+// WaitForTasks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - taskUIDs []int64
+//   - opts *meilisearch.WaitOptions
+func (_e *MockmeilisearchTaskReader_Expecter) WaitForTasks(ctx interface{}, taskUIDs interface{}, opts interface{}) *MockmeilisearchTaskReader_WaitForTasks_Call {
+	return &MockmeilisearchTaskReader_WaitForTasks_Call{Call: _e.mock.On("WaitForTasks", ctx, taskUIDs, opts)}
+}
+
+func (_c *MockmeilisearchTaskReader_WaitForTasks_Call) Run(run func(ctx context.Context, taskUIDs []int64, opts *meilisearch.WaitOptions)) *MockmeilisearchTaskReader_WaitForTasks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []int64
+		if args[1] != nil {
+			arg1 = args[1].([]int64)
+		}
+		var arg2 *meilisearch.WaitOptions
+		if args[2] != nil {
+			arg2 = args[2].(*meilisearch.WaitOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockmeilisearchTaskReader_WaitForTasks_Call) Return(int64ToTask map[int64]*meilisearch.Task, err error) *MockmeilisearchTaskReader_WaitForTasks_Call {
+	_c.Call.Return(int64ToTask, err)
+	return _c
+}
+
+func (_c *MockmeilisearchTaskReader_WaitForTasks_Call) RunAndReturn(run func(ctx context.Context, taskUIDs []int64, opts *meilisearch.WaitOptions) (map[int64]*meilisearch.Task, error)) *MockmeilisearchTaskReader_WaitForTasks_Call {
+	_c.Call.Return(run)
+	return _c
+}
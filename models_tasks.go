@@ -1,6 +1,10 @@
 package meilisearch
 
-import "time"
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
 
 // Task indicates information about a task resource
 //
@@ -9,6 +13,7 @@ type Task struct {
 	Status         TaskStatus          `json:"status"`
 	UID            int64               `json:"uid,omitempty"`
 	TaskUID        int64               `json:"taskUid,omitempty"`
+	BatchUID       *int64              `json:"batchUid,omitempty"`
 	IndexUID       string              `json:"indexUid"`
 	Type           TaskType            `json:"type"`
 	Error          meilisearchApiError `json:"error,omitempty"`
@@ -22,6 +27,22 @@ type Task struct {
 	CustomMetadata string              `json:"customMetadata,omitempty"`
 }
 
+// Err returns the error of a failed task as an *Error with the TaskFailed ErrCode, so that it
+// can be handled like the errors of the client, with errors.Is and the Is* classifiers. It
+// returns nil unless the task failed.
+func (t *Task) Err() error {
+	if t.Status != TaskStatusFailed {
+		return nil
+	}
+	uid := t.UID
+	if uid == 0 {
+		uid = t.TaskUID
+	}
+	e := newCallError("", http.MethodGet, fmt.Sprintf("/tasks/%d", uid))
+	e.APIError = t.Error
+	return e.WithErrCode(TaskFailed)
+}
+
 // TaskNetwork indicates information about a task network
 //
 // Documentation: https://www.meilisearch.com/docs/reference/api/tasks#network
@@ -0,0 +1,182 @@
+package meilisearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultHealthProbeInterval = 5 * time.Second
+
+// balancedFunctions are the read-only SDK functions spread across the healthy nodes,
+// every other call is sent to the primary.
+var balancedFunctions = map[string]bool{
+	"Search":                 true,
+	"SearchRaw":              true,
+	"MultiSearch":            true,
+	"FacetSearch":            true,
+	"SearchSimilarDocuments": true,
+	"GetDocuments":           true,
+	"GetDocument":            true,
+	"GetSettings":            true,
+}
+
+// node is a single Meilisearch instance of a multi-node client.
+type node struct {
+	host    string
+	ejected atomic.Bool
+}
+
+// nodePool routes requests between a primary and its replicas. Writes are pinned to the
+// primary, reads in balancedFunctions are spread round-robin across the nodes that are
+// not ejected. A node failing with a transport error or a 5xx is ejected until a
+// background Health probe succeeds on it.
+type nodePool struct {
+	client        *http.Client
+	nodes         []*node // nodes[0] is the primary
+	next          atomic.Uint64
+	probeInterval time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func newNodePool(cli *http.Client, primary string, replicas []string, probeInterval time.Duration) *nodePool {
+	if probeInterval <= 0 {
+		probeInterval = defaultHealthProbeInterval
+	}
+
+	p := &nodePool{
+		client:        cli,
+		nodes:         []*node{{host: primary}},
+		probeInterval: probeInterval,
+		stop:          make(chan struct{}),
+	}
+
+	seen := map[string]bool{primary: true}
+	for _, host := range replicas {
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		p.nodes = append(p.nodes, &node{host: host})
+	}
+
+	p.wg.Add(1)
+	go p.probeLoop()
+
+	return p
+}
+
+// pick returns the node a request is sent to.
+func (p *nodePool) pick(balanced bool) *node {
+	if !balanced {
+		return p.nodes[0]
+	}
+
+	n := uint64(len(p.nodes))
+	start := p.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		nd := p.nodes[(start+i)%n]
+		if !nd.ejected.Load() {
+			return nd
+		}
+	}
+
+	// every node is ejected, the primary is the best bet
+	return p.nodes[0]
+}
+
+// observe ejects the node when the attempt failed because of it and reports whether it did.
+func (p *nodePool) observe(nd *node, resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+	} else if resp.StatusCode < http.StatusInternalServerError {
+		return false
+	}
+
+	nd.ejected.Store(true)
+	return true
+}
+
+// hasHealthy reports whether at least one node is not ejected.
+func (p *nodePool) hasHealthy() bool {
+	for _, nd := range p.nodes {
+		if !nd.ejected.Load() {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *nodePool) probeLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.probeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			for _, nd := range p.nodes {
+				if nd.ejected.Load() && p.probe(nd) {
+					nd.ejected.Store(false)
+				}
+			}
+		}
+	}
+}
+
+// probe calls the Health route of the node.
+func (p *nodePool) probe(nd *node) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), p.probeInterval)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, nd.host+"/health", nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("User-Agent", GetQualifiedVersion())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// close stops the background probes.
+func (p *nodePool) close() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	p.wg.Wait()
+}
+
+// reroute points the request to another node after a failed attempt.
+func (c *client) reroute(request *http.Request, req *internalRequest) {
+	nd := c.nodes.pick(true)
+	if nd == req.node {
+		return
+	}
+
+	u, err := url.Parse(nd.host + req.endpoint)
+	if err != nil {
+		return
+	}
+	u.RawQuery = request.URL.RawQuery
+
+	req.node = nd
+	request.URL = u
+	request.Host = u.Host
+}
@@ -0,0 +1,103 @@
+package meilisearch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testNode struct {
+	*httptest.Server
+	searches atomic.Int32
+	writes   atomic.Int32
+	down     atomic.Bool
+}
+
+func newTestNode(t *testing.T) *testNode {
+	n := &testNode{}
+	n.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/health":
+			_, _ = w.Write([]byte(`{"status":"available"}`))
+		case "/indexes/movies/search":
+			n.searches.Add(1)
+			_, _ = w.Write([]byte(`{"hits":[]}`))
+		default:
+			n.writes.Add(1)
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"taskUid":1}`))
+		}
+	}))
+	t.Cleanup(n.Close)
+	return n
+}
+
+func TestNodes_ReadBalancingAndWritePinning(t *testing.T) {
+	primary, replica := newTestNode(t), newTestNode(t)
+
+	sv := New(primary.URL, WithHosts(replica.URL, primary.URL))
+	defer sv.Close()
+	require.Len(t, sv.(*meilisearch).client.nodes.nodes, 2)
+
+	for i := 0; i < 10; i++ {
+		_, err := sv.Index("movies").Search("", &SearchRequest{})
+		require.NoError(t, err)
+		_, err = sv.Index("movies").AddDocuments([]map[string]interface{}{{"id": i}}, nil)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, int32(5), primary.searches.Load())
+	require.Equal(t, int32(5), replica.searches.Load())
+	require.Equal(t, int32(10), primary.writes.Load())
+	require.Equal(t, int32(0), replica.writes.Load())
+}
+
+func TestNodes_EjectionAndProbe(t *testing.T) {
+	primary, replica := newTestNode(t), newTestNode(t)
+	replica.down.Store(true)
+
+	sv := New(primary.URL, WithHosts(replica.URL), WithHealthProbeInterval(10*time.Millisecond))
+	defer sv.Close()
+	nodes := sv.(*meilisearch).client.nodes
+
+	// the failed read is replayed on the primary
+	for i := 0; i < 4; i++ {
+		_, err := sv.Index("movies").Search("", &SearchRequest{})
+		require.NoError(t, err)
+	}
+	require.True(t, nodes.nodes[1].ejected.Load())
+	require.Equal(t, int32(4), primary.searches.Load())
+
+	replica.down.Store(false)
+	require.Eventually(t, func() bool {
+		return !nodes.nodes[1].ejected.Load()
+	}, time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		_, err := sv.Index("movies").Search("", &SearchRequest{})
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(2), replica.searches.Load())
+}
+
+func TestNodes_Pick(t *testing.T) {
+	p := &nodePool{nodes: []*node{{host: "a"}, {host: "b"}}}
+	require.Equal(t, "a", p.pick(false).host)
+
+	p.nodes[0].ejected.Store(true)
+	require.Equal(t, "a", p.pick(false).host, "writes stay on the primary")
+	require.Equal(t, "b", p.pick(true).host)
+	require.Equal(t, "b", p.pick(true).host)
+
+	p.nodes[1].ejected.Store(true)
+	require.False(t, p.hasHealthy())
+	require.Equal(t, "a", p.pick(true).host)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
@@ -18,8 +19,23 @@ type meiliOpt struct {
 	retryOnStatus   map[int]bool
 	disableRetry    bool
 	maxRetries      uint8
+	retryPolicy     *RetryPolicy
+	hosts           []string
+	probeInterval   time.Duration
+	circuitBreaker  *CircuitBreakerConfig
+	rateLimits      *RateLimits
+	hedging         *HedgingConfig
+	apiKeyProvider  APIKeyProvider
 	jsonMarshaler   JSONMarshal
 	jsonUnmarshaler JSONUnmarshal
+	middlewares     []Middleware
+	tracer          Tracer
+	metrics         MetricsRecorder
+	logger          *slog.Logger
+	logLevels       *LogLevels
+	logBodyLimit    int
+	requestDump     bool
+	compression     *CompressionThresholds
 }
 
 type encodingOpt struct {
@@ -129,6 +145,16 @@ func WithAPIKey(key string) Option {
 	}
 }
 
+// WithAPIKeyProvider makes the client ask the provider for the API key of every request
+// instead of using a static key, so keys can be rotated without recreating the client.
+// It takes precedence over WithAPIKey. When Meilisearch rejects the key with invalid_api_key,
+// the provider is asked for a fresh key and the call is retried once, see APIKeyProvider.
+func WithAPIKeyProvider(provider APIKeyProvider) Option {
+	return func(opt *meiliOpt) {
+		opt.apiKeyProvider = provider
+	}
+}
+
 // WithContentEncoding support the Content-Encoding header indicates the media type is compressed by a given algorithm.
 // compression improves transfer speed and reduces bandwidth consumption by sending and receiving smaller payloads.
 // the Accept-Encoding header, instead, indicates the compression algorithm the client understands.
@@ -159,6 +185,71 @@ func WithCustomRetries(retryOnStatus []int, maxRetries uint8) Option {
 	}
 }
 
+// WithRetryPolicy replaces the retry configuration of the client with the given policy,
+// it takes precedence over WithCustomRetries. Start from DefaultRetryPolicy:
+//
+//	policy := meilisearch.DefaultRetryPolicy()
+//	policy.MaxElapsedTime = 30 * time.Second
+//	client := meilisearch.New(host, meilisearch.WithRetryPolicy(policy))
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(opt *meiliOpt) {
+		opt.retryPolicy = &policy
+	}
+}
+
+// WithHosts adds replica hosts to the client, the host given to New being the primary.
+// Writes are always sent to the primary while Search, MultiSearch, FacetSearch,
+// SearchSimilarDocuments, GetDocuments, GetDocument and GetSettings are spread round-robin
+// across the healthy nodes. A node failing with a CommunicationError or a 5xx is ejected,
+// the failed read is replayed on another node, and a background Health probe brings it
+// back once it answers again. Call Close to stop the probes.
+func WithHosts(hosts ...string) Option {
+	return func(opt *meiliOpt) {
+		opt.hosts = append(opt.hosts, hosts...)
+	}
+}
+
+// WithHealthProbeInterval sets how often ejected nodes are probed, default is 5 seconds.
+// It only applies along with WithHosts.
+func WithHealthProbeInterval(interval time.Duration) Option {
+	return func(opt *meiliOpt) {
+		opt.probeInterval = interval
+	}
+}
+
+// WithCircuitBreaker guards every host with a circuit breaker. After FailureThreshold
+// consecutive communication errors, timeouts or 5xx responses the breaker opens and calls
+// fail fast with the CircuitBreakerOpen ErrCode, without reaching the network. Once the
+// cooldown is over a single trial request is let through, closing the breaker on success
+// and reopening it on failure. 4xx API errors never count as failures.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) Option {
+	return func(opt *meiliOpt) {
+		opt.circuitBreaker = &cfg
+	}
+}
+
+// WithRateLimits limits the calls of every operation class on the client side, with a token
+// bucket rate and a maximum number of calls in flight. Calls wait for capacity as long as their
+// context allows it, and fail with a TimeoutError otherwise. The rate of a class is halved every
+// time Meilisearch answers 429 Too Many Requests and grows back gradually once it does not.
+func WithRateLimits(limits RateLimits) Option {
+	return func(opt *meiliOpt) {
+		opt.rateLimits = &limits
+	}
+}
+
+// WithHedging enables hedged requests for Search and MultiSearch: when an attempt has not
+// answered after the configured delay, an identical one is sent, and the first successful
+// response wins while the others are canceled. Along with WithHosts the attempts are spread
+// across the nodes. Writes are never hedged. Along with WithRateLimits every attempt counts
+// against the limits of the search class. A single call opts out with CallWithoutHedging, and
+// CallWithHedging hedges a call of a client without WithHedging.
+func WithHedging(cfg HedgingConfig) Option {
+	return func(opt *meiliOpt) {
+		opt.hedging = &cfg
+	}
+}
+
 // DisableRetries disable retry logic in client
 func DisableRetries() Option {
 	return func(opt *meiliOpt) {
@@ -194,6 +285,80 @@ func WithCustomJsonUnmarshaler(unmarshal JSONUnmarshal) Option {
 	}
 }
 
+// WithMiddleware wraps every logical SDK call (one per method call, retries included) with the given
+// middlewares. Middlewares run in the order they are given, the first one being the outermost.
+// Unlike a custom http.RoundTripper, a middleware sees the SDK function name, the request payload,
+// the decoded response and the final *Error.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(opt *meiliOpt) {
+		opt.middlewares = append(opt.middlewares, middlewares...)
+	}
+}
+
+// WithTracer emits a span per SDK call, named after the function (e.g. "meilisearch.Search"),
+// with a child "meilisearch.attempt" span for every HTTP attempt including retries.
+//
+// Spans carry the index uid, the task uid of the returned TaskInfo, the HTTP status code and
+// the Meilisearch error code when available. See the contrib/otelmeilisearch module for an
+// OpenTelemetry adapter.
+func WithTracer(tracer Tracer) Option {
+	return func(opt *meiliOpt) {
+		opt.tracer = tracer
+	}
+}
+
+// WithMetrics reports the measurements of every SDK call to the given recorder: duration,
+// request and response sizes, retries, compression and error codes.
+// Use NewInMemoryMetrics for a ready to use recorder with a Prometheus text exposition.
+func WithMetrics(recorder MetricsRecorder) Option {
+	return func(opt *meiliOpt) {
+		opt.metrics = recorder
+	}
+}
+
+// WithLogger logs every request, response, retry and failed call to the given logger.
+// The Authorization header and the secrets found in bodies (API keys, webhook headers)
+// are redacted, and bodies are truncated, see WithLogBodyLimit.
+func WithLogger(logger *slog.Logger) Option {
+	return func(opt *meiliOpt) {
+		opt.logger = logger
+	}
+}
+
+// WithLogLevels sets the level of the records emitted by the logger given to WithLogger.
+func WithLogLevels(levels LogLevels) Option {
+	return func(opt *meiliOpt) {
+		opt.logLevels = &levels
+	}
+}
+
+// WithLogBodyLimit sets the number of body bytes kept in log records, default is 1024.
+// A negative limit logs bodies entirely.
+func WithLogBodyLimit(limit int) Option {
+	return func(opt *meiliOpt) {
+		opt.logBodyLimit = limit
+	}
+}
+
+// WithCompressionThresholds only compresses the request bodies larger than the threshold of
+// their Content-Type, e.g. DefaultCompressionThresholds. It applies along with WithContentEncoding,
+// responses are still asked in the chosen encoding whatever the size of the request.
+func WithCompressionThresholds(thresholds CompressionThresholds) Option {
+	return func(opt *meiliOpt) {
+		opt.compression = &thresholds
+	}
+}
+
+// WithRequestDump keeps the HTTP request behind every *Error returned by the client, with
+// the last response received for it, so that a failed call can be reproduced from Error.Dump
+// as a curl command or a raw HTTP transcript. The request bodies are kept in memory until
+// the call returns.
+func WithRequestDump() Option {
+	return func(opt *meiliOpt) {
+		opt.requestDump = true
+	}
+}
+
 func baseTransport() *http.Transport {
 	return &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
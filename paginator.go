@@ -0,0 +1,273 @@
+package meilisearch
+
+import (
+	"context"
+	"slices"
+)
+
+// Paginator walks the pages of a list endpoint, following its offset or cursor pagination. It
+// is built by the Paginate functions, e.g. PaginateTasks, the page size being the Limit of the
+// query, and is not safe for concurrent use.
+//
+//	p := meilisearch.PaginateTasks(client, &meilisearch.TasksQuery{Limit: 100})
+//	for p.HasNext() {
+//		tasks, err := p.Next(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+type Paginator[T any] struct {
+	fetch    func(ctx context.Context, cursor int64) (page[T], error)
+	cursor   int64
+	done     bool
+	prefetch bool
+
+	// ahead receives the page fetched in the background, nil when none is in flight
+	ahead chan prefetchedPage[T]
+}
+
+// page is a page of a list endpoint along with the cursor of the page after it, the offset or
+// the from parameter of the next request.
+type page[T any] struct {
+	items []T
+	next  int64
+	last  bool
+}
+
+type prefetchedPage[T any] struct {
+	page page[T]
+	err  error
+}
+
+// cursorZero is the cursor of the page of the tasks or batches lists holding UID 0 only, see
+// cursorPage.
+const cursorZero = -1
+
+func newPaginator[T any](cursor int64, fetch func(ctx context.Context, cursor int64) (page[T], error)) *Paginator[T] {
+	return &Paginator[T]{fetch: fetch, cursor: cursor}
+}
+
+// WithPrefetch makes the paginator fetch the next page in the background as soon as Next
+// returns a page, with the context given to Next. It returns p.
+func (p *Paginator[T]) WithPrefetch() *Paginator[T] {
+	p.prefetch = true
+	return p
+}
+
+// HasNext reports whether there may be another page, Next returns ErrNoMorePages otherwise.
+func (p *Paginator[T]) HasNext() bool {
+	return !p.done
+}
+
+// Next returns the next page, or ErrNoMorePages once the last page was returned. The page may
+// be empty when the list is. A failed call can be retried, the paginator does not move.
+func (p *Paginator[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, ErrNoMorePages
+	}
+
+	var (
+		pg  page[T]
+		err error
+	)
+	if p.ahead != nil {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case res := <-p.ahead:
+			p.ahead = nil
+			pg, err = res.page, res.err
+		}
+		if err != nil {
+			// the prefetch may have failed on the context of the previous call only
+			pg, err = p.fetch(ctx, p.cursor)
+		}
+	} else {
+		pg, err = p.fetch(ctx, p.cursor)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.cursor, p.done = pg.next, pg.last
+	if p.prefetch && !p.done {
+		ahead := make(chan prefetchedPage[T], 1)
+		p.ahead = ahead
+		go func(cursor int64) {
+			pg, err := p.fetch(ctx, cursor)
+			ahead <- prefetchedPage[T]{page: pg, err: err}
+		}(p.cursor)
+	}
+	return pg.items, nil
+}
+
+// All returns the items of all the remaining pages, along with the items fetched before an
+// error if any.
+func (p *Paginator[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for p.HasNext() {
+		items, err := p.Next(ctx)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// offsetPage returns the page of an offset paginated endpoint, the last one once total is reached.
+func offsetPage[T any](items []T, offset, total int64) page[T] {
+	next := offset + int64(len(items))
+	return page[T]{items: items, next: next, last: len(items) == 0 || next >= total}
+}
+
+// cursorPage returns the page of the tasks or batches lists, which are paginated by UID. Their
+// last page has a null next, read as 0 by the int64 Next of the results, while 0 is also the UID
+// of the oldest task or batch. When a descending list may still hold UID 0 after a full page,
+// the next page is cursorZero, fetched by UID.
+func cursorPage[T any](items []T, uid func(T) int64, next, limit int64, reverse bool, uids []int64) page[T] {
+	if next != 0 {
+		return page[T]{items: items, next: next}
+	}
+	if reverse || int64(len(items)) < limit || len(items) == 0 || uid(items[len(items)-1]) == 0 ||
+		len(uids) > 0 && !slices.Contains(uids, 0) {
+		return page[T]{items: items, last: true}
+	}
+	return page[T]{items: items, next: cursorZero}
+}
+
+// PaginateTasks walks the tasks matching query, from its From cursor, see TaskReader.GetTasks.
+func PaginateTasks(r TaskReader, query *TasksQuery) *Paginator[Task] {
+	var q TasksQuery
+	if query != nil {
+		q = *query
+	}
+	return newPaginator(q.From, func(ctx context.Context, from int64) (page[Task], error) {
+		param := q
+		param.From = from
+		if from == cursorZero {
+			param.From, param.UIDS = 0, []int64{0}
+		}
+		res, err := r.GetTasksWithContext(ctx, &param)
+		if err != nil {
+			return page[Task]{}, err
+		}
+		if from == cursorZero {
+			return page[Task]{items: res.Results, last: true}, nil
+		}
+		return cursorPage(res.Results, func(t Task) int64 { return t.UID }, res.Next, res.Limit, q.Reverse, q.UIDS), nil
+	})
+}
+
+// PaginateBatches walks the batches matching query, from its From cursor, see
+// ServiceReader.GetBatches.
+func PaginateBatches(r ServiceReader, query *BatchesQuery) *Paginator[*Batch] {
+	var q BatchesQuery
+	if query != nil {
+		q = *query
+	}
+	return newPaginator(q.From, func(ctx context.Context, from int64) (page[*Batch], error) {
+		param := q
+		param.From = from
+		if from == cursorZero {
+			param.From, param.BatchUIDs = 0, []int64{0}
+		}
+		res, err := r.GetBatchesWithContext(ctx, &param)
+		if err != nil {
+			return page[*Batch]{}, err
+		}
+		if from == cursorZero {
+			return page[*Batch]{items: res.Results, last: true}, nil
+		}
+		return cursorPage(res.Results, func(b *Batch) int64 { return int64(b.UID) }, res.Next, res.Limit, q.Reverse, q.BatchUIDs), nil
+	})
+}
+
+// PaginateIndexes walks the indexes from the Offset of query, see ServiceReader.ListIndexes.
+func PaginateIndexes(r ServiceReader, query *IndexesQuery) *Paginator[*IndexResult] {
+	var q IndexesQuery
+	if query != nil {
+		q = *query
+	}
+	return newPaginator(q.Offset, func(ctx context.Context, offset int64) (page[*IndexResult], error) {
+		param := q
+		param.Offset = offset
+		res, err := r.ListIndexesWithContext(ctx, &param)
+		if err != nil {
+			return page[*IndexResult]{}, err
+		}
+		return offsetPage(res.Results, offset, res.Total), nil
+	})
+}
+
+// PaginateKeys walks the API keys from the Offset of query, see KeyReader.GetKeys.
+func PaginateKeys(r KeyReader, query *KeysQuery) *Paginator[Key] {
+	var q KeysQuery
+	if query != nil {
+		q = *query
+	}
+	return newPaginator(q.Offset, func(ctx context.Context, offset int64) (page[Key], error) {
+		param := q
+		param.Offset = offset
+		res, err := r.GetKeysWithContext(ctx, &param)
+		if err != nil {
+			return page[Key]{}, err
+		}
+		return offsetPage(res.Results, offset, res.Total), nil
+	})
+}
+
+// PaginateChatWorkspaces walks the chat workspaces from the Offset of query, see
+// ChatReader.ListChatWorkspaces.
+func PaginateChatWorkspaces(r ChatReader, query *ListChatWorkSpaceQuery) *Paginator[*ChatWorkspace] {
+	var q ListChatWorkSpaceQuery
+	if query != nil {
+		q = *query
+	}
+	return newPaginator(q.Offset, func(ctx context.Context, offset int64) (page[*ChatWorkspace], error) {
+		param := q
+		param.Offset = offset
+		res, err := r.ListChatWorkspacesWithContext(ctx, &param)
+		if err != nil {
+			return page[*ChatWorkspace]{}, err
+		}
+		return offsetPage(res.Results, offset, res.Total), nil
+	})
+}
+
+// PaginateSearchRules walks the search rules matching params from its Offset, see
+// SearchRulesReader.ListSearchRules.
+func PaginateSearchRules(r SearchRulesReader, params *SearchRulesParams) *Paginator[SearchRule] {
+	var q SearchRulesParams
+	if params != nil {
+		q = *params
+	}
+	return newPaginator(q.Offset, func(ctx context.Context, offset int64) (page[SearchRule], error) {
+		param := q
+		param.Offset = offset
+		res, err := r.ListSearchRulesWithContext(ctx, &param)
+		if err != nil {
+			return page[SearchRule]{}, err
+		}
+		return offsetPage(res.Results, offset, res.Total), nil
+	})
+}
+
+// PaginateDocuments walks the documents of an index matching query from its Offset, see
+// DocumentReader.GetDocuments. Hit.DecodeInto decodes a document.
+func PaginateDocuments(r DocumentReader, query *DocumentsQuery) *Paginator[Hit] {
+	var q DocumentsQuery
+	if query != nil {
+		q = *query
+	}
+	return newPaginator(q.Offset, func(ctx context.Context, offset int64) (page[Hit], error) {
+		param := q
+		param.Offset = offset
+		var res DocumentsResult
+		if err := r.GetDocumentsWithContext(ctx, &param, &res); err != nil {
+			return page[Hit]{}, err
+		}
+		return offsetPage([]Hit(res.Results), offset, res.Total), nil
+	})
+}
@@ -0,0 +1,35 @@
+//go:build go1.23
+
+package meilisearch
+
+import (
+	"context"
+	"iter"
+)
+
+// Iter returns an iterator over the items of the remaining pages, fetched as the loop goes. An
+// error stops the iteration, it is yielded along with the zero T.
+//
+//	for task, err := range meilisearch.PaginateTasks(client, nil).Iter(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+func (p *Paginator[T]) Iter(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for p.HasNext() {
+			items, err := p.Next(ctx)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package meilisearch
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginator_Iter(t *testing.T) {
+	ts, requests := listServer(t, []int64{5, 4, 3, 2, 1})
+
+	var uids []int64
+	for task, err := range PaginateTasks(New(ts.URL), &TasksQuery{Limit: 2}).Iter(context.Background()) {
+		require.NoError(t, err)
+		uids = append(uids, task.UID)
+	}
+	require.Equal(t, []int64{5, 4, 3, 2, 1}, uids)
+
+	atomic.StoreInt32(requests, 0)
+	uids = nil
+	for task, err := range PaginateTasks(New(ts.URL), &TasksQuery{Limit: 2}).Iter(context.Background()) {
+		require.NoError(t, err)
+		uids = append(uids, task.UID)
+		if len(uids) == 3 {
+			break
+		}
+	}
+	require.Equal(t, []int64{5, 4, 3}, uids)
+	require.Equal(t, int32(2), atomic.LoadInt32(requests), "pages are fetched as the loop goes")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var errs []error
+	for task, err := range PaginateTasks(New(ts.URL), nil).Iter(ctx) {
+		require.Zero(t, task)
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], context.Canceled)
+}
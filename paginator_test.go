@@ -0,0 +1,179 @@
+package meilisearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// listServer serves the tasks with the given UIDs, newest first and paginated by cursor, and
+// as many indexes and documents, paginated by offset. It counts the requests.
+func listServer(t *testing.T, taskUIDs []int64) (*httptest.Server, *int32) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		query := r.URL.Query()
+		limit := 20
+		if query.Get("limit") != "" {
+			limit, _ = strconv.Atoi(query.Get("limit"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/tasks":
+			var results []string
+			var next interface{}
+			for _, uid := range taskUIDs {
+				if from, err := strconv.ParseInt(query.Get("from"), 10, 64); err == nil && uid > from {
+					continue
+				}
+				if query.Get("uids") != "" && query.Get("uids") != strconv.FormatInt(uid, 10) {
+					continue
+				}
+				if len(results) == limit {
+					next = uid
+					break
+				}
+				results = append(results, `{"uid":`+strconv.FormatInt(uid, 10)+`,"status":"succeeded"}`)
+			}
+			body, _ := json.Marshal(next)
+			_, _ = w.Write([]byte(`{"results":[` + strings.Join(results, ",") + `],"limit":` + strconv.Itoa(limit) + `,"next":` + string(body) + `}`))
+		case "/indexes":
+			offset, _ := strconv.Atoi(query.Get("offset"))
+			var results []string
+			for i := offset; i < len(taskUIDs) && i < offset+limit; i++ {
+				results = append(results, `{"uid":"index-`+strconv.Itoa(i)+`"}`)
+			}
+			_, _ = w.Write([]byte(`{"results":[` + strings.Join(results, ",") + `],"total":` + strconv.Itoa(len(taskUIDs)) + `}`))
+		case "/indexes/movies/documents/fetch":
+			var param DocumentsQuery
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&param))
+			require.Equal(t, []string{"id"}, param.Fields)
+			var results []string
+			for i := param.Offset; i < int64(len(taskUIDs)) && i < param.Offset+param.Limit; i++ {
+				results = append(results, `{"id":`+strconv.FormatInt(i, 10)+`}`)
+			}
+			_, _ = w.Write([]byte(`{"results":[` + strings.Join(results, ",") + `],"total":` + strconv.Itoa(len(taskUIDs)) + `}`))
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &requests
+}
+
+func taskUIDsOf(tasks []Task) []int64 {
+	uids := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		uids = append(uids, task.UID)
+	}
+	return uids
+}
+
+func TestPaginateTasks(t *testing.T) {
+	ctx := context.Background()
+
+	ts, requests := listServer(t, []int64{4, 3, 2, 1, 0})
+	p := PaginateTasks(New(ts.URL), &TasksQuery{Limit: 2})
+	tasks, err := p.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{4, 3}, taskUIDsOf(tasks))
+	require.True(t, p.HasNext())
+
+	tasks, err = p.All(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{2, 1, 0}, taskUIDsOf(tasks), "a next of 0 is followed")
+	require.False(t, p.HasNext())
+	_, err = p.Next(ctx)
+	require.ErrorIs(t, err, ErrNoMorePages)
+	require.Equal(t, int32(3), atomic.LoadInt32(requests))
+
+	ts, requests = listServer(t, []int64{4, 3, 2, 1})
+	tasks, err = PaginateTasks(New(ts.URL), &TasksQuery{Limit: 2}).All(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{4, 3, 2, 1}, taskUIDsOf(tasks))
+	require.Equal(t, int32(3), atomic.LoadInt32(requests), "a null next after a full page checks for UID 0")
+
+	tasks, err = PaginateTasks(New(ts.URL), &TasksQuery{Limit: 3, From: 3}).All(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 2, 1}, taskUIDsOf(tasks))
+	require.Equal(t, int32(5), atomic.LoadInt32(requests))
+}
+
+func TestPaginateIndexes(t *testing.T) {
+	ts, requests := listServer(t, make([]int64, 5))
+	p := PaginateIndexes(New(ts.URL), &IndexesQuery{Limit: 2})
+
+	var uids []string
+	for p.HasNext() {
+		indexes, err := p.Next(context.Background())
+		require.NoError(t, err)
+		for _, idx := range indexes {
+			uids = append(uids, idx.UID)
+		}
+	}
+	require.Equal(t, []string{"index-0", "index-1", "index-2", "index-3", "index-4"}, uids)
+	require.Equal(t, int32(3), atomic.LoadInt32(requests))
+
+	ts, requests = listServer(t, nil)
+	indexes, err := PaginateIndexes(New(ts.URL), nil).All(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, indexes)
+	require.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestPaginateDocuments(t *testing.T) {
+	ts, _ := listServer(t, make([]int64, 3))
+	hits, err := PaginateDocuments(New(ts.URL).Index("movies"), &DocumentsQuery{Limit: 2, Offset: 1, Fields: []string{"id"}}).
+		All(context.Background())
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+
+	var doc struct {
+		ID int `json:"id"`
+	}
+	require.NoError(t, hits[1].DecodeInto(&doc))
+	require.Equal(t, 2, doc.ID)
+}
+
+func TestPaginator_Prefetch(t *testing.T) {
+	ts, requests := listServer(t, []int64{5, 4, 3, 2, 1})
+	p := PaginateTasks(New(ts.URL), &TasksQuery{Limit: 2}).WithPrefetch()
+
+	tasks, err := p.Next(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int64{5, 4}, taskUIDsOf(tasks))
+	require.Eventually(t, func() bool { return atomic.LoadInt32(requests) == 2 }, time.Second, time.Millisecond,
+		"the second page is fetched ahead")
+
+	tasks, err = p.Next(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 2}, taskUIDsOf(tasks))
+	require.Eventually(t, func() bool { return atomic.LoadInt32(requests) == 3 }, time.Second, time.Millisecond)
+
+	tasks, err = p.Next(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, taskUIDsOf(tasks))
+	require.False(t, p.HasNext())
+	require.Equal(t, int32(3), atomic.LoadInt32(requests), "nothing is fetched after the last page")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p = PaginateTasks(New(ts.URL), &TasksQuery{Limit: 2}).WithPrefetch()
+	_, err = p.Next(ctx)
+	require.NoError(t, err)
+	cancel()
+	tasks, err = p.All(context.Background())
+	require.NoError(t, err, "a page prefetched with a context canceled since is fetched again")
+	require.Equal(t, []int64{3, 2, 1}, taskUIDsOf(tasks))
+
+	_, err = PaginateTasks(New(ts.URL), nil).Next(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
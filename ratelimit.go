@@ -0,0 +1,211 @@
+package meilisearch
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// OperationClass groups the SDK functions sharing the same client-side limits.
+type OperationClass int
+
+const (
+	// OperationSearch covers Search, SearchRaw, MultiSearch, FacetSearch and SearchSimilarDocuments.
+	OperationSearch OperationClass = iota
+	// OperationDocumentWrite covers the calls adding, updating or deleting documents.
+	OperationDocumentWrite
+	// OperationAdmin covers every other call.
+	OperationAdmin
+)
+
+func (o OperationClass) String() string {
+	switch o {
+	case OperationSearch:
+		return "search"
+	case OperationDocumentWrite:
+		return "document_write"
+	default:
+		return "admin"
+	}
+}
+
+var operationClasses = map[string]OperationClass{
+	"Search":                    OperationSearch,
+	"SearchRaw":                 OperationSearch,
+	"MultiSearch":               OperationSearch,
+	"FacetSearch":               OperationSearch,
+	"SearchSimilarDocuments":    OperationSearch,
+	"AddDocuments":              OperationDocumentWrite,
+	"UpdateDocuments":           OperationDocumentWrite,
+	"UpdateDocumentsByFunction": OperationDocumentWrite,
+	"DeleteDocument":            OperationDocumentWrite,
+	"DeleteDocuments":           OperationDocumentWrite,
+	"DeleteDocumentsByFilter":   OperationDocumentWrite,
+	"DeleteAllDocuments":        OperationDocumentWrite,
+}
+
+func operationClassOf(function string) OperationClass {
+	if class, ok := operationClasses[function]; ok {
+		return class
+	}
+	return OperationAdmin
+}
+
+// Limit is the client-side limit of an operation class, zero values mean no limit.
+type Limit struct {
+	// Rate is the number of calls per second allowed by the token bucket.
+	Rate float64
+	// Burst is the size of the token bucket, default is Rate rounded up.
+	Burst int
+	// MaxInFlight is the maximum number of concurrent calls.
+	MaxInFlight int
+}
+
+// RateLimits holds the limits of every operation class, see WithRateLimits.
+type RateLimits struct {
+	Search         Limit
+	DocumentWrites Limit
+	Admin          Limit
+}
+
+// minRateFactor is the lowest fraction of the configured rate the limiter slows down to on 429.
+const minRateFactor = 0.1
+
+// tokenBucket is a token bucket limiter whose rate adapts to 429 responses: the rate is halved
+// on every throttled call and grows back by a tenth of the configured rate on every call that
+// was not throttled (AIMD).
+type tokenBucket struct {
+	mu      sync.Mutex
+	maxRate float64
+	rate    float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	now     func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	b := &tokenBucket{
+		maxRate: rate,
+		rate:    rate,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		now:     time.Now,
+	}
+	b.last = b.now()
+	return b
+}
+
+// wait takes a token, waiting for one to be available or for the context to be done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := b.now()
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// adapt slows the bucket down when the call was throttled and speeds it back up otherwise.
+func (b *tokenBucket) adapt(throttled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if throttled {
+		b.rate = math.Max(b.rate/2, b.maxRate*minRateFactor)
+		return
+	}
+	b.rate = math.Min(b.rate+b.maxRate*minRateFactor, b.maxRate)
+}
+
+func (b *tokenBucket) currentRate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rate
+}
+
+// classLimiter enforces the Limit of a single operation class.
+type classLimiter struct {
+	bucket   *tokenBucket
+	inFlight chan struct{}
+}
+
+func newClassLimiter(limit Limit) *classLimiter {
+	l := &classLimiter{}
+	if limit.Rate > 0 {
+		l.bucket = newTokenBucket(limit.Rate, limit.Burst)
+	}
+	if limit.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// acquire waits for an in-flight slot then for a token, the returned func releases the slot.
+func (l *classLimiter) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+			release = func() { <-l.inFlight }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if l.bucket != nil {
+		if err := l.bucket.wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+func newClassLimiters(limits RateLimits) map[OperationClass]*classLimiter {
+	return map[OperationClass]*classLimiter{
+		OperationSearch:        newClassLimiter(limits.Search),
+		OperationDocumentWrite: newClassLimiter(limits.DocumentWrites),
+		OperationAdmin:         newClassLimiter(limits.Admin),
+	}
+}
+
+// rateLimitMiddleware holds every call until its operation class has capacity.
+func rateLimitMiddleware(limiters map[OperationClass]*classLimiter) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, info *RequestInfo) error {
+			limiter := limiters[operationClassOf(info.Function)]
+
+			release, err := limiter.acquire(ctx)
+			if err != nil {
+				return newCallError(info.Function, info.Method, info.Endpoint).WithErrCode(TimeoutError, err)
+			}
+			defer release()
+
+			err = next(ctx, info)
+			if limiter.bucket != nil {
+				limiter.bucket.adapt(info.req.stats.throttled)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package meilisearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit_OperationClass(t *testing.T) {
+	require.Equal(t, OperationSearch, operationClassOf("MultiSearch"))
+	require.Equal(t, OperationDocumentWrite, operationClassOf("AddDocuments"))
+	require.Equal(t, OperationAdmin, operationClassOf("GetDocuments"))
+	require.Equal(t, OperationAdmin, operationClassOf("CreateIndex"))
+	require.Equal(t, "document_write", OperationDocumentWrite.String())
+}
+
+func TestRateLimit_TokenBucket(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"hits":[]}`))
+	}))
+	defer ts.Close()
+
+	sv := New(ts.URL, WithRateLimits(RateLimits{
+		Search: Limit{Rate: 20, Burst: 1},
+	}))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := sv.Index("movies").Search("", &SearchRequest{})
+		require.NoError(t, err)
+	}
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	// admin calls are not limited
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		_, _ = sv.Version()
+	}
+	require.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestRateLimit_MaxInFlightHonoursContext(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"taskUid":1}`))
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	sv := New(ts.URL, WithRateLimits(RateLimits{
+		DocumentWrites: Limit{MaxInFlight: 1},
+	}))
+
+	go func() {
+		_, _ = sv.Index("movies").AddDocuments([]map[string]interface{}{{"id": 1}}, nil)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := sv.Index("movies").DeleteDocumentWithContext(ctx, "1", nil)
+
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, TimeoutError, e.ErrCode)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRateLimit_AdaptsOnTooManyRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"Too many search requests","code":"too_many_search_requests","type":"system","link":""}`))
+	}))
+	defer ts.Close()
+
+	b := newTokenBucket(100, 10)
+	b.adapt(true)
+	require.Equal(t, float64(50), b.currentRate())
+	for i := 0; i < 10; i++ {
+		b.adapt(true)
+	}
+	require.Equal(t, float64(10), b.currentRate())
+	b.adapt(false)
+	require.Equal(t, float64(20), b.currentRate())
+
+	sv := New(ts.URL, DisableRetries(), WithRateLimits(RateLimits{Search: Limit{Rate: 100, Burst: 10}}))
+	_, err := sv.Index("movies").Search("", &SearchRequest{})
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, APIErrCodeTooManySearchRequests, e.APIError.Code)
+	require.Equal(t, float64(50), sv.(*meilisearch).client.limiters[OperationSearch].bucket.currentRate())
+}
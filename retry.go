@@ -0,0 +1,184 @@
+package meilisearch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how the client retries failed attempts, see WithRetryPolicy.
+// Start from DefaultRetryPolicy and override the fields you need, zero durations and
+// multiplier fall back to the defaults.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries, the first attempt is not counted.
+	MaxRetries uint8
+	// InitialBackoff is the backoff before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the backoff between two attempts, including the wait asked by a
+	// Retry-After header.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every retry.
+	Multiplier float64
+	// Jitter waits a random duration between zero and the computed backoff (full jitter),
+	// which spreads the retries of concurrent clients.
+	Jitter bool
+	// MaxElapsedTime stops retrying when the next attempt would start after the call has
+	// lasted that long. Zero means no limit.
+	MaxElapsedTime time.Duration
+	// RetryOnStatus lists the HTTP status codes to retry.
+	RetryOnStatus []int
+	// RespectRetryAfter waits for the duration of the Retry-After response header instead
+	// of the computed backoff when the server sends one, up to MaxBackoff.
+	RespectRetryAfter bool
+	// RetryNetworkErrors retries idempotent requests (GET, HEAD, OPTIONS, PUT and DELETE)
+	// failing on a transient transport error such as a connection reset or refused.
+	RetryNetworkErrors bool
+	// OnAttempt is called after every attempt, retried or not. It runs synchronously
+	// on the calling goroutine.
+	OnAttempt func(attempt RetryAttempt)
+}
+
+// RetryAttempt describes a single HTTP attempt, it is reported to RetryPolicy.OnAttempt.
+type RetryAttempt struct {
+	// Attempt is the number of the attempt, starting at 0 for the first one.
+	Attempt uint8
+	// Method is the HTTP verb of the request.
+	Method string
+	// URL of the request.
+	URL string
+	// StatusCode of the response, zero when the attempt failed at the transport level.
+	StatusCode int
+	// Err is the transport error of the attempt, nil when a response was received.
+	Err error
+	// Retry reports whether the client is going to retry after this attempt.
+	Retry bool
+	// Backoff is the duration waited before the next attempt, zero when Retry is false.
+	Backoff time.Duration
+	// Elapsed is the time spent since the first attempt.
+	Elapsed time.Duration
+}
+
+const (
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+	defaultMultiplier     = 2
+)
+
+// DefaultRetryPolicy returns the policy used as a base by WithRetryPolicy: 3 retries on
+// 429, 502, 503 and 504 with an exponential backoff from 100ms to 10s and full jitter,
+// honoring Retry-After and retrying idempotent requests on transient network errors.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:         3,
+		InitialBackoff:     defaultInitialBackoff,
+		MaxBackoff:         defaultMaxBackoff,
+		Multiplier:         defaultMultiplier,
+		Jitter:             true,
+		RetryOnStatus:      []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		RespectRetryAfter:  true,
+		RetryNetworkErrors: true,
+	}
+}
+
+// maxBackoff returns the MaxBackoff of the policy, or its default.
+func (p RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return defaultMaxBackoff
+	}
+	return p.MaxBackoff
+}
+
+// backoff returns the exponential backoff function of the policy.
+func (p RetryPolicy) backoff() func(attempt uint8) time.Duration {
+	initial, maxBackoff, multiplier := p.InitialBackoff, p.maxBackoff(), p.Multiplier
+	if initial <= 0 {
+		initial = defaultInitialBackoff
+	}
+	if multiplier < 1 {
+		multiplier = defaultMultiplier
+	}
+	jitter := p.Jitter
+
+	return func(attempt uint8) time.Duration {
+		d := float64(initial) * math.Pow(multiplier, float64(attempt)-1)
+		backoff := maxBackoff
+		if d < float64(maxBackoff) {
+			backoff = time.Duration(d)
+		}
+		if jitter && backoff > 0 {
+			backoff = time.Duration(rand.Int63n(int64(backoff) + 1))
+		}
+		return backoff
+	}
+}
+
+// parseRetryAfter reads the Retry-After header, given either in seconds or as an HTTP date.
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		d := time.Until(date)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransientNetworkError reports whether a transport error is worth retrying.
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// statusError drains the body of a response that is going to be retried and turns it into
+// the error wrapped by MaxRetriesExceeded when no retry is left.
+func statusError(resp *http.Response, internalError *Error) error {
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	internalError.dump.captureResponse(resp, body)
+
+	if len(body) > 0 {
+		internalError.ErrorBody(body)
+	}
+	if internalError.APIError.Code != "" {
+		return fmt.Errorf("status code %d: %s: %s", resp.StatusCode, internalError.APIError.Code, internalError.APIError.Message)
+	}
+	return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+}
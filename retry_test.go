@@ -0,0 +1,251 @@
+package meilisearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+	backoff := policy.backoff()
+	require.Equal(t, 100*time.Millisecond, backoff(1))
+	require.Equal(t, 200*time.Millisecond, backoff(2))
+	require.Equal(t, 400*time.Millisecond, backoff(3))
+	require.Equal(t, time.Second, backoff(5))
+	require.Equal(t, time.Second, backoff(255))
+
+	policy.Jitter = true
+	backoff = policy.backoff()
+	for i := 0; i < 100; i++ {
+		d := backoff(3)
+		require.GreaterOrEqual(t, d, time.Duration(0))
+		require.LessOrEqual(t, d, 400*time.Millisecond)
+	}
+
+	// zero values fall back to the defaults
+	require.Equal(t, defaultInitialBackoff, RetryPolicy{}.backoff()(1))
+}
+
+func TestRetryPolicy_ParseRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	_, ok := parseRetryAfter(resp)
+	require.False(t, ok)
+
+	resp.Header.Set("Retry-After", "3")
+	d, ok := parseRetryAfter(resp)
+	require.True(t, ok)
+	require.Equal(t, 3*time.Second, d)
+
+	resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	d, ok = parseRetryAfter(resp)
+	require.True(t, ok)
+	require.Greater(t, d, 59*time.Minute)
+
+	resp.Header.Set("Retry-After", "soon")
+	_, ok = parseRetryAfter(resp)
+	require.False(t, ok)
+}
+
+func TestRetryPolicy_TooManyRequests(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message":"Too many search requests","code":"too_many_search_requests","type":"system","link":""}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"hits":[]}`))
+	}))
+	defer ts.Close()
+
+	var reported []RetryAttempt
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Hour // Retry-After must win
+	policy.OnAttempt = func(a RetryAttempt) { reported = append(reported, a) }
+
+	sv := New(ts.URL, WithRetryPolicy(policy))
+	_, err := sv.Index("movies").Search("", &SearchRequest{})
+	require.NoError(t, err)
+
+	require.Len(t, reported, 2)
+	require.Equal(t, uint8(0), reported[0].Attempt)
+	require.Equal(t, http.StatusTooManyRequests, reported[0].StatusCode)
+	require.True(t, reported[0].Retry)
+	require.Equal(t, time.Duration(0), reported[0].Backoff)
+	require.Equal(t, uint8(1), reported[1].Attempt)
+	require.Equal(t, http.StatusOK, reported[1].StatusCode)
+	require.False(t, reported[1].Retry)
+}
+
+func TestRetryPolicy_RetryAfterIsCappedByMaxBackoff(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"hits":[]}`))
+	}))
+	defer ts.Close()
+
+	var reported []RetryAttempt
+	policy := DefaultRetryPolicy()
+	policy.MaxBackoff = 10 * time.Millisecond
+	policy.OnAttempt = func(a RetryAttempt) { reported = append(reported, a) }
+
+	sv := New(ts.URL, WithRetryPolicy(policy))
+	_, err := sv.Index("movies").Search("", &SearchRequest{})
+	require.NoError(t, err)
+	require.Len(t, reported, 2)
+	require.Equal(t, 10*time.Millisecond, reported[0].Backoff)
+}
+
+func TestRetryPolicy_SuccessOnLastRetry(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"available"}`))
+	}))
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxRetries = 2
+	policy.InitialBackoff = time.Millisecond
+
+	sv := New(ts.URL, WithRetryPolicy(policy))
+	resp, err := sv.Health()
+	require.NoError(t, err)
+	require.Equal(t, "available", resp.Status)
+	require.Equal(t, 3, attempts)
+}
+
+func TestRetryPolicy_MaxRetriesExceededWrapsLastError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"message":"Meilisearch is restarting","code":"unavailable","type":"system","link":""}`))
+	}))
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxRetries = 1
+	policy.InitialBackoff = time.Millisecond
+
+	sv := New(ts.URL, WithRetryPolicy(policy))
+	_, err := sv.Health()
+	require.Error(t, err)
+
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, MaxRetriesExceeded, e.ErrCode)
+	require.Equal(t, http.StatusServiceUnavailable, e.StatusCode)
+	require.Error(t, e.OriginError)
+	require.Contains(t, e.OriginError.Error(), "Meilisearch is restarting")
+}
+
+func TestRetryPolicy_MaxElapsedTime(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxRetries = 10
+	policy.Jitter = false
+	policy.InitialBackoff = time.Second
+	policy.MaxElapsedTime = 500 * time.Millisecond
+
+	sv := New(ts.URL, WithRetryPolicy(policy))
+	_, err := sv.Health()
+
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, MaxRetriesExceeded, e.ErrCode)
+	require.Equal(t, 1, attempts)
+}
+
+func TestRetryPolicy_NetworkErrors(t *testing.T) {
+	resetErr := &mockRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		return nil, syscall.ECONNRESET
+	}}
+
+	newRetryClient := func(policy RetryPolicy) (*client, *int) {
+		attempts := 0
+		c := newClient(&http.Client{Transport: &mockRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return resetErr.RoundTrip(req)
+		}}}, "http://localhost", "", &clientConfig{
+			retryPolicy:   &policy,
+			jsonMarshal:   json.Marshal,
+			jsonUnmarshal: json.Unmarshal,
+		})
+		c.retryBackoff = func(uint8) time.Duration { return 0 }
+		return c, &attempts
+	}
+
+	policy := DefaultRetryPolicy()
+	c, attempts := newRetryClient(policy)
+	err := c.executeRequest(context.Background(), &internalRequest{
+		endpoint:            "/health",
+		method:              http.MethodGet,
+		acceptedStatusCodes: []int{http.StatusOK},
+	})
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, MaxRetriesExceeded, e.ErrCode)
+	require.True(t, errors.Is(err, syscall.ECONNRESET))
+	require.Equal(t, 4, *attempts)
+
+	// POST is not idempotent
+	c, attempts = newRetryClient(policy)
+	err = c.executeRequest(context.Background(), &internalRequest{
+		endpoint:            "/indexes",
+		method:              http.MethodPost,
+		contentType:         contentTypeJSON,
+		withRequest:         map[string]string{"uid": "movies"},
+		acceptedStatusCodes: []int{http.StatusAccepted},
+	})
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, CommunicationError, e.ErrCode)
+	require.Equal(t, 1, *attempts)
+
+	policy.RetryNetworkErrors = false
+	c, attempts = newRetryClient(policy)
+	err = c.executeRequest(context.Background(), &internalRequest{
+		endpoint:            "/health",
+		method:              http.MethodGet,
+		acceptedStatusCodes: []int{http.StatusOK},
+	})
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, CommunicationError, e.ErrCode)
+	require.Equal(t, 1, *attempts)
+}
+
+func TestRetryPolicy_IsTransientNetworkError(t *testing.T) {
+	require.True(t, isTransientNetworkError(syscall.ECONNREFUSED))
+	require.True(t, isTransientNetworkError(errors.Join(errors.New("read"), syscall.ECONNRESET)))
+	require.False(t, isTransientNetworkError(errors.New("tls: bad certificate")))
+}
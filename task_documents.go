@@ -2,10 +2,14 @@ package meilisearch
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"strconv"
 )
 
+// ndjsonHandler receives the values of an NDJSON response one at a time.
+type ndjsonHandler func(value json.RawMessage) error
+
 func (m *meilisearch) GetTaskDocuments(taskUID int64, dst interface{}) error {
 	return m.GetTaskDocumentsWithContext(context.Background(), taskUID, dst)
 }
@@ -24,3 +28,25 @@ func (m *meilisearch) GetTaskDocumentsWithContext(ctx context.Context, taskUID i
 	}
 	return m.client.executeRequest(ctx, req)
 }
+
+func (m *meilisearch) ForEachTaskDocument(taskUID int64, fn func(document json.RawMessage) error) error {
+	return m.ForEachTaskDocumentWithContext(context.Background(), taskUID, fn)
+}
+
+func (m *meilisearch) ForEachTaskDocumentWithContext(ctx context.Context, taskUID int64, fn func(document json.RawMessage) error) error {
+	if fn == nil {
+		return ErrNoTaskDocumentFunc
+	}
+	req := &internalRequest{
+		endpoint:             "/tasks/" + strconv.FormatInt(taskUID, 10) + "/documents",
+		method:               http.MethodGet,
+		withRequest:          nil,
+		withResponse:         ndjsonHandler(fn),
+		withQueryParams:      nil,
+		withResponseEncoding: true,
+		acceptedStatusCodes:  []int{http.StatusOK},
+		acceptedContentType:  contentTypeNDJSON,
+		functionName:         "GetTaskDocuments",
+	}
+	return m.client.executeRequest(ctx, req)
+}
@@ -1,6 +1,8 @@
 package meilisearch
 
 import (
+	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
@@ -143,6 +145,7 @@ func TestGetTaskDocumentsDecodesResponseContentEncodings(t *testing.T) {
 	}{
 		{name: "deflate", encoding: DeflateEncoding},
 		{name: "brotli", encoding: BrotliEncoding},
+		{name: "zstd", encoding: ZstdEncoding},
 	}
 
 	for _, tt := range tests {
@@ -309,3 +312,80 @@ func TestGetTaskDocumentsUnmarshalError(t *testing.T) {
 	err := client.GetTaskDocuments(42, &docs)
 	require.ErrorContains(t, err, "failed to unmarshal NDJSON response")
 }
+
+func TestForEachTaskDocument(t *testing.T) {
+	client := newTaskDocumentTestClient(func(r *http.Request) (*http.Response, error) {
+		require.Equal(t, GzipEncoding.String(), r.Header.Get("Accept-Encoding"))
+		require.Equal(t, "/tasks/42/documents", r.URL.Path)
+
+		resp := taskDocumentResponse(http.StatusOK, "application/x-ndjson", encodedTaskDocumentBody(t, GzipEncoding, "{\"id\":\"1\",\"name\":\"Alice\"}\n{\"id\":\"2\",\"name\":\"Bob\"}{\"id\":\"3\",\"name\":\"Carol\"}"))
+		resp.Header.Set("Content-Encoding", GzipEncoding.String())
+		return resp, nil
+	}, WithContentEncoding(GzipEncoding, DefaultCompression))
+
+	var docs []taskDocumentTest
+	err := client.ForEachTaskDocument(42, func(document json.RawMessage) error {
+		var doc taskDocumentTest
+		if err := json.Unmarshal(document, &doc); err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []taskDocumentTest{
+		{ID: "1", Name: "Alice"},
+		{ID: "2", Name: "Bob"},
+		{ID: "3", Name: "Carol"},
+	}, docs)
+}
+
+func TestForEachTaskDocumentStopsOnError(t *testing.T) {
+	client := newTaskDocumentTestClient(func(r *http.Request) (*http.Response, error) {
+		return taskDocumentResponse(http.StatusOK, "application/x-ndjson", strings.NewReader("{\"id\":\"1\"}\n{\"id\":\"2\"}\n{\"id\":")), nil
+	})
+
+	errStop := errors.New("stop")
+	calls := 0
+	err := client.ForEachTaskDocument(42, func(document json.RawMessage) error {
+		calls++
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, 1, calls)
+}
+
+func TestForEachTaskDocumentErrors(t *testing.T) {
+	t.Run("nil function", func(t *testing.T) {
+		err := New("http://127.0.0.1:1").ForEachTaskDocument(42, nil)
+		require.ErrorIs(t, err, ErrNoTaskDocumentFunc)
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		client := newTaskDocumentTestClient(func(r *http.Request) (*http.Response, error) {
+			return taskDocumentResponse(http.StatusOK, "application/x-ndjson", strings.NewReader("{\"id\":\"a\"}{\"id\":")), nil
+		})
+
+		calls := 0
+		err := client.ForEachTaskDocument(42, func(document json.RawMessage) error {
+			calls++
+			return nil
+		})
+		require.ErrorContains(t, err, "failed to decode NDJSON")
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		client := newTaskDocumentTestClient(func(r *http.Request) (*http.Response, error) {
+			return taskDocumentResponse(http.StatusNotFound, "application/json", strings.NewReader(`{"message":"task not found","code":"task_not_found","type":"invalid_request","link":""}`)), nil
+		})
+
+		err := client.ForEachTaskDocument(42, func(document json.RawMessage) error {
+			t.Fatal("no document expected")
+			return nil
+		})
+		var meiliErr *Error
+		require.ErrorAs(t, err, &meiliErr)
+		require.Equal(t, APIError, meiliErr.ErrCode)
+	})
+}
@@ -0,0 +1,94 @@
+package meilisearch
+
+import (
+	"context"
+	"sync"
+)
+
+// TaskHandle is a task bound to the client that enqueued it, see ServiceManager.TaskHandle and
+// IndexManager.TaskHandle.
+type TaskHandle interface {
+	// TaskInfo returns the summary of the task the handle was made from.
+	TaskInfo() *TaskInfo
+
+	// Wait waits for the task to complete with the default WaitOptions, bounded by ctx. It
+	// returns the completed task, whatever its status, see Err.
+	Wait(ctx context.Context) (*Task, error)
+
+	// Cancel asks Meilisearch to cancel the task if it is not processed yet, it returns the
+	// cancellation task.
+	//
+	// docs: https://www.meilisearch.com/docs/reference/api/async-task-management/cancel-tasks
+	Cancel(ctx context.Context) (*TaskInfo, error)
+
+	// Documents decodes into dst the documents the task added, updated or deleted, see
+	// ServiceManager.GetTaskDocuments.
+	//
+	// docs: https://www.meilisearch.com/docs/reference/api/async-task-management/get-tasks-documents
+	Documents(ctx context.Context, dst interface{}) error
+
+	// Batch returns the batch the task is processed in, it fails with ErrTaskNotBatched while
+	// the task is still enqueued.
+	//
+	// docs: https://www.meilisearch.com/docs/reference/api/async-task-management/get-batch
+	Batch(ctx context.Context) (*Batch, error)
+
+	// Err returns the error of the task as an *Error once Wait saw it fail or be canceled, with
+	// the TaskFailed or TaskCanceled ErrCode. It returns nil otherwise.
+	Err() error
+}
+
+type taskHandle struct {
+	ms   *meilisearch
+	info *TaskInfo
+
+	mu   sync.Mutex
+	task *Task
+}
+
+func newTaskHandle(cli *client, info *TaskInfo) TaskHandle {
+	return &taskHandle{ms: &meilisearch{client: cli}, info: info}
+}
+
+func (h *taskHandle) TaskInfo() *TaskInfo {
+	return h.info
+}
+
+func (h *taskHandle) Wait(ctx context.Context) (*Task, error) {
+	task, err := h.ms.WaitForTaskWithOptions(ctx, h.info.TaskUID, nil)
+	if err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	h.task = task
+	h.mu.Unlock()
+	return task, nil
+}
+
+func (h *taskHandle) Cancel(ctx context.Context) (*TaskInfo, error) {
+	return h.ms.CancelTasksWithContext(ctx, &CancelTasksQuery{UIDS: []int64{h.info.TaskUID}})
+}
+
+func (h *taskHandle) Documents(ctx context.Context, dst interface{}) error {
+	return h.ms.GetTaskDocumentsWithContext(ctx, h.info.TaskUID, dst)
+}
+
+func (h *taskHandle) Batch(ctx context.Context) (*Batch, error) {
+	task, err := h.ms.GetTaskWithContext(ctx, h.info.TaskUID)
+	if err != nil {
+		return nil, err
+	}
+	if task.BatchUID == nil {
+		return nil, ErrTaskNotBatched
+	}
+	return h.ms.GetBatchWithContext(ctx, int(*task.BatchUID))
+}
+
+func (h *taskHandle) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.task == nil {
+		return nil
+	}
+	return taskFinalError(h.task)
+}
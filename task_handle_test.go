@@ -0,0 +1,71 @@
+package meilisearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskHandle(t *testing.T) {
+	var polls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /tasks/7":
+			w.Header().Set("Content-Type", "application/json")
+			if atomic.AddInt32(&polls, 1) == 1 {
+				_, _ = w.Write([]byte(`{"uid":7,"status":"enqueued"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"uid":7,"batchUid":0,"status":"failed","error":{"message":"Invalid value","code":"invalid_document_id","type":"invalid_request","link":""}}`))
+		case "POST /tasks/cancel":
+			require.Equal(t, "7", r.URL.Query().Get("uids"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"taskUid":8,"status":"enqueued","type":"taskCancelation"}`))
+		case "GET /tasks/7/documents":
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			_, _ = w.Write([]byte("{\"id\":1}\n{\"id\":2}\n"))
+		case "GET /batches/0":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"uid":0,"batchStrategy":"batched all enqueued tasks"}`))
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	info := &TaskInfo{TaskUID: 7, Status: TaskStatusEnqueued}
+	handle := New(ts.URL).Index("movies").TaskHandle(info)
+	ctx := context.Background()
+	require.Same(t, info, handle.TaskInfo())
+
+	_, err := handle.Batch(ctx)
+	require.ErrorIs(t, err, ErrTaskNotBatched)
+	require.NoError(t, handle.Err(), "no error before Wait")
+
+	task, err := handle.Wait(ctx)
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusFailed, task.Status)
+	require.NotNil(t, task.BatchUID)
+	require.ErrorIs(t, handle.Err(), APIErrCodeInvalidDocumentID)
+
+	var meiliErr *Error
+	require.True(t, errors.As(handle.Err(), &meiliErr))
+	require.Equal(t, TaskFailed, meiliErr.ErrCode)
+
+	batch, err := handle.Batch(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "batched all enqueued tasks", batch.BatchStrategy)
+
+	cancelInfo, err := handle.Cancel(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(8), cancelInfo.TaskUID)
+
+	var docs []map[string]int
+	require.NoError(t, handle.Documents(ctx, &docs))
+	require.Equal(t, []map[string]int{{"id": 1}, {"id": 2}}, docs)
+}
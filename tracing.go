@@ -0,0 +1,104 @@
+package meilisearch
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Attribute keys set on the spans started by the client.
+const (
+	AttributeFunction     = "meilisearch.function"
+	AttributeIndexUID     = "meilisearch.index_uid"
+	AttributeTaskUID      = "meilisearch.task_uid"
+	AttributeAPIErrCode   = "meilisearch.error_code"
+	AttributeRetryAttempt = "meilisearch.retry_attempt"
+	AttributeHTTPMethod   = "http.request.method"
+	AttributeHTTPRoute    = "url.path"
+	AttributeHTTPStatus   = "http.response.status_code"
+)
+
+// Attribute is a key/value pair attached to a Span.
+// Value is one of string, int, int64, bool or float64.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Tracer starts spans around SDK calls. It is deliberately small so that any tracing library
+// can be adapted to it without the SDK depending on that library.
+type Tracer interface {
+	// Start creates a span named name as a child of any span found in ctx, and returns a
+	// context carrying the new span.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a single operation started by a Tracer.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// tracingMiddleware starts one span per logical SDK call, the spans of the HTTP attempts made
+// by client.do are started as its children.
+func tracingMiddleware(tracer Tracer) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, info *RequestInfo) error {
+			attrs := []Attribute{
+				{Key: AttributeFunction, Value: info.Function},
+				{Key: AttributeHTTPMethod, Value: info.Method},
+				{Key: AttributeHTTPRoute, Value: info.Endpoint},
+			}
+			if uid := indexUIDFromEndpoint(info.Endpoint); uid != "" {
+				attrs = append(attrs, Attribute{Key: AttributeIndexUID, Value: uid})
+			}
+
+			ctx, span := tracer.Start(ctx, "meilisearch."+info.Function, attrs...)
+			defer span.End()
+
+			err := next(ctx, info)
+
+			if info.StatusCode != 0 {
+				span.SetAttributes(Attribute{Key: AttributeHTTPStatus, Value: info.StatusCode})
+			}
+			if err != nil {
+				var meiliErr *Error
+				if errors.As(err, &meiliErr) && meiliErr.APIError.Code != "" {
+					span.SetAttributes(Attribute{Key: AttributeAPIErrCode, Value: string(meiliErr.APIError.Code)})
+				}
+				span.RecordError(err)
+				return err
+			}
+
+			if taskUID, ok := taskUIDFromResponse(info.Response); ok {
+				span.SetAttributes(Attribute{Key: AttributeTaskUID, Value: taskUID})
+			}
+			return nil
+		}
+	}
+}
+
+// indexUIDFromEndpoint extracts the index uid from endpoints shaped like /indexes/{uid}/...
+func indexUIDFromEndpoint(endpoint string) string {
+	rest, ok := strings.CutPrefix(endpoint, "/indexes/")
+	if !ok {
+		return ""
+	}
+	uid, _, _ := strings.Cut(rest, "/")
+	return uid
+}
+
+func taskUIDFromResponse(resp interface{}) (int64, bool) {
+	switch v := resp.(type) {
+	case *TaskInfo:
+		if v != nil {
+			return v.TaskUID, true
+		}
+	case **TaskInfo:
+		if v != nil && *v != nil {
+			return (*v).TaskUID, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,127 @@
+package meilisearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	attrs  map[string]interface{}
+	errs   []error
+	ended  bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) { s.errs = append(s.errs, err) }
+
+func (s *recordedSpan) End() { s.ended = true }
+
+type spanCtxKey struct{}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	parent, _ := ctx.Value(spanCtxKey{}).(*recordedSpan)
+	span := &recordedSpan{name: name, parent: parent, attrs: map[string]interface{}{}}
+	span.SetAttributes(attrs...)
+	r.spans = append(r.spans, span)
+	return context.WithValue(ctx, spanCtxKey{}, span), span
+}
+
+func TestTracing_SpanPerCallAndAttempt(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"taskUid":42,"indexUid":"movies","status":"enqueued","type":"documentAdditionOrUpdate"}`))
+	}))
+	defer ts.Close()
+
+	tracer := &recordingTracer{}
+	sv := New(ts.URL, WithTracer(tracer))
+	m := sv.(*meilisearch)
+	m.client.retryBackoff = func(uint8) time.Duration { return 0 }
+
+	_, err := sv.Index("movies").AddDocuments([]map[string]interface{}{{"id": 1}}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, tracer.spans, 3)
+	call := tracer.spans[0]
+	require.Equal(t, "meilisearch.AddDocuments", call.name)
+	require.Nil(t, call.parent)
+	require.True(t, call.ended)
+	require.Equal(t, "movies", call.attrs[AttributeIndexUID])
+	require.Equal(t, int64(42), call.attrs[AttributeTaskUID])
+	require.Equal(t, http.StatusAccepted, call.attrs[AttributeHTTPStatus])
+
+	for i, attempt := range tracer.spans[1:] {
+		require.Equal(t, "meilisearch.attempt", attempt.name)
+		require.Same(t, call, attempt.parent)
+		require.Equal(t, i, attempt.attrs[AttributeRetryAttempt])
+		require.True(t, attempt.ended)
+	}
+	require.Equal(t, http.StatusBadGateway, tracer.spans[1].attrs[AttributeHTTPStatus])
+	require.Equal(t, http.StatusAccepted, tracer.spans[2].attrs[AttributeHTTPStatus])
+}
+
+func TestTracing_APIError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Index movies not found.","code":"index_not_found","type":"invalid_request","link":""}`))
+	}))
+	defer ts.Close()
+
+	tracer := &recordingTracer{}
+	sv := New(ts.URL, WithTracer(tracer))
+
+	_, err := sv.Index("movies").FetchInfo()
+	require.Error(t, err)
+
+	call := tracer.spans[0]
+	require.Equal(t, "meilisearch.FetchInfo", call.name)
+	require.Equal(t, string(APIErrCodeIndexNotFound), call.attrs[AttributeAPIErrCode])
+	require.Equal(t, http.StatusNotFound, call.attrs[AttributeHTTPStatus])
+	require.Len(t, call.errs, 1)
+}
+
+func TestTracing_Helpers(t *testing.T) {
+	require.Equal(t, "movies", indexUIDFromEndpoint("/indexes/movies/documents"))
+	require.Equal(t, "movies", indexUIDFromEndpoint("/indexes/movies"))
+	require.Empty(t, indexUIDFromEndpoint("/indexes"))
+	require.Empty(t, indexUIDFromEndpoint("/tasks/1"))
+
+	info := &TaskInfo{TaskUID: 7}
+	uid, ok := taskUIDFromResponse(info)
+	require.True(t, ok)
+	require.Equal(t, int64(7), uid)
+
+	uid, ok = taskUIDFromResponse(&info)
+	require.True(t, ok)
+	require.Equal(t, int64(7), uid)
+
+	_, ok = taskUIDFromResponse(&Task{})
+	require.False(t, ok)
+}
@@ -0,0 +1,126 @@
+package meilisearch
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ReopenableReader is a request body read from a source that can be opened again, such as a
+// file. Bodies read from an io.Reader are streamed to Meilisearch instead of being loaded in
+// memory, which means a failed upload cannot be sent again. Uploads read from a ReopenableReader
+// open a fresh source on every attempt, so they are retried like any other call:
+//
+//	docs := meilisearch.NewReopenableReader(func() (io.ReadCloser, error) {
+//		return os.Open("movies.ndjson")
+//	})
+//	task, err := client.Index("movies").AddDocumentsNdjsonFromReader(docs, nil)
+type ReopenableReader struct {
+	open func() (io.ReadCloser, error)
+	rc   io.ReadCloser
+}
+
+// NewReopenableReader returns a ReopenableReader over the sources returned by open.
+func NewReopenableReader(open func() (io.ReadCloser, error)) *ReopenableReader {
+	return &ReopenableReader{open: open}
+}
+
+// Read reads from the source, it is opened on the first call.
+func (r *ReopenableReader) Read(p []byte) (int, error) {
+	if r.rc == nil {
+		rc, err := r.open()
+		if err != nil {
+			return 0, err
+		}
+		r.rc = rc
+	}
+	return r.rc.Read(p)
+}
+
+// Close closes the source opened by Read, if any.
+func (r *ReopenableReader) Close() error {
+	if r.rc == nil {
+		return nil
+	}
+	err := r.rc.Close()
+	r.rc = nil
+	return err
+}
+
+// streamingBody returns the reader of a request body to stream rather than to buffer. In-memory
+// readers are buffered, their content is already loaded and they can be sent again on retry.
+func streamingBody(v interface{}) (io.Reader, bool) {
+	r, ok := v.(io.Reader)
+	if !ok {
+		return nil, false
+	}
+	if _, inMemory := r.(interface{ Len() int }); inMemory {
+		return nil, false
+	}
+	return r, true
+}
+
+// isOneShotBody reports whether the body of req is streamed from a source that cannot be reopened.
+func isOneShotBody(req *internalRequest) bool {
+	r, ok := streamingBody(req.withRequest)
+	if !ok {
+		return false
+	}
+	_, reopenable := r.(*ReopenableReader)
+	return !reopenable
+}
+
+// isRewindable reports whether the body of the request can be sent again.
+func isRewindable(request *http.Request) bool {
+	return request.Body == nil || request.Body == http.NoBody || request.GetBody != nil
+}
+
+// streamBody returns the body sent for r, compressed on the fly when the client has a content
+// encoding. It is closed by the transport once sent, which closes r too.
+func (c *client) streamBody(req *internalRequest, r io.Reader, internalError *Error) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	if !c.compresses(req.contentType, -1) {
+		if rc, ok := r.(io.ReadCloser); ok {
+			body = rc
+		} else {
+			body = io.NopCloser(r)
+		}
+	} else {
+		compressed, err := c.encoder.EncodeStream(r)
+		if err != nil {
+			if rc, ok := r.(io.Closer); ok {
+				_ = rc.Close()
+			}
+			return nil, internalError.WithErrCode(ErrCodeMarshalRequest,
+				fmt.Errorf("failed to encode request body: %w", err))
+		}
+		body = compressed
+		req.stats.requestEncoding = c.contentEncoding
+	}
+
+	req.stats.requestBytes = 0
+	return &countingReadCloser{ReadCloser: body, n: &req.stats.requestBytes}, nil
+}
+
+// openStream returns the body of a streamed request. A ReopenableReader is opened afresh, so the
+// body can be sent again on retry through GetBody.
+func (c *client) openStream(req *internalRequest, r io.Reader, internalError *Error) (io.ReadCloser, func() (io.ReadCloser, error), error) {
+	reopenable, ok := r.(*ReopenableReader)
+	if !ok {
+		body, err := c.streamBody(req, r, internalError)
+		return body, nil, err
+	}
+
+	getBody := func() (io.ReadCloser, error) {
+		src, err := reopenable.open()
+		if err != nil {
+			return nil, fmt.Errorf("unable to open request body: %w", err)
+		}
+		return c.streamBody(req, src, internalError)
+	}
+	body, err := getBody()
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, getBody, nil
+}
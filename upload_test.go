@@ -0,0 +1,158 @@
+package meilisearch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const uploadDocuments = "{\"id\":1,\"title\":\"Carol\"}\n{\"id\":2,\"title\":\"Wonder Woman\"}\n"
+
+// onlyReader hides the Len method of in-memory readers so the body is streamed.
+type onlyReader struct {
+	io.Reader
+}
+
+func uploadServer(t *testing.T, statuses ...int) (*httptest.Server, *[]string, *int32) {
+	t.Helper()
+	var calls int32
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == GzipEncoding.String() {
+			gr, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = gr
+		}
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		require.Equal(t, int64(-1), r.ContentLength)
+		require.Equal(t, []string{"chunked"}, r.TransferEncoding)
+		bodies = append(bodies, string(data))
+
+		status := http.StatusAccepted
+		if int(n) <= len(statuses) {
+			status = statuses[n-1]
+		}
+		if r.Header.Get("Accept-Encoding") == GzipEncoding.String() {
+			w.Header().Set("Content-Encoding", GzipEncoding.String())
+			w.WriteHeader(status)
+			gw := gzip.NewWriter(w)
+			_, _ = gw.Write([]byte(`{"taskUid":1}`))
+			_ = gw.Close()
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"taskUid":1}`))
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &bodies, &calls
+}
+
+func TestUpload_Streamed(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "uncompressed"},
+		{name: "gzip", opts: []Option{WithContentEncoding(GzipEncoding, BestSpeed)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, bodies, _ := uploadServer(t)
+			sv := New(ts.URL, tt.opts...)
+
+			task, err := sv.Index("movies").AddDocumentsNdjsonFromReader(onlyReader{strings.NewReader(uploadDocuments)}, nil)
+			require.NoError(t, err)
+			require.Equal(t, int64(1), task.TaskUID)
+			require.Equal(t, []string{uploadDocuments}, *bodies)
+		})
+	}
+}
+
+func TestUpload_OneShotReaderIsNotRetried(t *testing.T) {
+	ts, _, calls := uploadServer(t, http.StatusServiceUnavailable)
+	sv := New(ts.URL, WithRetryPolicy(RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, RetryOnStatus: []int{http.StatusServiceUnavailable}}))
+
+	_, err := sv.Index("movies").AddDocumentsNdjsonFromReader(onlyReader{strings.NewReader(uploadDocuments)}, nil)
+	require.Error(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestUpload_ReopenableReaderIsRetried(t *testing.T) {
+	ts, bodies, calls := uploadServer(t, http.StatusServiceUnavailable)
+	sv := New(ts.URL,
+		WithContentEncoding(GzipEncoding, BestSpeed),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, RetryOnStatus: []int{http.StatusServiceUnavailable}}))
+
+	opened := 0
+	docs := NewReopenableReader(func() (io.ReadCloser, error) {
+		opened++
+		return io.NopCloser(onlyReader{strings.NewReader(uploadDocuments)}), nil
+	})
+
+	_, err := sv.Index("movies").AddDocumentsNdjsonFromReader(docs, nil)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(calls))
+	require.Equal(t, 2, opened)
+	require.Equal(t, []string{uploadDocuments, uploadDocuments}, *bodies)
+}
+
+func TestReopenableReader_Read(t *testing.T) {
+	docs := NewReopenableReader(func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(uploadDocuments)), nil
+	})
+
+	data, err := io.ReadAll(docs)
+	require.NoError(t, err)
+	require.Equal(t, uploadDocuments, string(data))
+	require.NoError(t, docs.Close())
+	require.NoError(t, docs.Close())
+}
+
+func TestEncoder_EncodeStream(t *testing.T) {
+	for _, ce := range []ContentEncoding{GzipEncoding, DeflateEncoding, BrotliEncoding, ZstdEncoding} {
+		t.Run(ce.String(), func(t *testing.T) {
+			enc := newEncoding(ce, DefaultCompression)
+			payload := bytes.Repeat([]byte(`{"id":1,"title":"Carol"},`), 1000)
+			payload = append(append([]byte("["), payload[:len(payload)-1]...), ']')
+
+			// the writer is taken back from its pool between streams
+			for i := 0; i < 2; i++ {
+				rc, err := enc.EncodeStream(bytes.NewReader(payload))
+				require.NoError(t, err)
+				compressed, err := io.ReadAll(rc)
+				require.NoError(t, err)
+				require.NoError(t, rc.Close())
+
+				var docs []map[string]interface{}
+				require.NoError(t, enc.Decode(compressed, &docs))
+				require.Len(t, docs, 1000)
+			}
+		})
+	}
+}
+
+func TestEncoder_EncodeStreamAborted(t *testing.T) {
+	enc := newEncoding(GzipEncoding, DefaultCompression)
+	rc, err := enc.EncodeStream(onlyReader{strings.NewReader(strings.Repeat(uploadDocuments, 10000))})
+	require.NoError(t, err)
+
+	buf := make([]byte, 16)
+	_, err = rc.Read(buf)
+	require.NoError(t, err)
+	// closing the reading end stops the compressing goroutine
+	require.NoError(t, rc.Close())
+	_, err = rc.Read(buf)
+	require.ErrorIs(t, err, io.ErrClosedPipe)
+}
@@ -0,0 +1,304 @@
+package meilisearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultWaitMinInterval = 50 * time.Millisecond
+	defaultWaitMaxInterval = time.Second
+	defaultWaitMultiplier  = 2
+)
+
+// WaitOptions configures how WaitForTaskWithOptions polls a task. The zero value polls from
+// every 50ms up to every second, with no timeout other than the one of the context.
+type WaitOptions struct {
+	// MinInterval is the delay before the second poll, 50ms when zero
+	MinInterval time.Duration
+
+	// MaxInterval caps the delay between two polls, 1s when zero
+	MaxInterval time.Duration
+
+	// Multiplier grows the delay after every poll, 2 when lower than 1
+	Multiplier float64
+
+	// Timeout bounds the whole wait, which then fails with a TimeoutError, zero waits as long
+	// as the context allows
+	Timeout time.Duration
+
+	// OnStatusChange is called with the polled task each time its status changes, starting
+	// with the status of the first poll. WaitForTasks only reports the final status of a task.
+	OnStatusChange func(task *Task)
+
+	// FailOnError makes the wait return an error along with the task when it ends failed,
+	// see Task.Err, or canceled, with the TaskCanceled ErrCode. WaitForTasks always does.
+	FailOnError bool
+}
+
+// withDefaults returns a copy of the options with the unset fields defaulted, o may be nil.
+func (o *WaitOptions) withDefaults() WaitOptions {
+	var w WaitOptions
+	if o != nil {
+		w = *o
+	}
+	if w.MinInterval <= 0 {
+		w.MinInterval = defaultWaitMinInterval
+	}
+	if w.MaxInterval <= 0 {
+		w.MaxInterval = defaultWaitMaxInterval
+	}
+	if w.MaxInterval < w.MinInterval {
+		w.MaxInterval = w.MinInterval
+	}
+	if w.Multiplier < 1 {
+		w.Multiplier = defaultWaitMultiplier
+	}
+	return w
+}
+
+func waitForTaskWithOptions(ctx context.Context, cli *client, taskUID int64, opts *WaitOptions) (*Task, error) {
+	o := opts.withDefaults()
+
+	waitCtx := ctx
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	var status TaskStatus
+	interval := o.MinInterval
+	for {
+		task, err := getTask(waitCtx, cli, taskUID)
+		if err != nil {
+			if waitTimedOut(ctx, waitCtx) {
+				return nil, waitTimeoutError("WaitForTaskWithOptions", "/tasks/"+strconv.FormatInt(taskUID, 10), waitCtx.Err())
+			}
+			return nil, err
+		}
+
+		if task.Status != status {
+			status = task.Status
+			if o.OnStatusChange != nil {
+				o.OnStatusChange(task)
+			}
+		}
+
+		if task.Status != TaskStatusEnqueued && task.Status != TaskStatusProcessing {
+			if o.FailOnError {
+				return task, taskFinalError(task)
+			}
+			return task, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-waitCtx.Done():
+			timer.Stop()
+			if waitTimedOut(ctx, waitCtx) {
+				return nil, waitTimeoutError("WaitForTaskWithOptions", "/tasks/"+strconv.FormatInt(taskUID, 10), waitCtx.Err())
+			}
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = min(time.Duration(float64(interval)*o.Multiplier), o.MaxInterval)
+	}
+}
+
+// terminalStatuses are the statuses of the tasks Meilisearch is done with.
+var terminalStatuses = []TaskStatus{TaskStatusSucceeded, TaskStatusFailed, TaskStatusCanceled}
+
+func waitForTasks(ctx context.Context, cli *client, taskUIDs []int64, opts *WaitOptions) (map[int64]*Task, error) {
+	o := opts.withDefaults()
+
+	waitCtx := ctx
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	pending := make(map[int64]bool, len(taskUIDs))
+	for _, uid := range taskUIDs {
+		pending[uid] = true
+	}
+	tasks := make(map[int64]*Task, len(pending))
+
+	interval := o.MinInterval
+	for checked := false; len(pending) > 0; checked = true {
+		uids := make([]int64, 0, len(pending))
+		for uid := range pending {
+			uids = append(uids, uid)
+		}
+		sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+
+		// the first lookup lists every task to find the UIDs matching none, the next ones only
+		// the tasks done with, a single page holds them all
+		query := &TasksQuery{UIDS: uids, Limit: int64(len(uids))}
+		if checked {
+			query.Statuses = terminalStatuses
+		}
+		res, err := getTasks(waitCtx, cli, query)
+		if err != nil {
+			if waitTimedOut(ctx, waitCtx) {
+				return tasks, waitTimeoutError("WaitForTasks", "/tasks", waitCtx.Err())
+			}
+			return tasks, err
+		}
+
+		if !checked {
+			found := make(map[int64]bool, len(res.Results))
+			for _, task := range res.Results {
+				found[task.UID] = true
+			}
+			var missing []int64
+			for _, uid := range uids {
+				if !found[uid] {
+					missing = append(missing, uid)
+				}
+			}
+			if len(missing) > 0 {
+				return tasks, taskNotFoundError(missing)
+			}
+		}
+
+		for i := range res.Results {
+			task := &res.Results[i]
+			if !pending[task.UID] || task.Status == TaskStatusEnqueued || task.Status == TaskStatusProcessing {
+				continue
+			}
+			delete(pending, task.UID)
+			tasks[task.UID] = task
+			if o.OnStatusChange != nil {
+				o.OnStatusChange(task)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-waitCtx.Done():
+			timer.Stop()
+			if waitTimedOut(ctx, waitCtx) {
+				return tasks, waitTimeoutError("WaitForTasks", "/tasks", waitCtx.Err())
+			}
+			return tasks, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = min(time.Duration(float64(interval)*o.Multiplier), o.MaxInterval)
+	}
+
+	return tasks, newTasksError(tasks)
+}
+
+// taskNotFoundError is the error of WaitForTasks for the UIDs matching no task, shaped like
+// the task_not_found error Meilisearch answers GetTask with.
+func taskNotFoundError(uids []int64) error {
+	ids := make([]string, len(uids))
+	for i, uid := range uids {
+		ids[i] = strconv.FormatInt(uid, 10)
+	}
+	message := fmt.Sprintf("Task `%s` not found.", ids[0])
+	if len(ids) > 1 {
+		message = fmt.Sprintf("Tasks `%s` not found.", strings.Join(ids, "`, `"))
+	}
+	e := newCallError("WaitForTasks", http.MethodGet, "/tasks")
+	e.StatusCode = http.StatusNotFound
+	e.StatusCodeExpected = []int{http.StatusOK}
+	e.APIError = APIErrorDetails{
+		Message: message,
+		Code:    APIErrCodeTaskNotFound,
+		Type:    "invalid_request",
+		Link:    "https://docs.meilisearch.com/errors#task_not_found",
+	}
+	return e.WithErrCode(APIError)
+}
+
+// TasksError is returned by WaitForTasks when some of the tasks failed or were canceled. It
+// unwraps to the error of each of them, so that errors.Is works with the Err sentinels.
+type TasksError struct {
+	// Total is the number of tasks waited for
+	Total int
+
+	// Failed lists the tasks that failed or were canceled, ordered by UID
+	Failed []FailedTask
+}
+
+// FailedTask is a task WaitForTasks found failed or canceled.
+type FailedTask struct {
+	UID    int64
+	Status TaskStatus
+
+	// Error holds the details of the failure, it is empty for a canceled task
+	Error APIErrorDetails
+}
+
+// newTasksError returns the *TasksError of tasks, nil when none of them failed or was canceled.
+func newTasksError(tasks map[int64]*Task) error {
+	e := &TasksError{Total: len(tasks)}
+	for uid, task := range tasks {
+		if task.Status == TaskStatusFailed || task.Status == TaskStatusCanceled {
+			e.Failed = append(e.Failed, FailedTask{UID: uid, Status: task.Status, Error: task.Error})
+		}
+	}
+	if len(e.Failed) == 0 {
+		return nil
+	}
+	sort.Slice(e.Failed, func(i, j int) bool { return e.Failed[i].UID < e.Failed[j].UID })
+	return e
+}
+
+func (e *TasksError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d tasks did not succeed:", len(e.Failed), e.Total)
+	for i, task := range e.Failed {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, " task %d %s", task.UID, task.Status)
+		if task.Error.Code != "" {
+			fmt.Fprintf(&b, " (%s: %s)", task.Error.Code, task.Error.Message)
+		}
+	}
+	return b.String()
+}
+
+// Unwrap returns the error of every failed or canceled task, as Task.Err would.
+func (e *TasksError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, task := range e.Failed {
+		errs = append(errs, taskFinalError(&Task{UID: task.UID, Status: task.Status, Error: task.Error}))
+	}
+	return errs
+}
+
+// waitTimedOut reports whether the wait stopped on its own timeout rather than on ctx.
+func waitTimedOut(ctx, waitCtx context.Context) bool {
+	return ctx.Err() == nil && waitCtx.Err() != nil
+}
+
+func waitTimeoutError(function, endpoint string, err error) error {
+	return newCallError(function, http.MethodGet, endpoint).WithErrCode(TimeoutError, err)
+}
+
+// taskFinalError returns the error of a failed or canceled task, nil otherwise.
+func taskFinalError(task *Task) error {
+	if task.Status != TaskStatusCanceled {
+		return task.Err()
+	}
+	uid := task.UID
+	if uid == 0 {
+		uid = task.TaskUID
+	}
+	return newCallError("", http.MethodGet, "/tasks/"+strconv.FormatInt(uid, 10)).WithErrCode(TaskCanceled)
+}
@@ -0,0 +1,225 @@
+package meilisearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// taskServer answers GET /tasks/1 with the given statuses in turn, the last one repeated.
+func taskServer(t *testing.T, task string, statuses ...TaskStatus) (*httptest.Server, *int32) {
+	var polls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/tasks/1", r.URL.Path)
+		n := int(atomic.AddInt32(&polls, 1))
+		status := statuses[min(n, len(statuses))-1]
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"uid":1,"status":"` + string(status) + `"` + task + `}`))
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &polls
+}
+
+func TestWaitForTaskWithOptions(t *testing.T) {
+	ts, polls := taskServer(t, "", TaskStatusEnqueued, TaskStatusEnqueued, TaskStatusProcessing,
+		TaskStatusProcessing, TaskStatusSucceeded)
+
+	var seen []TaskStatus
+	start := time.Now()
+	task, err := New(ts.URL).WaitForTaskWithOptions(context.Background(), 1, &WaitOptions{
+		MinInterval: 5 * time.Millisecond,
+		MaxInterval: 20 * time.Millisecond,
+		OnStatusChange: func(task *Task) {
+			seen = append(seen, task.Status)
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusSucceeded, task.Status)
+	require.Equal(t, []TaskStatus{TaskStatusEnqueued, TaskStatusProcessing, TaskStatusSucceeded}, seen)
+	require.Equal(t, int32(5), atomic.LoadInt32(polls))
+	require.GreaterOrEqual(t, time.Since(start), (5+10+20+20)*time.Millisecond, "the interval doubles up to the max")
+}
+
+func TestWaitForTaskWithOptions_Timeout(t *testing.T) {
+	ts, _ := taskServer(t, "", TaskStatusProcessing)
+	sv := New(ts.URL)
+
+	_, err := sv.WaitForTaskWithOptions(context.Background(), 1, &WaitOptions{
+		MinInterval: time.Millisecond,
+		Timeout:     30 * time.Millisecond,
+	})
+	var meiliErr *Error
+	require.True(t, errors.As(err, &meiliErr))
+	require.Equal(t, TimeoutError, meiliErr.ErrCode)
+	require.Equal(t, "WaitForTaskWithOptions", meiliErr.Function)
+	require.Equal(t, "/tasks/1", meiliErr.Endpoint)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err = sv.Index("movies").WaitForTaskWithOptions(ctx, 1, &WaitOptions{Timeout: time.Minute})
+	require.ErrorIs(t, err, context.Canceled, "the cancellation of the context is returned as is")
+	require.False(t, errors.As(err, &meiliErr))
+}
+
+func TestWaitForTaskWithOptions_FailOnError(t *testing.T) {
+	ts, _ := taskServer(t, `,"error":{"message":"Index not found","code":"index_not_found","type":"invalid_request","link":""}`,
+		TaskStatusFailed)
+	sv := New(ts.URL)
+
+	task, err := sv.WaitForTaskWithOptions(context.Background(), 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusFailed, task.Status)
+
+	task, err = sv.WaitForTaskWithOptions(context.Background(), 1, &WaitOptions{FailOnError: true})
+	require.Equal(t, TaskStatusFailed, task.Status)
+	require.ErrorIs(t, err, ErrIndexNotFound)
+	var meiliErr *Error
+	require.True(t, errors.As(err, &meiliErr))
+	require.Equal(t, TaskFailed, meiliErr.ErrCode)
+
+	ts, _ = taskServer(t, "", TaskStatusCanceled)
+	task, err = New(ts.URL).WaitForTaskWithOptions(context.Background(), 1, &WaitOptions{FailOnError: true})
+	require.Equal(t, TaskStatusCanceled, task.Status)
+	require.True(t, errors.As(err, &meiliErr))
+	require.Equal(t, TaskCanceled, meiliErr.ErrCode)
+	require.EqualError(t, err, `task canceled (path "GET /tasks/1")`)
+}
+
+func TestWaitOptions_Defaults(t *testing.T) {
+	var opts *WaitOptions
+	require.Equal(t, WaitOptions{
+		MinInterval: 50 * time.Millisecond,
+		MaxInterval: time.Second,
+		Multiplier:  2,
+	}, opts.withDefaults())
+
+	o := (&WaitOptions{MinInterval: 2 * time.Second, Multiplier: 0.5, Timeout: time.Minute}).withDefaults()
+	require.Equal(t, 2*time.Second, o.MaxInterval)
+	require.Equal(t, float64(2), o.Multiplier)
+	require.Equal(t, time.Minute, o.Timeout)
+}
+
+func TestWaitForTasks(t *testing.T) {
+	// task uid -> the poll from which it is done, and how it ends
+	done := map[int64]int{1: 1, 2: 2, 3: 3, 4: 3}
+	final := map[int64]string{
+		1: `"status":"succeeded"`,
+		2: `"status":"failed","error":{"message":"Index not found","code":"index_not_found","type":"invalid_request","link":""}`,
+		3: `"status":"canceled"`,
+		4: `"status":"succeeded"`,
+	}
+	var polls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/tasks", r.URL.Path)
+		uids := strings.Split(r.URL.Query().Get("uids"), ",")
+		require.Equal(t, strconv.Itoa(len(uids)), r.URL.Query().Get("limit"))
+
+		n := int(atomic.AddInt32(&polls, 1))
+		// the first poll lists every task, the next ones only the tasks done with
+		if n == 1 {
+			require.Empty(t, r.URL.Query().Get("statuses"))
+		} else {
+			require.Equal(t, "succeeded,failed,canceled", r.URL.Query().Get("statuses"))
+		}
+		var results []string
+		for _, s := range uids {
+			uid, err := strconv.ParseInt(s, 10, 64)
+			require.NoError(t, err)
+			if n >= done[uid] {
+				results = append(results, `{"uid":`+s+`,`+final[uid]+`}`)
+			} else if n == 1 {
+				results = append(results, `{"uid":`+s+`,"status":"enqueued"}`)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[` + strings.Join(results, ",") + `]}`))
+	}))
+	defer ts.Close()
+
+	var seen []int64
+	tasks, err := New(ts.URL).WaitForTasks(context.Background(), []int64{4, 3, 2, 1, 1}, &WaitOptions{
+		MinInterval: time.Millisecond,
+		OnStatusChange: func(task *Task) {
+			seen = append(seen, task.UID)
+		},
+	})
+	require.Equal(t, int32(3), atomic.LoadInt32(&polls))
+	require.Len(t, tasks, 4)
+	require.Equal(t, TaskStatusSucceeded, tasks[4].Status)
+	require.ElementsMatch(t, []int64{1, 2, 3, 4}, seen)
+
+	var tasksErr *TasksError
+	require.True(t, errors.As(err, &tasksErr))
+	require.Equal(t, 4, tasksErr.Total)
+	require.Equal(t, []FailedTask{
+		{UID: 2, Status: TaskStatusFailed, Error: APIErrorDetails{
+			Message: "Index not found", Code: APIErrCodeIndexNotFound, Type: "invalid_request",
+		}},
+		{UID: 3, Status: TaskStatusCanceled},
+	}, tasksErr.Failed)
+	require.EqualError(t, err, "2 of 4 tasks did not succeed: task 2 failed (index_not_found: Index not found), task 3 canceled")
+	require.ErrorIs(t, err, ErrIndexNotFound)
+
+	var meiliErr *Error
+	require.True(t, errors.As(err, &meiliErr))
+	require.Equal(t, TaskFailed, meiliErr.ErrCode)
+}
+
+func TestWaitForTasks_Timeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("statuses") == "" {
+			_, _ = w.Write([]byte(`{"results":[{"uid":1,"status":"succeeded"},{"uid":2,"status":"processing"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[{"uid":1,"status":"succeeded"}]}`))
+	}))
+	defer ts.Close()
+
+	tasks, err := New(ts.URL).Index("movies").WaitForTasks(context.Background(), []int64{1, 2}, &WaitOptions{
+		MinInterval: time.Millisecond,
+		Timeout:     30 * time.Millisecond,
+	})
+	require.Len(t, tasks, 1, "the tasks completed so far are returned")
+	require.Equal(t, TaskStatusSucceeded, tasks[1].Status)
+	var meiliErr *Error
+	require.True(t, errors.As(err, &meiliErr))
+	require.Equal(t, TimeoutError, meiliErr.ErrCode)
+	require.Equal(t, "WaitForTasks", meiliErr.Function)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	tasks, err = New(ts.URL).WaitForTasks(context.Background(), nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+}
+
+func TestWaitForTasks_NotFound(t *testing.T) {
+	var polls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&polls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[{"uid":1,"status":"succeeded"},{"uid":2,"status":"enqueued"}]}`))
+	}))
+	defer ts.Close()
+
+	tasks, err := New(ts.URL).WaitForTasks(context.Background(), []int64{1, 2, 7, 9}, &WaitOptions{
+		MinInterval: time.Millisecond,
+	})
+	require.Empty(t, tasks)
+	require.Equal(t, int32(1), atomic.LoadInt32(&polls), "the missing UIDs are found by the first poll")
+	require.ErrorIs(t, err, ErrTaskNotFound)
+
+	var meiliErr *Error
+	require.True(t, errors.As(err, &meiliErr))
+	require.Equal(t, http.StatusNotFound, meiliErr.StatusCode)
+	require.Equal(t, "Tasks `7`, `9` not found.", meiliErr.APIError.Message)
+}